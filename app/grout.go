@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"grout/cfw"
 	"grout/update"
 	"os"
@@ -10,6 +11,11 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "detect-cfw" {
+		detectCFW()
+		return
+	}
+
 	defer cleanup()
 
 	result := setup()
@@ -28,6 +34,14 @@ func main() {
 	}
 }
 
+// detectCFW prints which CFW fingerprints match on this device, for diagnosing launches.
+func detectCFW() {
+	if env := os.Getenv("CFW"); env != "" {
+		fmt.Printf("CFW environment variable: %s\n", env)
+	}
+	cfw.DetectCFW().WriteReport(os.Stdout)
+}
+
 func cleanup() {
 	if err := os.RemoveAll(".tmp"); err != nil {
 		gaba.GetLogger().Error("Failed to clean .tmp directory", "error", err)
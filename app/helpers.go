@@ -4,6 +4,8 @@ import (
 	"grout/cache"
 	"grout/cfw"
 	"grout/internal"
+	"grout/internal/romset"
+	"grout/offline"
 	"grout/romm"
 	"grout/ui"
 
@@ -34,7 +36,7 @@ func savePlatformOrder(state *AppState, platforms []romm.Platform) {
 	internal.SaveConfig(state.Config)
 }
 
-func executeDownloadUI(state *AppState, r ui.GameDetailsOutput, stack *router.Stack) {
+func executeDownloadUI(state *AppState, platform romm.Platform, game romm.Rom, selection ui.FileSelection, stack *router.Stack) {
 	entry := stack.Peek()
 	var allGames []romm.Rom
 	var searchFilter string
@@ -45,13 +47,36 @@ func executeDownloadUI(state *AppState, r ui.GameDetailsOutput, stack *router.St
 		}
 	}
 
+	if offline.IsOffline() {
+		queueDownload(platform, []romm.Rom{game}, selection)
+		return
+	}
+
 	downloadScreen := ui.NewDownloadScreen()
-	downloadScreen.Execute(*state.Config, state.Host, r.Platform, []romm.Rom{r.Game}, allGames, searchFilter, r.SelectedFileID)
+	downloadScreen.Execute(*state.Config, state.Host, platform, []romm.Rom{game}, allGames, searchFilter, selection)
 }
 
 func executeMultiDownloadUI(state *AppState, r ui.GameListOutput) {
+	games := dedupeRomSets(state.Config, r.SelectedGames)
+
+	if offline.IsOffline() {
+		queueDownload(r.Platform, games, ui.FileSelection{})
+		return
+	}
+
 	downloadScreen := ui.NewDownloadScreen()
-	downloadScreen.Execute(*state.Config, state.Host, r.Platform, r.SelectedGames, r.AllGames, r.SearchFilter, 0)
+	downloadScreen.Execute(*state.Config, state.Host, r.Platform, games, r.AllGames, r.SearchFilter, ui.FileSelection{})
+}
+
+// dedupeRomSets keeps only the preferred version of each game when One Game, One ROM
+// is on, so a bulk download never fetches several regions or revisions of a game.
+func dedupeRomSets(config *internal.Config, games []romm.Rom) []romm.Rom {
+	if !config.OneGameOneRom {
+		return games
+	}
+	return romset.Dedupe(games, config.RomSetPreferences(), func(g romm.Rom) bool {
+		return g.IsDownloaded(config)
+	})
 }
 
 func handlePlatformMappingUpdateUI(state *AppState, r ui.PlatformMappingOutput) {
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"grout/live"
+	"grout/offline"
+	"grout/romm"
+	"slices"
+)
+
+// updateLiveListener starts listening for library changes when live updates are on and
+// RomM is reachable, and stops the listener otherwise.
+func updateLiveListener(state *AppState) {
+	state.liveMu.Lock()
+	defer state.liveMu.Unlock()
+
+	if !state.Config.LiveUpdates || offline.IsOffline() {
+		if state.Live != nil {
+			state.Live.Stop()
+			state.Live = nil
+		}
+		return
+	}
+	if state.Live != nil {
+		return
+	}
+
+	state.Live = live.NewListener(state.Host, func(platformIDs []int) {
+		refetchChangedPlatforms(state, platformIDs)
+	})
+	state.Live.Start()
+}
+
+// restartLiveListener reconnects the listener, e.g. after the server address changed.
+func restartLiveListener(state *AppState) {
+	state.liveMu.Lock()
+	if state.Live != nil {
+		state.Live.Stop()
+		state.Live = nil
+	}
+	state.liveMu.Unlock()
+
+	updateLiveListener(state)
+}
+
+// refetchChangedPlatforms refetches the changed platforms this device has mapped;
+// changes to the others don't show anywhere.
+func refetchChangedPlatforms(state *AppState, platformIDs []int) {
+	var changed []romm.Platform
+	for _, platform := range state.Platforms {
+		if slices.Contains(platformIDs, platform.ID) {
+			changed = append(changed, platform)
+		}
+	}
+	if len(changed) > 0 {
+		state.CacheSync.RefreshPlatforms(changed)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"grout/offline"
+	"grout/romm"
+	"grout/ui"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/router"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// blockedOffline tells the user an action needs RomM and returns true when the session
+// is offline. Callers return to the screen they came from.
+func blockedOffline() bool {
+	if !offline.IsOffline() {
+		return false
+	}
+	showOfflineMessage(i18n.Localize(&goi18n.Message{ID: "offline_not_available", Other: "This needs a connection to RomM.\nGrout will reconnect on its own once RomM is reachable."}, nil))
+	return true
+}
+
+// queueDownload saves a download for when RomM is reachable again.
+func queueDownload(platform romm.Platform, games []romm.Rom, selection ui.FileSelection) {
+	err := offline.Enqueue(offline.Action{
+		Kind:       offline.ActionDownload,
+		Platform:   platform,
+		Games:      games,
+		FileID:     selection.FileID,
+		PatchID:    selection.PatchID,
+		Extras:     selection.Extras,
+		ExtrasOnly: selection.ExtrasOnly,
+	})
+	if err != nil {
+		gaba.GetLogger().Error("Failed to queue offline download", "error", err)
+		blockedOffline()
+		return
+	}
+
+	showOfflineMessage(i18n.Localize(&goi18n.Message{
+		ID:    "offline_download_queued",
+		Other: "You're offline.\nQueued {{.Count}} download(s) for when RomM is reachable.",
+	}, map[string]interface{}{"Count": len(games)}))
+}
+
+// queueSaveSync saves a save sync for when RomM is reachable again.
+func queueSaveSync() {
+	if err := offline.Enqueue(offline.Action{Kind: offline.ActionSaveSync}); err != nil {
+		gaba.GetLogger().Error("Failed to queue offline save sync", "error", err)
+		blockedOffline()
+		return
+	}
+	showOfflineMessage(i18n.Localize(&goi18n.Message{ID: "offline_save_sync_queued", Other: "You're offline.\nSaves will sync once RomM is reachable."}, nil))
+}
+
+func showOfflineMessage(message string) {
+	gaba.ConfirmationMessage(message, []gaba.FooterHelpItem{
+		{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_continue", Other: "Continue"}, nil)},
+	}, gaba.MessageOptions{})
+}
+
+// runOfflineQueue offers to run what was queued while offline, the first time the app
+// moves between screens after RomM became reachable. Downloads run in place; a queued
+// save sync is shown before continuing to next.
+func runOfflineQueue(state *AppState, stack *router.Stack, next router.Screen, input any) (router.Screen, any) {
+	if offline.IsOffline() || next == router.ScreenExit {
+		return next, input
+	}
+
+	actions := offline.Pending()
+	if len(actions) == 0 {
+		return next, input
+	}
+
+	logger := gaba.GetLogger()
+	message := i18n.Localize(&goi18n.Message{
+		ID:    "offline_queue_prompt",
+		Other: "Back online!\nRun the {{.Count}} queued action(s) now?",
+	}, map[string]interface{}{"Count": len(actions)})
+
+	result, err := gaba.ConfirmationMessage(message, []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "offline_queue_discard", Other: "Discard"}, nil)},
+		{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "offline_queue_run", Other: "Run"}, nil)},
+	}, gaba.MessageOptions{})
+
+	if clearErr := offline.Clear(); clearErr != nil {
+		logger.Error("Failed to clear offline queue", "error", clearErr)
+	}
+	if err != nil || result == nil || !result.Confirmed {
+		logger.Info("Discarded offline queue", "actions", len(actions))
+		return next, input
+	}
+
+	syncQueued := false
+	for _, action := range actions {
+		switch action.Kind {
+		case offline.ActionDownload:
+			ui.NewDownloadScreen().Execute(*state.Config, state.Host, action.Platform, action.Games, nil, "", ui.FileSelection{
+				FileID:     action.FileID,
+				PatchID:    action.PatchID,
+				Extras:     action.Extras,
+				ExtrasOnly: action.ExtrasOnly,
+			})
+		case offline.ActionSaveSync:
+			syncQueued = true
+		}
+	}
+
+	if !syncQueued || state.Host.DeviceID == "" || next == ScreenSaveSync {
+		return next, input
+	}
+
+	stack.Push(next, input, nil)
+	return ScreenSaveSync, ui.SaveSyncInput{
+		Config: state.Config,
+		Host:   state.Host,
+	}
+}
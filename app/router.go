@@ -4,7 +4,10 @@ import (
 	"grout/cache"
 	"grout/cfw"
 	"grout/internal"
+	"grout/internal/gamelist"
+	"grout/offline"
 	"grout/romm"
+	"grout/sync"
 	"grout/ui"
 	"grout/update"
 
@@ -24,12 +27,14 @@ func runWithRouter(config *internal.Config, currentCFW cfw.CFW, platforms []romm
 	}
 	currentAppState = state
 
-	go func() {
-		client := romm.NewClientFromHost(state.Host)
-		if heartbeat, err := client.GetHeartbeat(); err == nil {
-			state.RommVersion.Store(heartbeat.System.Version)
-		}
-	}()
+	if !offline.IsOffline() {
+		go func() {
+			client := romm.NewClientFromHost(state.Host)
+			if heartbeat, err := client.GetHeartbeat(); err == nil {
+				state.RommVersion.Store(heartbeat.System.Version)
+			}
+		}()
+	}
 
 	r := buildRouter(state, quitOnBack, showCollections)
 
@@ -47,9 +52,26 @@ func buildRouter(state *AppState, quitOnBack bool, showCollections bool) *router
 	r := router.New()
 
 	state.CacheSync = cache.NewBackgroundSync(state.Platforms)
+	state.CacheSync.SetRefreshInterval(state.Config.GetRefreshInterval())
+	state.CacheSync.SetSaveStatusCheck(func() (int, error) {
+		client := romm.NewClientFromHost(state.Host, state.Config.ApiTimeout.Duration())
+		return sync.SavesNewerOnServer(client, state.Config, state.Host.DeviceID)
+	})
 	ui.AddStatusBarIcon(state.CacheSync.Icon())
+	ui.AddStatusBarIcon(state.CacheSync.SaveStatusIcon())
+
+	state.Offline = offline.NewMonitor(state.Host, func(heartbeat romm.HeartbeatResponse) {
+		state.RommVersion.Store(heartbeat.System.Version)
+		state.CacheSync.Start()
+		updateLiveListener(state)
+	})
+	ui.AddStatusBarIcon(state.Offline.Icon())
 
-	if cm := cache.GetCacheManager(); cm != nil && cm.IsFirstRun() {
+	if offline.IsOffline() {
+		// Nothing can be refreshed until RomM answers; the monitor starts the sync then.
+		state.CacheSync.SetSynced()
+		state.Offline.Start()
+	} else if cm := cache.GetCacheManager(); cm != nil && cm.IsFirstRun() {
 		progress := uatomic.NewFloat64(0)
 		gaba.ProcessMessage(
 			i18n.Localize(&goi18n.Message{ID: "cache_building", Other: "Building cache..."}, nil),
@@ -64,11 +86,18 @@ func buildRouter(state *AppState, quitOnBack bool, showCollections bool) *router
 			},
 		)
 		state.CacheSync.SetSynced()
+		state.CacheSync.StartScheduled()
 	} else {
 		state.CacheSync.Start()
 	}
+	updateLiveListener(state)
 
 	cache.RunArtworkValidation()
+	// Only gamelist.xml records play history. Onion's miyoogamelist.xml carries names and
+	// art alone; Onion keeps play activity and favourites in its own database.
+	if name, ok := state.CFW.GamelistFileName(); ok && name == gamelist.GameListFileName {
+		cache.ImportPlayHistory(state.Platforms)
+	}
 
 	registerScreens(r, state)
 	r.OnTransition(buildTransitionFunc(state, quitOnBack, showCollections))
@@ -80,11 +109,14 @@ func registerScreens(r *router.Router, state *AppState) {
 	r.Register(ScreenPlatformSelection, func(input any) (any, error) {
 		in := input.(ui.PlatformSelectionInput)
 
-		state.autoUpdateOnce.Do(func() {
-			state.AutoUpdate = update.NewAutoUpdate(state.CFW, state.Config.ReleaseChannel, &state.Host)
-			ui.AddStatusBarIcon(state.AutoUpdate.Icon())
-			state.AutoUpdate.Start()
-		})
+		// Checked again on the next visit once back online.
+		if !offline.IsOffline() {
+			state.autoUpdateOnce.Do(func() {
+				state.AutoUpdate = update.NewAutoUpdate(state.CFW, state.Config.ReleaseChannel, &state.Host)
+				ui.AddStatusBarIcon(state.AutoUpdate.Icon())
+				state.AutoUpdate.Start()
+			})
+		}
 
 		screen := ui.NewPlatformSelectionScreen()
 		return screen.Draw(in)
@@ -135,6 +167,16 @@ func registerScreens(r *router.Router, state *AppState) {
 		return screen.Draw(input.(ui.GeneralSettingsInput))
 	})
 
+	r.Register(ScreenGameFiles, func(input any) (any, error) {
+		screen := ui.NewGameFilesScreen()
+		return screen.Draw(input.(ui.GameFilesInput))
+	})
+
+	r.Register(ScreenRomPriority, func(input any) (any, error) {
+		screen := ui.NewRomPriorityScreen()
+		return screen.Draw(input.(ui.RomPriorityInput))
+	})
+
 	r.Register(ScreenCollectionsSettings, func(input any) (any, error) {
 		screen := ui.NewCollectionsSettingsScreen()
 		return screen.Draw(input.(ui.CollectionsSettingsInput))
@@ -181,6 +223,26 @@ func registerScreens(r *router.Router, state *AppState) {
 		return ui.ArtworkSyncOutput{}, nil
 	})
 
+	r.Register(ScreenArtOptimize, func(input any) (any, error) {
+		screen := ui.NewArtOptimizeScreen()
+		return screen.Execute(input.(ui.ArtOptimizeInput)), nil
+	})
+
+	r.Register(ScreenGamelistCleanup, func(input any) (any, error) {
+		screen := ui.NewGamelistCleanupScreen()
+		return screen.Execute(input.(ui.GamelistCleanupInput)), nil
+	})
+
+	r.Register(ScreenBIOSFetchAll, func(input any) (any, error) {
+		screen := ui.NewBIOSFetchAllScreen()
+		return screen.Execute(input.(ui.BIOSFetchAllInput)), nil
+	})
+
+	r.Register(ScreenDiagnosticsExport, func(input any) (any, error) {
+		screen := ui.NewDiagnosticsExportScreen()
+		return screen.Execute(input.(ui.DiagnosticsExportInput)), nil
+	})
+
 	r.Register(ScreenUpdateCheck, func(input any) (any, error) {
 		screen := ui.NewUpdateScreen()
 		return screen.Draw(input.(ui.UpdateInput))
@@ -38,4 +38,10 @@ const (
 	ScreenServerAddress
 	ScreenToolsSettings
 	ScreenInputMapping
+	ScreenRomPriority
+	ScreenGameFiles
+	ScreenArtOptimize
+	ScreenGamelistCleanup
+	ScreenBIOSFetchAll
+	ScreenDiagnosticsExport
 )
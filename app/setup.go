@@ -4,26 +4,20 @@ import (
 	"errors"
 	"grout/cache"
 	"grout/cfw"
-	"grout/cfw/allium"
-	"grout/cfw/arkos"
-	"grout/cfw/koriki"
-	"grout/cfw/minui"
-	"grout/cfw/muos"
-	"grout/cfw/nextui"
-	"grout/cfw/onion"
-	"grout/cfw/rocknix"
-	"grout/cfw/spruce"
 	"grout/internal"
 	"grout/internal/environment"
 	"grout/internal/fileutil"
+	"grout/offline"
 	"grout/resources"
 	"grout/romm"
 	"grout/sync"
 	"grout/ui"
+	"grout/version"
 	"log"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
@@ -45,8 +39,14 @@ func setup() SetupResult {
 
 	logger := gaba.GetLogger()
 
-	config, isFirstLaunch := loadOrCreateConfig(logger)
-	config = handleFirstLaunch(config, isFirstLaunch, logger)
+	if cfw.IsGuess() {
+		showUndetectedCFWWarning(currentCFW)
+	}
+
+	provisioning := findProvisioning(logger)
+
+	config, isFirstLaunch := loadOrCreateConfig(provisioning, logger)
+	config = handleFirstLaunch(config, isFirstLaunch, provisioning, logger)
 	config = applyConfig(config, isFirstLaunch, currentCFW, logger)
 
 	if err := cache.InitCacheManager(config.Hosts[0], config); err != nil {
@@ -77,27 +77,7 @@ func setupInputMapping(currentCFW cfw.CFW) {
 		return
 	}
 
-	var mappingBytes []byte
-	var mappingErr error
-	switch currentCFW {
-	case cfw.MuOS:
-		mappingBytes, mappingErr = muos.GetInputMappingBytes()
-	case cfw.Allium:
-		mappingBytes, mappingErr = allium.GetInputMappingBytes()
-	case cfw.Onion:
-		mappingBytes, mappingErr = onion.GetInputMappingBytes()
-	case cfw.Koriki:
-		mappingBytes, mappingErr = koriki.GetInputMappingBytes()
-	case cfw.MinUI:
-		mappingBytes, mappingErr = minui.GetInputMappingBytes()
-	case cfw.Spruce:
-		mappingBytes, mappingErr = spruce.GetInputMappingBytes()
-	case cfw.ROCKNIX:
-		mappingBytes, mappingErr = rocknix.GetInputMappingBytes()
-	case cfw.ArkOS:
-		mappingBytes, mappingErr = arkos.GetInputMappingBytes()
-	}
-
+	mappingBytes, mappingErr := currentCFW.InputMapping()
 	if mappingBytes != nil && mappingErr == nil {
 		gaba.SetInputMappingBytes(mappingBytes)
 	} else if mappingErr != nil {
@@ -106,30 +86,19 @@ func setupInputMapping(currentCFW cfw.CFW) {
 }
 
 func initFramework(currentCFW cfw.CFW) {
+	device := currentCFW.Device()
 	gabaOptions := gaba.Options{
 		WindowTitle:          "Grout",
 		PrimaryThemeColorHex: 0x007C77,
 		ShowBackground:       true,
-		IsNextUI:             currentCFW == cfw.NextUI,
-		DisplayOrientation:   gaba.OrientationNormal,
+		IsNextUI:             currentCFW.UsesNextUITheme(),
+		DisplayOrientation:   displayOrientation(device.Rotation),
 	}
 	if preConfig, err := internal.LoadConfig(); err == nil {
 		gaba.SetFlipFaceButtons(preConfig.SwapFaceButtons)
 	}
-	if currentCFW == cfw.Spruce && spruce.DetectDevice() == spruce.DeviceA30 {
-		gabaOptions.DisplayOrientation = gaba.OrientationRotate270
-	}
 
-	var minuiDevice minui.Device
-	if currentCFW == cfw.MinUI {
-		minuiDevice = minui.DetectDevice()
-		if minuiDevice == minui.DeviceZero28 {
-			gabaOptions.DisplayOrientation = gaba.OrientationRotate90
-		}
-	}
-
-	if (currentCFW == cfw.MinUI && minuiDevice == minui.DeviceMiyooFlip) ||
-		(currentCFW == cfw.NextUI && nextui.DetectDevice() == nextui.DeviceMiyooFlip) {
+	if device.GamepadOnly {
 		gabaOptions.DisabledInputSources = gaba.DisabledInputSources{
 			Keyboard: true,
 			Joystick: true,
@@ -166,19 +135,72 @@ func initFramework(currentCFW cfw.CFW) {
 	cfw.AddGroutToGamelist(currentCFW)
 }
 
-func loadOrCreateConfig(logger *slog.Logger) (*internal.Config, bool) {
+// showUndetectedCFWWarning tells the user which CFW Grout fell back to when it couldn't
+// detect the one it is running on, so wrong folders aren't a silent surprise.
+func showUndetectedCFWWarning(currentCFW cfw.CFW) {
+	message := i18n.Localize(&goi18n.Message{
+		ID:    "startup_warning_cfw_guessed",
+		Other: "Grout couldn't detect your CFW and is using {{.CFW}} folders.\nSet CFW in the launch script if this is wrong.",
+	}, map[string]interface{}{"CFW": string(currentCFW)})
+
+	gaba.ConfirmationMessage(message, []gaba.FooterHelpItem{
+		{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_continue", Other: "Continue"}, nil)},
+	}, gaba.MessageOptions{})
+}
+
+func displayOrientation(rotation int) gaba.DisplayOrientation {
+	switch rotation {
+	case 90:
+		return gaba.OrientationRotate90
+	case 180:
+		return gaba.OrientationRotate180
+	case 270:
+		return gaba.OrientationRotate270
+	default:
+		return gaba.OrientationNormal
+	}
+}
+
+// findProvisioning returns the grout-provision.json waiting on the SD card, if any. It
+// only takes effect on first launch.
+func findProvisioning(logger *slog.Logger) *internal.Provisioning {
+	provisioning, err := internal.FindProvisioning()
+	if err != nil {
+		logger.Error("Ignoring invalid provisioning file", "error", err)
+		return nil
+	}
+	if provisioning != nil {
+		logger.Info("Found provisioning file", "path", provisioning.Path())
+	}
+	return provisioning
+}
+
+func loadOrCreateConfig(provisioning *internal.Provisioning, logger *slog.Logger) (*internal.Config, bool) {
 	config, err := internal.LoadConfig()
 	isFirstLaunch := err != nil || (len(config.Hosts) == 0 && config.Language == "")
 
+	if config != nil {
+		for _, problem := range config.Problems {
+			logger.Warn("Problem in config.json", "field", problem.Field, "value", problem.Value, "problem", problem.Problem)
+		}
+	}
+
 	if isFirstLaunch {
-		logger.Debug("First launch detected, showing language selection")
-		languageScreen := ui.NewLanguageSelectionScreen()
-		selectedLanguage, langErr := languageScreen.Draw()
-		if langErr != nil {
-			logger.Error("Language selection failed", "error", langErr)
-			selectedLanguage = "en"
+		var selectedLanguage string
+		if provisioning != nil && provisioning.Language() != "" {
+			selectedLanguage = provisioning.Language()
+			logger.Debug("First launch detected, using provisioned language", "language", selectedLanguage)
+		} else {
+			logger.Debug("First launch detected, showing language selection")
+			languageScreen := ui.NewLanguageSelectionScreen()
+			var langErr error
+			selectedLanguage, langErr = languageScreen.Draw()
+			if langErr != nil {
+				logger.Error("Language selection failed", "error", langErr)
+				selectedLanguage = "en"
+			}
+			logger.Debug("Language selected", "language", selectedLanguage)
 		}
-		logger.Debug("Language selected", "language", selectedLanguage)
 
 		if err := i18n.SetWithCode(selectedLanguage); err != nil {
 			logger.Error("Failed to set language", "error", err, "language", selectedLanguage)
@@ -191,19 +213,41 @@ func loadOrCreateConfig(logger *slog.Logger) (*internal.Config, bool) {
 				DownloadTimeout:        internal.DurationSeconds(60 * time.Minute),
 			}
 		}
+
+		if provisioning != nil {
+			if err := provisioning.Apply(config); err != nil {
+				logger.Error("Failed to apply provisioning settings", "error", err)
+			}
+			for _, problem := range config.Validate(cfw.GetPlatformMap(cfw.GetCFW())) {
+				logger.Warn("Problem in provisioning settings", "field", problem.Field, "value", problem.Value, "problem", problem.Problem)
+			}
+		}
 		config.Language = selectedLanguage
+	} else if provisioning != nil {
+		logger.Info("Grout is already set up, ignoring provisioning file", "path", provisioning.Path())
 	}
 
 	return config, isFirstLaunch
 }
 
-func handleFirstLaunch(config *internal.Config, isFirstLaunch bool, logger *slog.Logger) *internal.Config {
+func handleFirstLaunch(config *internal.Config, isFirstLaunch bool, provisioning *internal.Provisioning, logger *slog.Logger) *internal.Config {
 	if len(config.Hosts) > 0 {
 		return config
 	}
 
+	if isFirstLaunch && provisioning != nil {
+		if provisioned := provisionedLogin(config, provisioning, logger); provisioned {
+			return config
+		}
+	}
+
+	existingHost := romm.Host{}
+	if provisioning != nil {
+		existingHost, _ = provisioning.Host()
+	}
+
 	logger.Debug("No RomM Host Configured, starting login flow")
-	loginConfig, loginErr := ui.LoginFlow(romm.Host{})
+	loginConfig, loginErr := ui.LoginFlow(existingHost)
 	if loginErr != nil {
 		logger.Error("Login flow failed", "error", loginErr)
 		gaba.Close()
@@ -218,6 +262,55 @@ func handleFirstLaunch(config *internal.Config, isFirstLaunch bool, logger *slog
 	return config
 }
 
+// provisionedLogin logs in and registers the device as the provisioning file describes,
+// then marks the file as consumed. It reports false when the regular login flow is
+// needed instead.
+func provisionedLogin(config *internal.Config, provisioning *internal.Provisioning, logger *slog.Logger) bool {
+	host, err := provisioning.Host()
+	if err != nil {
+		logger.Error("Invalid provisioning host", "error", err)
+		return false
+	}
+	host.ClientDeviceID = romm.NewClientDeviceID()
+	deviceName := provisioning.ExpandDeviceName(host.ClientDeviceID)
+
+	logger.Debug("Logging in from provisioning file", "host", host.URL(), "deviceName", deviceName)
+	loginConfig, err := ui.ProvisionedLogin(host, provisioning.PairingCode, deviceName)
+	if err != nil {
+		logger.Warn("Provisioned login failed, falling back to manual login", "error", err)
+		return false
+	}
+	config.Hosts = loginConfig.Hosts
+	config.PlatformsBinding = loginConfig.PlatformsBinding
+
+	if provisioning.RegisterDevice && config.Hosts[0].DeviceID == "" {
+		registerProvisionedDevice(config, deviceName, logger)
+	}
+
+	internal.SaveConfig(config)
+
+	if err := provisioning.MarkConsumed(); err != nil {
+		logger.Error("Failed to mark provisioning file as consumed", "error", err)
+	}
+	return true
+}
+
+func registerProvisionedDevice(config *internal.Config, deviceName string, logger *slog.Logger) {
+	host := config.Hosts[0]
+	client := romm.NewClientFromHost(host, config.ApiTimeout.Duration())
+
+	device, err := sync.RegisterDevice(client, deviceName)
+	if err != nil {
+		logger.Error("Failed to register provisioned device", "error", err)
+		return
+	}
+
+	host.DeviceID = device.ID
+	host.DeviceName = deviceName
+	host.DeviceClientVersion = version.Get().Version
+	config.Hosts[0] = host
+}
+
 func applyConfig(config *internal.Config, isFirstLaunch bool, currentCFW cfw.CFW, logger *slog.Logger) *internal.Config {
 	if config.LogLevel != "" {
 		gaba.SetRawLogLevel(string(config.LogLevel))
@@ -229,6 +322,10 @@ func applyConfig(config *internal.Config, isFirstLaunch bool, currentCFW cfw.CFW
 		}
 	}
 
+	if len(config.Problems) > 0 {
+		showConfigProblems(config, logger)
+	}
+
 	internal.InitKidMode(config)
 	gaba.SetFlipFaceButtons(config.SwapFaceButtons)
 
@@ -275,6 +372,45 @@ func applyConfig(config *internal.Config, isFirstLaunch bool, currentCFW cfw.CFW
 	return config
 }
 
+// maxShownConfigProblems keeps the config problems message on one screen.
+const maxShownConfigProblems = 5
+
+// showConfigProblems lists the values in config.json Grout can't use. Defaults swapped in
+// for broken values are only written to config.json if the user agrees.
+func showConfigProblems(config *internal.Config, logger *slog.Logger) {
+	lines := make([]string, 0, maxShownConfigProblems+1)
+	for i, problem := range config.Problems {
+		if i == maxShownConfigProblems {
+			lines = append(lines, i18n.Localize(&goi18n.Message{ID: "config_problems_more", Other: "...and {{.Count}} more, see the log"}, map[string]interface{}{"Count": len(config.Problems) - i}))
+			break
+		}
+		lines = append(lines, problem.String())
+	}
+	message := i18n.Localize(&goi18n.Message{ID: "config_problems_title", Other: "Some settings in config.json can't be used:"}, nil) + "\n" + strings.Join(lines, "\n")
+
+	if !config.HasResets() {
+		gaba.ConfirmationMessage(message, []gaba.FooterHelpItem{
+			{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_continue", Other: "Continue"}, nil)},
+		}, gaba.MessageOptions{})
+		return
+	}
+
+	message += "\n" + i18n.Localize(&goi18n.Message{ID: "config_problems_reset_prompt", Other: "Defaults are used for now. Save them to config.json?"}, nil)
+	result, err := gaba.ConfirmationMessage(message, []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "config_problems_keep", Other: "Keep File"}, nil)},
+		{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "config_problems_save", Other: "Save Defaults"}, nil)},
+	}, gaba.MessageOptions{})
+	if err != nil || result == nil || !result.Confirmed {
+		logger.Info("Keeping config.json as written, using defaults for this session")
+		return
+	}
+
+	config.AcceptResets()
+	if err := internal.SaveConfig(config); err != nil {
+		logger.Error("Failed to save config defaults", "error", err)
+	}
+}
+
 func connectAndLoadPlatforms(config *internal.Config, logger *slog.Logger) []romm.Platform {
 	var platforms []romm.Platform
 	splashBytes, _ := resources.GetSplashImageBytes()
@@ -342,10 +478,21 @@ func connectAndLoadPlatforms(config *internal.Config, logger *slog.Logger) []rom
 
 		if connErr != nil {
 			logger.Warn("Server connectivity failed", "error", connErr)
-			errorMessage := classifyStartupError(connErr)
-			if !showStartupError(i18n.Localize(errorMessage, nil)) {
-				gaba.Close()
-				os.Exit(1)
+			errorMessage := i18n.Localize(classifyStartupError(connErr), nil)
+
+			cached := loadCachedPlatforms(config, logger)
+			if len(cached) == 0 {
+				if !showStartupError(errorMessage) {
+					gaba.Close()
+					os.Exit(1)
+				}
+				continue
+			}
+
+			if !showOfflineStartupError(errorMessage) {
+				logger.Info("Starting offline from the cache", "platforms", len(cached))
+				offline.SetOffline(true)
+				return cached
 			}
 			continue
 		}
@@ -425,6 +572,39 @@ func classifyStartupError(err error) *goi18n.Message {
 	}
 }
 
+// loadCachedPlatforms returns the mapped platforms from the cache, without touching the
+// network, so Grout can start offline.
+func loadCachedPlatforms(config *internal.Config, logger *slog.Logger) []romm.Platform {
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return nil
+	}
+	if cached, err := cm.GetPlatforms(); err != nil || len(cached) == 0 {
+		return nil
+	}
+
+	platforms, err := internal.GetMappedPlatforms(config.Hosts[0], config.DirectoryMappings)
+	if err != nil {
+		logger.Debug("Failed to load cached platforms", "error", err)
+		return nil
+	}
+	return internal.SortPlatformsByOrder(platforms, config.PlatformOrder)
+}
+
+// showOfflineStartupError is showStartupError for when the cache can be browsed instead.
+// It returns true to retry and false to start offline.
+func showOfflineStartupError(errorMsg string) bool {
+	message := errorMsg + "\n" + i18n.Localize(&goi18n.Message{ID: "startup_error_offline_available", Other: "Your cached library can still be browsed offline."}, nil)
+	footerItems := []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "startup_error_action_offline", Other: "Browse Offline"}, nil)},
+		{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "startup_error_action_retry", Other: "Retry Connection"}, nil)},
+	}
+
+	result, err := gaba.ConfirmationMessage(message, footerItems, gaba.MessageOptions{})
+
+	return err == nil && result != nil && result.Confirmed
+}
+
 func showStartupError(errorMsg string) bool {
 	footerItems := []gaba.FooterHelpItem{
 		{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "startup_error_action_exit", Other: "Exit"}, nil)},
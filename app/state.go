@@ -4,6 +4,8 @@ import (
 	"grout/cache"
 	"grout/cfw"
 	"grout/internal"
+	"grout/live"
+	"grout/offline"
 	"grout/romm"
 	"grout/update"
 	gosync "sync"
@@ -22,6 +24,9 @@ type AppState struct {
 
 	AutoUpdate *update.AutoUpdate
 	CacheSync  *cache.BackgroundSync
+	Offline    *offline.Monitor
+	Live       *live.Listener
 
 	autoUpdateOnce gosync.Once
+	liveMu         gosync.Mutex
 }
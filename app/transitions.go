@@ -4,6 +4,8 @@ import (
 	"grout/cache"
 	"grout/cfw"
 	"grout/internal"
+	"grout/internal/romset"
+	"grout/offline"
 	"grout/romm"
 	"grout/sync"
 	"grout/ui"
@@ -33,71 +35,88 @@ func buildTransitionFunc(state *AppState, quitOnBack bool, initialShowCollection
 		}
 		defer func() { showCollections = ctx.showCollections }()
 
-		switch from {
-		case ScreenPlatformSelection:
-			return transitionPlatformSelection(ctx, result)
-		case ScreenGameList:
-			return transitionGameList(ctx, result)
-		case ScreenSearch:
-			return transitionSearch(ctx, result)
-		case ScreenGameDetails:
-			return transitionGameDetails(ctx, result)
-		case ScreenGameOptions:
-			return transitionGameOptions(ctx, result)
-		case ScreenGameQR:
-			return popOrExit(stack)
-		case ScreenCollectionList:
-			return transitionCollectionList(ctx, result)
-		case ScreenCollectionPlatformSelection:
-			return transitionCollectionPlatformSelection(ctx, result)
-		case ScreenSettings:
-			return transitionSettings(ctx, result)
-		case ScreenGeneralSettings:
-			return transitionGeneralSettings(ctx, result)
-		case ScreenCollectionsSettings:
-			return transitionCollectionsSettings(ctx, result)
-		case ScreenToolsSettings:
-			return transitionToolsSettings(ctx, result)
-		case ScreenAdvancedSettings:
-			return transitionAdvancedSettings(ctx, result)
-		case ScreenPlatformMapping:
-			return transitionPlatformMapping(ctx, result)
-		case ScreenInfo:
-			return transitionInfo(ctx, result)
-		case ScreenLogoutConfirmation:
-			return transitionLogoutConfirmation(ctx, result)
-		case ScreenRebuildCache:
-			return transitionRebuildCache(ctx, result)
-		case ScreenBIOSDownload:
-			return popOrExit(stack)
-		case ScreenArtworkSync:
-			return popOrExit(stack)
-		case ScreenUpdateCheck:
-			return transitionUpdateCheck(ctx, result)
-		case ScreenGameFilters:
-			return transitionGameFilters(ctx, result)
-		case ScreenSaveSync:
-			return transitionSaveSync(ctx, result)
-		case ScreenSaveConflict:
-			return transitionSaveConflict(ctx, result)
-		case ScreenSyncMenu:
-			return transitionSyncMenu(ctx, result)
-		case ScreenSyncedGames:
-			return transitionSyncedGames(ctx, result)
-		case ScreenSyncHistory:
-			return popOrExit(stack)
-		case ScreenSaveSyncSettings:
-			return transitionSaveSyncSettings(ctx, result)
-		case ScreenSaveMapping:
-			return transitionSaveMapping(ctx, result)
-		case ScreenServerAddress:
-			return transitionServerAddress(ctx, result)
-		case ScreenInputMapping:
-			return popOrExit(stack)
-		}
+		next, input := transition(ctx, from, result)
+		return runOfflineQueue(state, stack, next, input)
+	}
+}
 
-		return router.ScreenExit, nil
+func transition(ctx *transitionContext, from router.Screen, result any) (router.Screen, any) {
+	switch from {
+	case ScreenPlatformSelection:
+		return transitionPlatformSelection(ctx, result)
+	case ScreenGameList:
+		return transitionGameList(ctx, result)
+	case ScreenSearch:
+		return transitionSearch(ctx, result)
+	case ScreenGameDetails:
+		return transitionGameDetails(ctx, result)
+	case ScreenGameOptions:
+		return transitionGameOptions(ctx, result)
+	case ScreenGameQR:
+		return popOrExit(ctx.stack)
+	case ScreenCollectionList:
+		return transitionCollectionList(ctx, result)
+	case ScreenCollectionPlatformSelection:
+		return transitionCollectionPlatformSelection(ctx, result)
+	case ScreenSettings:
+		return transitionSettings(ctx, result)
+	case ScreenGeneralSettings:
+		return transitionGeneralSettings(ctx, result)
+	case ScreenRomPriority:
+		return transitionRomPriority(ctx, result)
+	case ScreenGameFiles:
+		return transitionGameFiles(ctx, result)
+	case ScreenCollectionsSettings:
+		return transitionCollectionsSettings(ctx, result)
+	case ScreenToolsSettings:
+		return transitionToolsSettings(ctx, result)
+	case ScreenAdvancedSettings:
+		return transitionAdvancedSettings(ctx, result)
+	case ScreenPlatformMapping:
+		return transitionPlatformMapping(ctx, result)
+	case ScreenInfo:
+		return transitionInfo(ctx, result)
+	case ScreenLogoutConfirmation:
+		return transitionLogoutConfirmation(ctx, result)
+	case ScreenRebuildCache:
+		return transitionRebuildCache(ctx, result)
+	case ScreenBIOSDownload:
+		return popOrExit(ctx.stack)
+	case ScreenArtworkSync:
+		return popOrExit(ctx.stack)
+	case ScreenArtOptimize:
+		return popOrExit(ctx.stack)
+	case ScreenGamelistCleanup:
+		return popOrExit(ctx.stack)
+	case ScreenBIOSFetchAll:
+		return popOrExit(ctx.stack)
+	case ScreenDiagnosticsExport:
+		return popOrExit(ctx.stack)
+	case ScreenUpdateCheck:
+		return transitionUpdateCheck(ctx, result)
+	case ScreenGameFilters:
+		return transitionGameFilters(ctx, result)
+	case ScreenSaveSync:
+		return transitionSaveSync(ctx, result)
+	case ScreenSaveConflict:
+		return transitionSaveConflict(ctx, result)
+	case ScreenSyncMenu:
+		return transitionSyncMenu(ctx, result)
+	case ScreenSyncedGames:
+		return transitionSyncedGames(ctx, result)
+	case ScreenSyncHistory:
+		return popOrExit(ctx.stack)
+	case ScreenSaveSyncSettings:
+		return transitionSaveSyncSettings(ctx, result)
+	case ScreenSaveMapping:
+		return transitionSaveMapping(ctx, result)
+	case ScreenServerAddress:
+		return transitionServerAddress(ctx, result)
+	case ScreenInputMapping:
+		return popOrExit(ctx.stack)
 	}
+
+	return router.ScreenExit, nil
 }
 
 func transitionPlatformSelection(ctx *transitionContext, result any) (router.Screen, any) {
@@ -130,6 +149,19 @@ func transitionPlatformSelection(ctx *transitionContext, result any) (router.Scr
 			Host:   ctx.state.Host,
 		}
 
+	case ui.PlatformSelectionActionPlayHistory:
+		ctx.stack.Push(ScreenPlatformSelection, pushInput, r)
+		cache.RefreshPlayHistory(ctx.state.Platforms)
+		input := ui.GameListInput{
+			Config:     ctx.state.Config,
+			Host:       ctx.state.Host,
+			Collection: r.SelectedCollection,
+		}
+		if r.SelectedCollection.VirtualID == cache.RecentlyPlayedCollectionID {
+			input.GameFilter.Sort = cache.SortByLastPlayed
+		}
+		return ScreenGameList, input
+
 	case ui.PlatformSelectionActionSettings:
 		ctx.stack.Push(ScreenPlatformSelection, pushInput, r)
 		return ScreenSettings, ui.SettingsInput{
@@ -162,6 +194,10 @@ func transitionSyncMenu(ctx *transitionContext, result any) (router.Screen, any)
 
 	switch r.Action {
 	case ui.SyncMenuActionSyncNow:
+		if offline.IsOffline() {
+			queueSaveSync()
+			return ScreenSyncMenu, pushInput
+		}
 		ctx.stack.Push(ScreenSyncMenu, pushInput, r)
 		return ScreenSaveSync, ui.SaveSyncInput{
 			Config: ctx.state.Config,
@@ -208,14 +244,20 @@ func transitionSyncedGames(ctx *transitionContext, result any) (router.Screen, a
 	}
 
 	if r.Action == ui.SyncedGamesActionSyncNow {
-		// Resume data is nil because SyncedGamesScreen doesn't track scroll position
-		// externally — it manages its own navigation loops internally.
-		ctx.stack.Push(ScreenSyncedGames, ui.SyncedGamesInput{
+		syncedGamesInput := ui.SyncedGamesInput{
 			Config:    ctx.state.Config,
 			Host:      ctx.state.Host,
 			Platforms: &ctx.state.Platforms,
 			DeviceID:  ctx.state.Host.DeviceID,
-		}, nil)
+		}
+		if offline.IsOffline() {
+			queueSaveSync()
+			return ScreenSyncedGames, syncedGamesInput
+		}
+
+		// Resume data is nil because SyncedGamesScreen doesn't track scroll position
+		// externally — it manages its own navigation loops internally.
+		ctx.stack.Push(ScreenSyncedGames, syncedGamesInput, nil)
 		syncInput := ui.SaveSyncInput{
 			Config: ctx.state.Config,
 			Host:   ctx.state.Host,
@@ -272,6 +314,7 @@ func transitionSaveSyncSettings(ctx *transitionContext, result any) (router.Scre
 func transitionSaveSync(ctx *transitionContext, result any) (router.Screen, any) {
 	r := result.(ui.SaveSyncOutput)
 	if !r.NeedsConflictResolution {
+		ctx.state.CacheSync.RefreshSaveStatus()
 		return popOrExit(ctx.stack)
 	}
 
@@ -350,12 +393,21 @@ func transitionGameList(ctx *transitionContext, result any) (router.Screen, any)
 			}
 		}
 
+		var versions []romm.Rom
+		if ctx.state.Config.OneGameOneRom {
+			config := ctx.state.Config
+			versions = romset.Versions(r.AllGames, r.SelectedGames[0], config.RomSetPreferences(), func(g romm.Rom) bool {
+				return g.IsDownloaded(config)
+			})
+		}
+
 		ctx.stack.Push(ScreenGameList, pushInput, r)
 		return ScreenGameDetails, ui.GameDetailsInput{
 			Config:   ctx.state.Config,
 			Host:     ctx.state.Host,
 			Platform: r.Platform,
 			Game:     r.SelectedGames[0],
+			Versions: versions,
 		}
 
 	case ui.GameListActionSearch:
@@ -387,6 +439,11 @@ func transitionGameList(ctx *transitionContext, result any) (router.Screen, any)
 		}
 
 	case ui.GameListActionBIOS:
+		if blockedOffline() {
+			pushInput.LastSelectedIndex = r.LastSelectedIndex
+			pushInput.LastSelectedPosition = r.LastSelectedPosition
+			return ScreenGameList, pushInput
+		}
 		ctx.stack.Push(ScreenGameList, pushInput, r)
 		return ScreenBIOSDownload, ui.BIOSDownloadInput{
 			Config:   *ctx.state.Config,
@@ -473,7 +530,27 @@ func transitionGameDetails(ctx *transitionContext, result any) (router.Screen, a
 
 	switch r.Action {
 	case ui.GameDetailsActionDownload:
-		executeDownloadUI(ctx.state, r, ctx.stack)
+		selection := ui.FileSelection{FileID: r.SelectedFileID, PatchID: r.SelectedPatchID}
+
+		// Let the user pick DLC, updates and other extras before downloading
+		if len(r.Game.ExtraFiles()) > 0 {
+			ctx.stack.Push(ScreenGameDetails, ui.GameDetailsInput{
+				Config:   ctx.state.Config,
+				Host:     ctx.state.Host,
+				Platform: r.Platform,
+				Game:     r.Game,
+				Versions: r.Versions,
+			}, nil)
+			return ScreenGameFiles, ui.GameFilesInput{
+				Config:    ctx.state.Config,
+				Host:      ctx.state.Host,
+				Platform:  r.Platform,
+				Game:      r.Game,
+				Selection: selection,
+			}
+		}
+
+		executeDownloadUI(ctx.state, r.Platform, r.Game, selection, ctx.stack)
 
 		return popOrExit(ctx.stack)
 
@@ -483,6 +560,7 @@ func transitionGameDetails(ctx *transitionContext, result any) (router.Screen, a
 			Host:     ctx.state.Host,
 			Platform: r.Platform,
 			Game:     r.Game,
+			Versions: r.Versions,
 		}, nil)
 		return ScreenGameOptions, ui.GameOptionsInput{
 			Config: ctx.state.Config,
@@ -497,6 +575,18 @@ func transitionGameDetails(ctx *transitionContext, result any) (router.Screen, a
 	return router.ScreenExit, nil
 }
 
+func transitionGameFiles(ctx *transitionContext, result any) (router.Screen, any) {
+	r := result.(ui.GameFilesOutput)
+
+	if r.Action == ui.GameFilesActionDownload {
+		// Drop the game details entry so the download returns to the game list
+		ctx.stack.Pop()
+		executeDownloadUI(ctx.state, r.Platform, r.Game, r.Selection, ctx.stack)
+	}
+
+	return popOrExit(ctx.stack)
+}
+
 func transitionGameOptions(ctx *transitionContext, result any) (router.Screen, any) {
 	r := result.(ui.GameOptionsOutput)
 	if r.Config != nil {
@@ -516,11 +606,16 @@ func transitionGameOptions(ctx *transitionContext, result any) (router.Screen, a
 	}
 
 	if r.Action == ui.GameOptionsActionSyncNow {
-		ctx.stack.Push(ScreenGameOptions, ui.GameOptionsInput{
+		optionsInput := ui.GameOptionsInput{
 			Config: ctx.state.Config,
 			Host:   r.Host,
 			Game:   r.Game,
-		}, nil)
+		}
+		if offline.IsOffline() {
+			queueSaveSync()
+			return ScreenGameOptions, optionsInput
+		}
+		ctx.stack.Push(ScreenGameOptions, optionsInput, nil)
 		syncInput := ui.SaveSyncInput{
 			Config: ctx.state.Config,
 			Host:   r.Host,
@@ -634,6 +729,9 @@ func transitionSettings(ctx *transitionContext, result any) (router.Screen, any)
 		return ScreenAdvancedSettings, ui.AdvancedSettingsInput{Config: ctx.state.Config, Host: ctx.state.Host}
 
 	case ui.SettingsActionPlatformMapping:
+		if blockedOffline() {
+			return ScreenSettings, pushInput
+		}
 		ctx.stack.Push(ScreenSettings, pushInput, r)
 		return ScreenPlatformMapping, ui.PlatformMappingInput{
 			Host:             ctx.state.Host,
@@ -649,6 +747,9 @@ func transitionSettings(ctx *transitionContext, result any) (router.Screen, any)
 		return ScreenInfo, buildInfoInput(ctx.state)
 
 	case ui.SettingsActionCheckUpdate:
+		if blockedOffline() {
+			return ScreenSettings, pushInput
+		}
 		ctx.stack.Push(ScreenSettings, pushInput, r)
 		return ScreenUpdateCheck, ui.UpdateInput{
 			CFW:            ctx.state.CFW,
@@ -676,12 +777,37 @@ func transitionGeneralSettings(ctx *transitionContext, result any) (router.Scree
 	if r.Config != nil {
 		ctx.state.Config = r.Config
 	}
+
+	switch r.Action {
+	case ui.GeneralSettingsActionRegionPriority:
+		ctx.stack.Push(ScreenGeneralSettings, ui.GeneralSettingsInput{Config: ctx.state.Config}, r)
+		return ScreenRomPriority, ui.RomPriorityInput{Config: ctx.state.Config, Kind: ui.RomPriorityRegions}
+
+	case ui.GeneralSettingsActionLanguagePriority:
+		ctx.stack.Push(ScreenGeneralSettings, ui.GeneralSettingsInput{Config: ctx.state.Config}, r)
+		return ScreenRomPriority, ui.RomPriorityInput{Config: ctx.state.Config, Kind: ui.RomPriorityLanguages}
+	}
+
+	return popOrExit(ctx.stack)
+}
+
+func transitionRomPriority(ctx *transitionContext, result any) (router.Screen, any) {
+	r := result.(ui.RomPriorityOutput)
+	if r.Config != nil {
+		ctx.state.Config = r.Config
+	}
 	return popOrExit(ctx.stack)
 }
 
 func transitionCollectionsSettings(ctx *transitionContext, result any) (router.Screen, any) {
 	r := result.(ui.CollectionsSettingsOutput)
-	if r.SyncNeeded {
+	if r.SyncNeeded && offline.IsOffline() {
+		// Refetch every collection, not just recent changes, once back online.
+		if cm := cache.GetCacheManager(); cm != nil {
+			cm.SetMetadata(cache.MetaKeyCollectionsRefreshedAt, "")
+		}
+		ctx.showCollections = ctx.state.Config.ShowCollections(ctx.state.Host)
+	} else if r.SyncNeeded {
 		if cm := cache.GetCacheManager(); cm != nil {
 			cm.ClearCollections()
 			cm.SetMetadata(cache.MetaKeyCollectionsRefreshedAt, "")
@@ -709,6 +835,9 @@ func transitionToolsSettings(ctx *transitionContext, result any) (router.Screen,
 
 	switch r.Action {
 	case ui.ToolsSettingsActionSyncLocalArtwork:
+		if blockedOffline() {
+			return ScreenToolsSettings, pushInput
+		}
 		ctx.stack.Push(ScreenToolsSettings, pushInput, r)
 		return ScreenArtworkSync, ui.ArtworkSyncInput{
 			Config:         *ctx.state.Config,
@@ -716,6 +845,30 @@ func transitionToolsSettings(ctx *transitionContext, result any) (router.Screen,
 			DownloadedOnly: true,
 		}
 
+	case ui.ToolsSettingsActionOptimizeArt:
+		ctx.stack.Push(ScreenToolsSettings, pushInput, r)
+		return ScreenArtOptimize, ui.ArtOptimizeInput{
+			Config: *ctx.state.Config,
+			Host:   ctx.state.Host,
+		}
+
+	case ui.ToolsSettingsActionCleanGamelists:
+		ctx.stack.Push(ScreenToolsSettings, pushInput, r)
+		return ScreenGamelistCleanup, ui.GamelistCleanupInput{
+			Config: *ctx.state.Config,
+			Host:   ctx.state.Host,
+		}
+
+	case ui.ToolsSettingsActionFetchAllBIOS:
+		if blockedOffline() {
+			return ScreenToolsSettings, pushInput
+		}
+		ctx.stack.Push(ScreenToolsSettings, pushInput, r)
+		return ScreenBIOSFetchAll, ui.BIOSFetchAllInput{
+			Config: *ctx.state.Config,
+			Host:   ctx.state.Host,
+		}
+
 	default:
 		return popOrExit(ctx.stack)
 	}
@@ -728,6 +881,9 @@ func transitionAdvancedSettings(ctx *transitionContext, result any) (router.Scre
 
 	switch r.Action {
 	case ui.AdvancedSettingsActionRebuildCache:
+		if blockedOffline() {
+			return ScreenAdvancedSettings, pushInput
+		}
 		ctx.stack.Push(ScreenAdvancedSettings, pushInput, r)
 		return ScreenRebuildCache, ui.RebuildCacheInput{
 			Host:   ctx.state.Host,
@@ -735,6 +891,9 @@ func transitionAdvancedSettings(ctx *transitionContext, result any) (router.Scre
 		}
 
 	case ui.AdvancedSettingsActionSyncArtwork:
+		if blockedOffline() {
+			return ScreenAdvancedSettings, pushInput
+		}
 		ctx.stack.Push(ScreenAdvancedSettings, pushInput, r)
 		return ScreenArtworkSync, ui.ArtworkSyncInput{
 			Config: *ctx.state.Config,
@@ -755,10 +914,19 @@ func transitionAdvancedSettings(ctx *transitionContext, result any) (router.Scre
 	case ui.AdvancedSettingsActionResetInputMapping:
 		return popOrExit(ctx.stack)
 
+	case ui.AdvancedSettingsActionExportDiagnostics:
+		ctx.stack.Push(ScreenAdvancedSettings, pushInput, r)
+		return ScreenDiagnosticsExport, ui.DiagnosticsExportInput{
+			Config: ctx.state.Config,
+			Host:   ctx.state.Host,
+		}
+
 	default:
 		if ctx.state.AutoUpdate != nil {
 			ctx.state.AutoUpdate.Recheck(ctx.state.Config.ReleaseChannel)
 		}
+		ctx.state.CacheSync.SetRefreshInterval(ctx.state.Config.GetRefreshInterval())
+		updateLiveListener(ctx.state)
 		return popOrExit(ctx.stack)
 	}
 }
@@ -772,6 +940,7 @@ func transitionServerAddress(ctx *transitionContext, result any) (router.Screen,
 		if err := internal.SaveConfig(ctx.state.Config); err != nil {
 			gaba.GetLogger().Error("Failed to save config after server address change", "error", err)
 		}
+		restartLiveListener(ctx.state)
 	}
 
 	return popOrExit(ctx.stack)
@@ -800,6 +969,9 @@ func buildInfoInput(state *AppState) ui.InfoInput {
 func transitionInfo(ctx *transitionContext, result any) (router.Screen, any) {
 	r := result.(ui.InfoOutput)
 	if r.Action == ui.InfoActionLogout {
+		if blockedOffline() {
+			return ScreenInfo, buildInfoInput(ctx.state)
+		}
 		ctx.stack.Push(ScreenInfo, buildInfoInput(ctx.state), nil)
 		return ScreenLogoutConfirmation, nil
 	}
@@ -17,6 +17,31 @@ func mustLoadJSONMap[K comparable, V any](path string) map[K]V {
 	return jsonutil.MustLoadJSONMap[K, V](embeddedFiles, path)
 }
 
+// LibretroCoreToBIOS lists the BIOS files each core looks for. Checksums come from libretro's
+// System.dat. A file without one can only show as present, never verified or mismatched, so
+// it is checked against RomM's verified copies instead, when there are any. These are left
+// without one:
+//   - MAME and FBNeo sets (every .zip) are rebuilt for each romset version, so the archive's
+//     checksum changes with it.
+//   - Files that come in one dump per region, revision or release, where any of them works:
+//     tos.img, bios.sms, saturn_bios.bin, SGB1.sfc, SGB2.sfc, uni-bioscd.rom, the TI-83, Palm,
+//     JiffyDOS and MT-32/CM-32L ROMs, and the optional Kickstarts.
+//   - Dumps and images unique to each device: nds7.bin, nds9.bin, firmware.bin, the dsi_*.bin
+//     files, nds_sd_card.bin and flash.bin.
+//   - Emulator data shipped with the core or an asset pack rather than dumped: ScummVM's .dat,
+//     .tbl, .cpt, .lab, .m4b and .sf2 files, the BASS libraries, msxromdb.xml and bluemsx's
+//     Machines/Shared Roms/MSX.rom, codehandler.bin, GameIndex.yaml and PCSX2's bios folder,
+//     the mkxp-z RTP folders, ppge_atlas.zim, rom.db, nes.pal, font.bmp, .jar, .pk3 and .v32
+//     files, and hiscore.dat.
+//   - Single dumps whose System.dat checksum hasn't been carried over yet: the bsnes
+//     coprocessor halves (cx4, dsp1-4 and st010/011/018 .data.rom and .program.rom) and
+//     higan's program.rom; SkyEmu's cgb0_boot.bin, cgb_agb_boot.bin and dmg0_rom.bin;
+//     atari800's BB01R4_OS.ROM and XEGAME.ROM; ep128emu's Enterprise, TVC, CPC and Spectrum
+//     ROMs; Fuse's 128p-*, 256s-*, gluck.rom and trdos.rom; quasi88's n88*.rom and disk.rom;
+//     np2kai's bios.rom, FONT.ROM, itf.rom, sound.rom and 2608_*.WAV; the BK, Galaksija and
+//     X1 ROMs; iplrom30.dat; bioscv.rom; bootloader-dbvz.rom; Genesis Plus GX's sk.bin,
+//     sk2chip.bin, areplay.bin and ggenie.bin; gamegenie.nes; and the Saturn cartridges
+//     mpr-18811-mx.ic1 and mpr-19367-mx.ic1.
 var LibretroCoreToBIOS = mustLoadJSONMap[string, CoreBIOS]("data/core_requirements.json")
 var PlatformToLibretroCores = mustLoadJSONMap[string, []string]("data/platform_cores.json")
 
@@ -25,6 +50,8 @@ type File struct {
 	FileName     string // e.g., "gba_bios.bin"
 	RelativePath string // e.g., "gba_bios.bin" or "psx/scph5500.bin"
 	Optional     bool   // true if BIOS file is optional for the emulator to function
+	MD5          string // expected MD5 of a good dump, when known
+	SHA1         string // expected SHA1 of a good dump, when known
 }
 
 // CoreBIOS represents all BIOS requirements for a Libretro core
@@ -0,0 +1,71 @@
+package bios
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+
+	"grout/romm"
+)
+
+// MatchFirmware finds the firmware on the RomM server that provides biosFile, first by
+// checksum and then by file name, so renamed uploads are still found. A firmware whose
+// checksum is known and differs from biosFile's, such as another region's dump, is never
+// matched by name.
+func MatchFirmware(biosFile File, firmware []romm.Firmware) (romm.Firmware, bool) {
+	for _, fw := range firmware {
+		if biosFile.MD5 != "" && strings.EqualFold(fw.MD5Hash, biosFile.MD5) {
+			return fw, true
+		}
+		if biosFile.SHA1 != "" && strings.EqualFold(fw.SHA1Hash, biosFile.SHA1) {
+			return fw, true
+		}
+	}
+
+	names := []string{biosFile.FileName, filepath.Base(biosFile.RelativePath)}
+	for _, fw := range firmware {
+		if knownMismatch(biosFile, fw) {
+			continue
+		}
+		for _, name := range names {
+			if strings.EqualFold(fw.FileName, name) || strings.EqualFold(filepath.Base(fw.FilePath), name) {
+				return fw, true
+			}
+		}
+	}
+
+	return romm.Firmware{}, false
+}
+
+// knownMismatch reports whether fw's checksums show it isn't a good copy of biosFile.
+func knownMismatch(biosFile File, fw romm.Firmware) bool {
+	if biosFile.MD5 != "" && fw.MD5Hash != "" {
+		return !strings.EqualFold(fw.MD5Hash, biosFile.MD5)
+	}
+	if biosFile.SHA1 != "" && fw.SHA1Hash != "" {
+		return !strings.EqualFold(fw.SHA1Hash, biosFile.SHA1)
+	}
+	return false
+}
+
+// MatchesChecksum reports whether data is a good copy of biosFile. Files without a known
+// checksum always match.
+func MatchesChecksum(biosFile File, data []byte) bool {
+	if biosFile.MD5 != "" {
+		sum := md5.Sum(data)
+		return strings.EqualFold(hex.EncodeToString(sum[:]), biosFile.MD5)
+	}
+	if biosFile.SHA1 != "" {
+		sum := sha1.Sum(data)
+		return strings.EqualFold(hex.EncodeToString(sum[:]), biosFile.SHA1)
+	}
+	return true
+}
+
+// NeedsDownload reports whether a verified BIOS file should be fetched: required files
+// that are missing, and any file that doesn't match a good dump.
+func NeedsDownload(v Verification) bool {
+	return v.Status == StatusMissing || v.Status == StatusMismatch
+}
@@ -0,0 +1,66 @@
+package bios
+
+import (
+	"testing"
+
+	"grout/romm"
+)
+
+func TestMatchFirmware(t *testing.T) {
+	firmware := []romm.Firmware{
+		{ID: 1, FileName: "SCPH5501.BIN"},
+		{ID: 2, FileName: "gba.bin", MD5Hash: "A860E8C0B6D573D191E4EC7DB1B1E4F6"},
+		{ID: 3, FileName: "bios_CD_U.bin", MD5Hash: "278a9397d192149e84e820ac621a8edd"},
+		{ID: 4, FileName: "scph5500.bin", MD5Hash: "8dd7d5296a650fac7319bce665a6a53c"},
+		{ID: 5, FileName: "jp.bin", MD5Hash: "8DD7D5296A650FAC7319BCE665A6A53C"},
+	}
+
+	tests := []struct {
+		name   string
+		file   File
+		wantID int
+	}{
+		{"by name, ignoring case", File{FileName: "scph5501.bin", RelativePath: "scph5501.bin"}, 1},
+		{"by checksum when renamed", File{FileName: "gba_bios.bin", RelativePath: "gba_bios.bin", MD5: "a860e8c0b6d573d191e4ec7db1b1e4f6"}, 2},
+		{"not on the server", File{FileName: "dc_boot.bin", RelativePath: "dc/dc_boot.bin"}, 0},
+		{"not by name when the checksum differs", File{FileName: "bios_CD_U.bin", RelativePath: "bios_CD_U.bin", MD5: "2efd74e3232ff260e371b99f84024f7f"}, 0},
+		{"checksum before name", File{FileName: "scph5500.bin", RelativePath: "scph5500.bin", MD5: "8dd7d5296a650fac7319bce665a6a53c"}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fw, ok := MatchFirmware(tt.file, firmware)
+			if tt.wantID == 0 {
+				if ok {
+					t.Errorf("matched firmware %d, want none", fw.ID)
+				}
+				return
+			}
+			if !ok || fw.ID != tt.wantID {
+				t.Errorf("matched %d (%v), want %d", fw.ID, ok, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestMatchesChecksum(t *testing.T) {
+	data := []byte("bios")
+
+	tests := []struct {
+		name string
+		file File
+		want bool
+	}{
+		{"no checksum", File{}, true},
+		{"md5 matches", File{MD5: "88264747405203A0502C8D242FDAD7DF"}, true},
+		{"md5 differs", File{MD5: "a860e8c0b6d573d191e4ec7db1b1e4f6"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesChecksum(tt.file, data); got != tt.want {
+				t.Errorf("MatchesChecksum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package bios
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"grout/cfw"
+	"grout/romm"
+	"io"
+	"os"
+	"strings"
+)
+
+// Status is the result of checking a BIOS file on the device.
+type Status int
+
+const (
+	// StatusMissing means a required file isn't on the device.
+	StatusMissing Status = iota
+	// StatusOptional means an optional file isn't on the device.
+	StatusOptional
+	// StatusPresent means the file is there but there is no known checksum to check it against.
+	StatusPresent
+	// StatusVerified means the file matches a known good dump.
+	StatusVerified
+	// StatusMismatch means the file doesn't match any known good dump, usually a bad dump
+	// or a BIOS for the wrong region.
+	StatusMismatch
+)
+
+// Verification is the status of one BIOS file for a platform.
+type Verification struct {
+	File   File
+	Status Status
+	Path   string // the file that was checked; empty when missing
+	MD5    string
+}
+
+// Verify hashes every copy of biosFile the CFW looks for and checks it against the known
+// good checksums from core_requirements.json and the copies RomM has verified. When copies
+// disagree, the worst result is reported since the emulator may load any of them.
+func Verify(biosFile File, platformFSSlug string, firmware []romm.Firmware) Verification {
+	result := Verification{File: biosFile, Status: StatusMissing}
+	if biosFile.Optional {
+		result.Status = StatusOptional
+	}
+
+	md5s, sha1s := expectedHashes(biosFile, firmware)
+
+	for _, path := range cfw.GetBIOSFilePaths(biosFile.RelativePath, platformFSSlug) {
+		fileMD5, fileSHA1, err := hashFile(path)
+		if err != nil {
+			continue
+		}
+
+		status := StatusPresent
+		switch {
+		case md5s[fileMD5] || sha1s[fileSHA1]:
+			status = StatusVerified
+		case len(md5s) > 0 || len(sha1s) > 0:
+			status = StatusMismatch
+		}
+
+		if statusRank(status) > statusRank(result.Status) {
+			result.Status = status
+			result.Path = path
+			result.MD5 = fileMD5
+		}
+	}
+
+	return result
+}
+
+// VerifyPlatform checks every BIOS file the platform's cores use, plus any RomM has that
+// the core data doesn't know about.
+func VerifyPlatform(platformFSSlug string, firmware []romm.Firmware) []Verification {
+	files := GetFilesForPlatform(platformFSSlug)
+	known := make(map[string]bool, len(files))
+	for _, f := range files {
+		known[strings.ToLower(f.FileName)] = true
+	}
+	for _, fw := range firmware {
+		if !known[strings.ToLower(fw.FileName)] {
+			files = append(files, File{FileName: fw.FileName, RelativePath: fw.FileName})
+			known[strings.ToLower(fw.FileName)] = true
+		}
+	}
+
+	results := make([]Verification, 0, len(files))
+	for _, f := range files {
+		results = append(results, Verify(f, platformFSSlug, firmware))
+	}
+	return results
+}
+
+// expectedHashes collects the checksums a good copy of biosFile may have.
+func expectedHashes(biosFile File, firmware []romm.Firmware) (map[string]bool, map[string]bool) {
+	md5s := make(map[string]bool)
+	sha1s := make(map[string]bool)
+	if biosFile.MD5 != "" {
+		md5s[strings.ToLower(biosFile.MD5)] = true
+	}
+	if biosFile.SHA1 != "" {
+		sha1s[strings.ToLower(biosFile.SHA1)] = true
+	}
+	for _, fw := range firmware {
+		if !fw.IsVerified || !strings.EqualFold(fw.FileName, biosFile.FileName) {
+			continue
+		}
+		if fw.MD5Hash != "" {
+			md5s[strings.ToLower(fw.MD5Hash)] = true
+		}
+		if fw.SHA1Hash != "" {
+			sha1s[strings.ToLower(fw.SHA1Hash)] = true
+		}
+	}
+	return md5s, sha1s
+}
+
+// statusRank orders the statuses of a file that exists from best to worst.
+func statusRank(s Status) int {
+	switch s {
+	case StatusVerified:
+		return 1
+	case StatusPresent:
+		return 2
+	case StatusMismatch:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func hashFile(path string) (string, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	md5Hash := md5.New()
+	sha1Hash := sha1.New()
+	if _, err := io.Copy(io.MultiWriter(md5Hash, sha1Hash), f); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(md5Hash.Sum(nil)), hex.EncodeToString(sha1Hash.Sum(nil)), nil
+}
@@ -0,0 +1,87 @@
+package bios
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"grout/romm"
+)
+
+func TestVerify(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("CFW", "ROCKNIX")
+	t.Setenv("BASE_PATH", base)
+	biosDir := filepath.Join(base, "roms", "bios")
+	if err := os.MkdirAll(biosDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(biosDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// md5("good") and md5("other")
+	const goodMD5 = "755f85c2723bb39381c7379a604160d8"
+	write("good.bin", "good")
+	write("bad.bin", "bad")
+	write("unknown.bin", "anything")
+	write("romm.bin", "other")
+
+	firmware := []romm.Firmware{
+		{FileName: "romm.bin", IsVerified: true, MD5Hash: "795f3202b17cb6bc3d4b771d8c6c9eaf"},
+	}
+
+	tests := []struct {
+		file File
+		want Status
+	}{
+		{File{FileName: "good.bin", RelativePath: "good.bin", MD5: goodMD5}, StatusVerified},
+		{File{FileName: "bad.bin", RelativePath: "bad.bin", MD5: goodMD5}, StatusMismatch},
+		{File{FileName: "unknown.bin", RelativePath: "unknown.bin"}, StatusPresent},
+		{File{FileName: "romm.bin", RelativePath: "romm.bin"}, StatusVerified},
+		{File{FileName: "gone.bin", RelativePath: "gone.bin"}, StatusMissing},
+		{File{FileName: "gone.bin", RelativePath: "gone.bin", Optional: true}, StatusOptional},
+	}
+
+	for _, tt := range tests {
+		if got := Verify(tt.file, "gba", firmware); got.Status != tt.want {
+			t.Errorf("Verify(%s) = %d, want %d", tt.file.FileName, got.Status, tt.want)
+		}
+	}
+}
+
+func TestCoreRequirementChecksums(t *testing.T) {
+	isHex := func(s string, n int) bool {
+		if len(s) != n {
+			return false
+		}
+		for _, r := range s {
+			if !strings.ContainsRune("0123456789abcdef", r) {
+				return false
+			}
+		}
+		return true
+	}
+
+	byPath := make(map[string]string)
+	for core, req := range LibretroCoreToBIOS {
+		for _, f := range req.Files {
+			if f.MD5 != "" && !isHex(f.MD5, 32) {
+				t.Errorf("%s: %s has malformed MD5 %q", core, f.FileName, f.MD5)
+			}
+			if f.SHA1 != "" && !isHex(f.SHA1, 40) {
+				t.Errorf("%s: %s has malformed SHA1 %q", core, f.FileName, f.SHA1)
+			}
+			if f.MD5 == "" {
+				continue
+			}
+			if prev, ok := byPath[f.RelativePath]; ok && prev != f.MD5 {
+				t.Errorf("%s: %s has MD5 %s, another core lists %s", core, f.RelativePath, f.MD5, prev)
+			}
+			byPath[f.RelativePath] = f.MD5
+		}
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"grout/internal/fileutil"
 	"grout/internal/imageutil"
 	"grout/romm"
+	"hash/fnv"
 	"image/png"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
@@ -20,6 +23,55 @@ func GetArtworkCachePath(platformFSSlug string, romID int) string {
 	return filepath.Join(GetArtworkCacheDir(), platformFSSlug, strconv.Itoa(romID)+".png")
 }
 
+// GetArtPiecePath returns where one source image of a composite is kept, so the
+// composite can be rendered again without downloading its pieces. The name carries a
+// hash of url, so a piece whose artwork changed on the server is fetched again.
+func GetArtPiecePath(platformFSSlug string, romID int, piece, url string) string {
+	h := fnv.New32a()
+	h.Write([]byte(url))
+	return filepath.Join(artPiecesDir(platformFSSlug, romID), fmt.Sprintf("%s-%08x", piece, h.Sum32()))
+}
+
+// RemoveStaleArtPieces deletes the earlier versions of the piece kept at path, left
+// behind when its artwork changed on the server.
+func RemoveStaleArtPieces(path string) {
+	name := filepath.Base(path)
+	prefix := name[:strings.LastIndexByte(name, '-')+1]
+	files, _ := os.ReadDir(filepath.Dir(path))
+	for _, f := range files {
+		if f.Name() != name && strings.HasPrefix(f.Name(), prefix) {
+			os.Remove(filepath.Join(filepath.Dir(path), f.Name()))
+		}
+	}
+}
+
+func GetArtPiecesDir() string {
+	return filepath.Join(GetCacheDir(), "art_pieces")
+}
+
+func artPiecesDir(platformFSSlug string, romID int) string {
+	return filepath.Join(GetArtPiecesDir(), platformFSSlug, strconv.Itoa(romID))
+}
+
+// artPiecesSize returns the total size of the composite pieces cached for a game and
+// when the newest of them was written.
+func artPiecesSize(platformFSSlug string, romID int) (int64, time.Time) {
+	var size int64
+	var latest time.Time
+	files, _ := os.ReadDir(artPiecesDir(platformFSSlug, romID))
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil || f.IsDir() {
+			continue
+		}
+		size += info.Size()
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return size, latest
+}
+
 func ArtworkExists(platformFSSlug string, romID int) bool {
 	return fileutil.FileExists(GetArtworkCachePath(platformFSSlug, romID))
 }
@@ -84,6 +136,9 @@ func RunArtworkValidation() {
 			if removed > 0 {
 				gaba.GetLogger().Debug("Removed invalid artwork files", "count", removed)
 			}
+			if _, err := cm.EnforceArtworkBudget(); err != nil {
+				gaba.GetLogger().Debug("Failed to enforce artwork cache budget", "error", err)
+			}
 		}()
 	}
 }
@@ -99,6 +154,8 @@ func isValidPNG(path string) bool {
 	return err == nil
 }
 
+// GetMissingArtwork returns the roms whose covers aren't cached yet, limited to as
+// many as fit in the artwork cache budget.
 func GetMissingArtwork(roms []romm.Rom) []romm.Rom {
 	var missing []romm.Rom
 	for _, rom := range roms {
@@ -109,6 +166,13 @@ func GetMissingArtwork(roms []romm.Rom) []romm.Rom {
 			missing = append(missing, rom)
 		}
 	}
+
+	if cm := GetCacheManager(); cm != nil {
+		if room := cm.ArtworkBudgetRoom(); room >= 0 && len(missing) > room {
+			gaba.GetLogger().Debug("Limiting artwork prefetch to cache budget", "missing", len(missing), "room", room)
+			missing = missing[:room]
+		}
+	}
 	return missing
 }
 
@@ -180,6 +244,9 @@ func DownloadAndCacheArtwork(rom romm.Rom, kind artutil.ArtKind, host romm.Host)
 		return fmt.Errorf("processed artwork is not a valid PNG: %w", err)
 	}
 
+	if cm := GetCacheManager(); cm != nil {
+		cm.TrackArtwork(rom)
+	}
 	return nil
 }
 
@@ -196,4 +263,10 @@ func SyncArtworkInBackground(artkind artutil.ArtKind, host romm.Host, games []ro
 			logger.Debug("Failed to download artwork", "rom", rom.Name, "error", err)
 		}
 	}
+
+	if cm := GetCacheManager(); cm != nil {
+		if _, err := cm.EnforceArtworkBudget(); err != nil {
+			logger.Debug("Failed to enforce artwork cache budget", "error", err)
+		}
+	}
 }
@@ -0,0 +1,406 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"grout/internal/fileutil"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// DefaultArtworkCacheBudget is the artwork cache size used when none is configured.
+const DefaultArtworkCacheBudget int64 = 200 << 20
+
+// estimatedArtworkSize stands in for the average cover size before anything is cached.
+const estimatedArtworkSize int64 = 64 << 10
+
+var (
+	artworkHits   atomic.Int64
+	artworkMisses atomic.Int64
+)
+
+// ArtworkStats describes how well the artwork cache served this session.
+type ArtworkStats struct {
+	Hits   int64
+	Misses int64
+	Files  int
+	Size   int64
+	Budget int64 // 0 when the cache is unbounded
+}
+
+// artworkEntry is the tracked artwork of one game: its cached cover and the pieces
+// its composite is rendered from.
+type artworkEntry struct {
+	PlatformFSSlug string
+	RomID          int
+	Size           int64
+	DataJSON       string
+}
+
+// GetArtworkStats returns the artwork cache hit rate for this session and its current size.
+func (cm *Manager) GetArtworkStats() ArtworkStats {
+	stats := ArtworkStats{
+		Hits:   artworkHits.Load(),
+		Misses: artworkMisses.Load(),
+		Budget: cm.artworkBudget(),
+	}
+	if cm == nil || !cm.initialized {
+		return stats
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	cm.db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(size), 0) FROM artwork_access`).Scan(&stats.Files, &stats.Size)
+	return stats
+}
+
+// GetMetadataStats returns the metadata cache hits, misses and errors for this session.
+func (cm *Manager) GetMetadataStats() (hits, misses, errors int64) {
+	if cm == nil || cm.stats == nil {
+		return 0, 0, 0
+	}
+	cm.stats.mu.Lock()
+	defer cm.stats.mu.Unlock()
+	return cm.stats.Hits, cm.stats.Misses, cm.stats.Errors
+}
+
+// RecordArtworkAccess counts a cache hit or miss for each rom's cover and marks the
+// cached ones as recently used, so eviction removes the covers browsed longest ago.
+func (cm *Manager) RecordArtworkAccess(roms []romm.Rom) {
+	var present []artworkEntry
+	for _, rom := range roms {
+		if fileutil.FileExists(GetArtworkCachePath(rom.PlatformFSSlug, rom.ID)) {
+			artworkHits.Add(1)
+		} else {
+			artworkMisses.Add(1)
+		}
+		if size, ok := artworkSize(rom.PlatformFSSlug, rom.ID); ok {
+			present = append(present, artworkEntry{PlatformFSSlug: rom.PlatformFSSlug, RomID: rom.ID, Size: size})
+		}
+	}
+	cm.touchArtwork(present, nowUTC())
+}
+
+// TrackArtwork starts tracking a cover or composite pieces that were just written to
+// the cache.
+func (cm *Manager) TrackArtwork(rom romm.Rom) {
+	size, ok := artworkSize(rom.PlatformFSSlug, rom.ID)
+	if !ok {
+		return
+	}
+	cm.touchArtwork([]artworkEntry{{PlatformFSSlug: rom.PlatformFSSlug, RomID: rom.ID, Size: size}}, nowUTC())
+}
+
+// artworkSize returns the combined size of a game's cached cover and composite pieces,
+// and false when neither is cached.
+func artworkSize(platformFSSlug string, romID int) (int64, bool) {
+	size, _ := artPiecesSize(platformFSSlug, romID)
+	found := size > 0
+	if info, err := os.Stat(GetArtworkCachePath(platformFSSlug, romID)); err == nil {
+		size += info.Size()
+		found = true
+	}
+	return size, found
+}
+
+func (cm *Manager) touchArtwork(entries []artworkEntry, accessedAt string) {
+	if cm == nil || !cm.initialized || len(entries) == 0 {
+		return
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO artwork_access (platform_fs_slug, rom_id, size, last_access) VALUES (?, ?, ?, ?)
+		ON CONFLICT(platform_fs_slug, rom_id) DO UPDATE SET size = excluded.size, last_access = excluded.last_access
+	`)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	for _, e := range entries {
+		if _, err := stmt.Exec(e.PlatformFSSlug, e.RomID, e.Size, accessedAt); err != nil {
+			gaba.GetLogger().Debug("Failed to record artwork access", "platform", e.PlatformFSSlug, "rom_id", e.RomID, "error", err)
+			return
+		}
+	}
+	tx.Commit()
+}
+
+// ArtworkBudgetRoom returns how many more covers fit in the artwork cache, or -1
+// when the cache is unbounded. It is an estimate based on the average cover size.
+func (cm *Manager) ArtworkBudgetRoom() int {
+	budget := cm.artworkBudget()
+	if budget <= 0 {
+		return -1
+	}
+
+	stats := cm.GetArtworkStats()
+	avg := estimatedArtworkSize
+	if stats.Files > 0 && stats.Size > 0 {
+		avg = stats.Size / int64(stats.Files)
+	}
+	if stats.Size >= budget {
+		return 0
+	}
+	return int((budget - stats.Size) / avg)
+}
+
+// EnforceArtworkBudget removes the least recently used covers, along with their
+// composite pieces, until the artwork cache fits its budget. Artwork of games that are
+// downloaded on the device is never removed.
+func (cm *Manager) EnforceArtworkBudget() (int, error) {
+	if cm == nil || !cm.initialized {
+		return 0, ErrNotInitialized
+	}
+
+	if err := cm.reconcileArtworkAccess(); err != nil {
+		return 0, err
+	}
+
+	budget := cm.artworkBudget()
+	if budget <= 0 {
+		return 0, nil
+	}
+
+	cm.mu.RLock()
+	entries, total, err := cm.loadArtworkEntries()
+	cm.mu.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+	if total <= budget {
+		return 0, nil
+	}
+
+	evict := selectArtworkEvictions(entries, total, budget, cm.isDownloadedArtwork)
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	for _, e := range evict {
+		if err := os.Remove(GetArtworkCachePath(e.PlatformFSSlug, e.RomID)); err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		if err := os.RemoveAll(artPiecesDir(e.PlatformFSSlug, e.RomID)); err != nil {
+			continue
+		}
+		cm.db.Exec(`DELETE FROM artwork_access WHERE platform_fs_slug = ? AND rom_id = ?`, e.PlatformFSSlug, e.RomID)
+	}
+
+	gaba.GetLogger().Debug("Evicted artwork to fit cache budget", "count", len(evict), "budget", budget)
+	return len(evict), nil
+}
+
+// selectArtworkEvictions walks entries from least to most recently used and picks
+// unprotected ones until the remaining size fits budget.
+func selectArtworkEvictions(entries []artworkEntry, total, budget int64, protected func(artworkEntry) bool) []artworkEntry {
+	var evict []artworkEntry
+	for _, e := range entries {
+		if total <= budget {
+			break
+		}
+		if protected(e) {
+			continue
+		}
+		evict = append(evict, e)
+		total -= e.Size
+	}
+	return evict
+}
+
+func (cm *Manager) isDownloadedArtwork(e artworkEntry) bool {
+	if e.DataJSON == "" || cm.config == nil {
+		return false
+	}
+	var rom romm.Rom
+	if err := json.Unmarshal([]byte(e.DataJSON), &rom); err != nil {
+		return false
+	}
+	return rom.IsDownloaded(cm.config)
+}
+
+// loadArtworkEntries returns the tracked covers, least recently used first, with the
+// cached game data used to tell whether the game is downloaded. Callers hold cm.mu.
+func (cm *Manager) loadArtworkEntries() ([]artworkEntry, int64, error) {
+	rows, err := cm.db.Query(`
+		SELECT a.platform_fs_slug, a.rom_id, a.size, COALESCE(g.data_json, '')
+		FROM artwork_access a
+		LEFT JOIN games g ON g.id = a.rom_id
+		ORDER BY a.last_access ASC
+	`)
+	if err != nil {
+		return nil, 0, newCacheError("load_artwork", "artwork_access", "", err)
+	}
+	defer rows.Close()
+
+	var entries []artworkEntry
+	var total int64
+	for rows.Next() {
+		var e artworkEntry
+		if err := rows.Scan(&e.PlatformFSSlug, &e.RomID, &e.Size, &e.DataJSON); err != nil {
+			return nil, 0, newCacheError("load_artwork", "artwork_access", "", err)
+		}
+		entries = append(entries, e)
+		total += e.Size
+	}
+	return entries, total, rows.Err()
+}
+
+// reconcileArtworkAccess brings artwork_access in line with the files on disk: covers
+// and composite pieces cached before access tracking (or by the artwork preloader) are
+// added using their modification time, and rows for deleted files are dropped.
+func (cm *Manager) reconcileArtworkAccess() error {
+	type artworkKey struct {
+		platformFSSlug string
+		romID          int
+	}
+	type artworkFiles struct {
+		size    int64
+		modTime time.Time
+	}
+	found := make(map[artworkKey]artworkFiles)
+	add := func(key artworkKey, size int64, modTime time.Time) {
+		f := found[key]
+		f.size += size
+		if modTime.After(f.modTime) {
+			f.modTime = modTime
+		}
+		found[key] = f
+	}
+
+	cacheDir := GetArtworkCacheDir()
+	platformDirs, _ := os.ReadDir(cacheDir)
+	for _, platformDir := range platformDirs {
+		if !platformDir.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(cacheDir, platformDir.Name()))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			romID, err := strconv.Atoi(strings.TrimSuffix(file.Name(), ".png"))
+			if file.IsDir() || filepath.Ext(file.Name()) != ".png" || err != nil {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				continue
+			}
+			add(artworkKey{platformDir.Name(), romID}, info.Size(), info.ModTime())
+		}
+	}
+
+	piecesDir := GetArtPiecesDir()
+	platformDirs, _ = os.ReadDir(piecesDir)
+	for _, platformDir := range platformDirs {
+		if !platformDir.IsDir() {
+			continue
+		}
+		romDirs, err := os.ReadDir(filepath.Join(piecesDir, platformDir.Name()))
+		if err != nil {
+			continue
+		}
+		for _, romDir := range romDirs {
+			romID, err := strconv.Atoi(romDir.Name())
+			if !romDir.IsDir() || err != nil {
+				continue
+			}
+			if size, modTime := artPiecesSize(platformDir.Name(), romID); size > 0 {
+				add(artworkKey{platformDir.Name(), romID}, size, modTime)
+			}
+		}
+	}
+
+	onDisk := make(map[artworkEntry]time.Time, len(found))
+	for key, f := range found {
+		onDisk[artworkEntry{PlatformFSSlug: key.platformFSSlug, RomID: key.romID, Size: f.size}] = f.modTime
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return newCacheError("reconcile_artwork", "artwork_access", "", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`CREATE TEMP TABLE IF NOT EXISTS artwork_on_disk (platform_fs_slug TEXT, rom_id INTEGER)`); err != nil {
+		return newCacheError("reconcile_artwork", "artwork_access", "", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM artwork_on_disk`); err != nil {
+		return newCacheError("reconcile_artwork", "artwork_access", "", err)
+	}
+
+	insertDisk, err := tx.Prepare(`INSERT INTO artwork_on_disk (platform_fs_slug, rom_id) VALUES (?, ?)`)
+	if err != nil {
+		return newCacheError("reconcile_artwork", "artwork_access", "", err)
+	}
+	defer insertDisk.Close()
+	insertAccess, err := tx.Prepare(`
+		INSERT INTO artwork_access (platform_fs_slug, rom_id, size, last_access) VALUES (?, ?, ?, ?)
+		ON CONFLICT(platform_fs_slug, rom_id) DO UPDATE SET size = excluded.size
+	`)
+	if err != nil {
+		return newCacheError("reconcile_artwork", "artwork_access", "", err)
+	}
+	defer insertAccess.Close()
+
+	for e, modTime := range onDisk {
+		if _, err := insertDisk.Exec(e.PlatformFSSlug, e.RomID); err != nil {
+			return newCacheError("reconcile_artwork", "artwork_access", "", err)
+		}
+		if _, err := insertAccess.Exec(e.PlatformFSSlug, e.RomID, e.Size, modTime.UTC().Format(time.RFC3339)); err != nil {
+			return newCacheError("reconcile_artwork", "artwork_access", "", err)
+		}
+	}
+
+	if _, err := tx.Exec(`
+		DELETE FROM artwork_access WHERE NOT EXISTS (
+			SELECT 1 FROM artwork_on_disk d
+			WHERE d.platform_fs_slug = artwork_access.platform_fs_slug AND d.rom_id = artwork_access.rom_id
+		)
+	`); err != nil {
+		return newCacheError("reconcile_artwork", "artwork_access", "", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCacheError("reconcile_artwork", "artwork_access", "", err)
+	}
+	return nil
+}
+
+func (cm *Manager) artworkBudget() int64 {
+	if cm == nil || cm.config == nil {
+		return DefaultArtworkCacheBudget
+	}
+	return cm.config.GetArtworkCacheBudget()
+}
+
+// clearArtworkAccess forgets every tracked cover after the artwork directory is removed.
+func (cm *Manager) clearArtworkAccess() {
+	if cm == nil || !cm.initialized {
+		return
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if _, err := cm.db.Exec(`DELETE FROM artwork_access`); err != nil {
+		gaba.GetLogger().Debug("Failed to clear artwork access", "error", err)
+	}
+}
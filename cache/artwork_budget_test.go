@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"grout/romm"
+)
+
+func TestSelectArtworkEvictions(t *testing.T) {
+	entries := []artworkEntry{
+		{PlatformFSSlug: "gba", RomID: 1, Size: 40},
+		{PlatformFSSlug: "gba", RomID: 2, Size: 40},
+		{PlatformFSSlug: "snes", RomID: 3, Size: 40},
+		{PlatformFSSlug: "snes", RomID: 4, Size: 40},
+	}
+	protected := func(e artworkEntry) bool { return e.RomID == 2 }
+
+	evict := selectArtworkEvictions(entries, 160, 80, protected)
+
+	if len(evict) != 2 {
+		t.Fatalf("evicted %d entries, want 2", len(evict))
+	}
+	if evict[0].RomID != 1 || evict[1].RomID != 3 {
+		t.Errorf("evicted rom IDs %d and %d, want 1 and 3 (oldest unprotected)", evict[0].RomID, evict[1].RomID)
+	}
+
+	if got := selectArtworkEvictions(entries, 160, 200, protected); len(got) != 0 {
+		t.Errorf("evicted %d entries under budget, want 0", len(got))
+	}
+}
+
+func TestArtworkAccessOrdersLeastRecentlyUsedFirst(t *testing.T) {
+	cm := newTestManager(t)
+
+	cm.touchArtwork([]artworkEntry{
+		{PlatformFSSlug: "gba", RomID: 1, Size: 100},
+		{PlatformFSSlug: "gba", RomID: 2, Size: 200},
+	}, "2026-01-01T00:00:00Z")
+	cm.touchArtwork([]artworkEntry{{PlatformFSSlug: "gba", RomID: 1, Size: 120}}, "2026-02-01T00:00:00Z")
+
+	entries, total, err := cm.loadArtworkEntries()
+	if err != nil {
+		t.Fatalf("loadArtworkEntries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].RomID != 2 || entries[1].RomID != 1 {
+		t.Errorf("order = %d, %d; want 2, 1", entries[0].RomID, entries[1].RomID)
+	}
+	if total != 320 {
+		t.Errorf("total = %d, want 320 (updated size counted once)", total)
+	}
+}
+
+func TestArtPiecesCountTowardsBudget(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cm := newTestManager(t)
+	cm.config = &testBudgetConfig{budget: 150}
+
+	writeFile := func(path string, size int) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Game 1 only has composite pieces; game 2 a cover.
+	old := GetArtPiecePath("gba", 1, "box", "http://romm/box-v1.png")
+	writeFile(old, 60)
+	writeFile(GetArtPiecePath("gba", 1, "logo", "http://romm/logo.png"), 40)
+	writeFile(GetArtworkCachePath("gba", 2), 100)
+
+	if size, ok := artworkSize("gba", 1); !ok || size != 100 {
+		t.Errorf("artworkSize(pieces only) = %d, %v; want 100, true", size, ok)
+	}
+
+	// A new box URL leaves the old version behind until it is replaced.
+	current := GetArtPiecePath("gba", 1, "box", "http://romm/box-v2.png")
+	if current == old {
+		t.Fatal("changed URL kept the same piece path")
+	}
+	writeFile(current, 60)
+	RemoveStaleArtPieces(current)
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("stale piece was not removed")
+	}
+	if _, err := os.Stat(GetArtPiecePath("gba", 1, "logo", "http://romm/logo.png")); err != nil {
+		t.Errorf("other pieces were removed: %v", err)
+	}
+
+	// Make game 1 the least recently used so it is evicted first.
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(current, past, past)
+	os.Chtimes(GetArtPiecePath("gba", 1, "logo", "http://romm/logo.png"), past, past)
+
+	evicted, err := cm.EnforceArtworkBudget()
+	if err != nil {
+		t.Fatalf("EnforceArtworkBudget: %v", err)
+	}
+	if evicted != 1 {
+		t.Errorf("evicted %d entries, want 1", evicted)
+	}
+	if _, err := os.Stat(artPiecesDir("gba", 1)); !os.IsNotExist(err) {
+		t.Error("pieces of the evicted game are still cached")
+	}
+	if _, err := os.Stat(GetArtworkCachePath("gba", 2)); err != nil {
+		t.Errorf("the newer cover was evicted: %v", err)
+	}
+
+	writeFile(GetArtPiecePath("gba", 3, "box", "http://romm/box.png"), 10)
+	cm.ClearArtwork()
+	if _, err := os.Stat(GetArtPiecesDir()); !os.IsNotExist(err) {
+		t.Error("Clear artwork left the composite pieces behind")
+	}
+}
+
+type testBudgetConfig struct {
+	budget int64
+}
+
+func (c *testBudgetConfig) GetPlatformRomDirectory(romm.Platform) string      { return "" }
+func (c *testBudgetConfig) GetPlatformGamelistDirectory(romm.Platform) string { return "" }
+func (c *testBudgetConfig) GetApiTimeout() time.Duration                      { return time.Second }
+func (c *testBudgetConfig) GetShowCollections() bool                          { return false }
+func (c *testBudgetConfig) GetShowSmartCollections() bool                     { return false }
+func (c *testBudgetConfig) GetShowVirtualCollections() bool                   { return false }
+func (c *testBudgetConfig) GetArtworkCacheBudget() int64                      { return c.budget }
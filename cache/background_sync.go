@@ -1,16 +1,29 @@
 package cache
 
 import (
+	"errors"
+	"fmt"
 	"grout/romm"
 	"sync"
+	"time"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
 
 const (
-	iconSynced  = "\U000F0AA9"
-	iconSyncing = "\U000F0CFF"
-	iconAlert   = "\U000F163A"
+	iconSynced     = "\U000F0AA9"
+	iconSyncing    = "\U000F0CFF"
+	iconAlert      = "\U000F163A"
+	iconSavesNewer = "\U000F0162"
+)
+
+const (
+	// heartbeatTimeout keeps a scheduled refresh from waiting long on a server that is down.
+	heartbeatTimeout = 3 * time.Second
+
+	// minBackoff is the first retry delay after a failed refresh. It doubles with each
+	// further failure, up to the refresh interval.
+	minBackoff = time.Minute
 )
 
 type syncType int
@@ -26,22 +39,53 @@ type syncRequest struct {
 	Platforms []romm.Platform
 }
 
+// SaveStatusFunc reports how many saves on the server are newer than the ones on the
+// device. It is supplied by the caller because save sync lives outside the cache.
+type SaveStatusFunc func() (int, error)
+
+// BackgroundSync keeps the cache up to date. It syncs once when started, then again
+// every refresh interval while RomM answers its heartbeat, backing off after failures.
 type BackgroundSync struct {
 	platforms []romm.Platform
 	icon      *gaba.DynamicStatusBarIcon
+	saveIcon  *gaba.DynamicStatusBarIcon
 	requests  chan syncRequest
 	stop      chan struct{}
 	wg        sync.WaitGroup
 	mu        sync.Mutex
 	running   bool
+
+	interval        time.Duration
+	intervalChanged chan struct{}
+	saveStatus      SaveStatusFunc
+	heartbeat       func() error
+
+	// changed signals platforms waiting in pending to be refetched.
+	changed chan struct{}
+	pending map[int]romm.Platform
+
+	// saveStatusRequested asks for a save status check. It is kept out of requests so a
+	// check waiting to run never crowds out a sync.
+	saveStatusRequested chan struct{}
+
+	// failures counts consecutive failed refreshes; only the worker touches it.
+	failures int
 }
 
 func NewBackgroundSync(platforms []romm.Platform) *BackgroundSync {
 	return &BackgroundSync{
 		platforms: platforms,
 		icon:      gaba.NewDynamicStatusBarIcon(iconSyncing),
+		saveIcon:  gaba.NewDynamicStatusBarIcon(""),
 		requests:  make(chan syncRequest, 1),
 		stop:      make(chan struct{}),
+		heartbeat: checkHeartbeat,
+
+		intervalChanged: make(chan struct{}, 1),
+		changed:         make(chan struct{}, 1),
+		pending:         make(map[int]romm.Platform),
+
+		saveStatusRequested: make(chan struct{}, 1),
 	}
 }
 
@@ -51,6 +95,45 @@ func (b *BackgroundSync) Icon() gaba.StatusBarIcon {
 	}
 }
 
+// SaveStatusIcon shows how many saves are newer on the server, and nothing when none are.
+func (b *BackgroundSync) SaveStatusIcon() gaba.StatusBarIcon {
+	return gaba.StatusBarIcon{
+		Dynamic: b.saveIcon,
+	}
+}
+
+// SetRefreshInterval sets how often the cache is refreshed after the initial sync. Zero
+// turns periodic refreshes off.
+func (b *BackgroundSync) SetRefreshInterval(interval time.Duration) {
+	b.mu.Lock()
+	b.interval = interval
+	b.mu.Unlock()
+
+	b.reschedule()
+}
+
+// SetSaveStatusCheck sets the check run after each successful refresh to update the
+// save status icon.
+func (b *BackgroundSync) SetSaveStatusCheck(check SaveStatusFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.saveStatus = check
+}
+
+// StartScheduled starts the worker without syncing now, for when the cache was just
+// built in the foreground.
+func (b *BackgroundSync) StartScheduled() {
+	b.ensureWorkerRunning()
+	b.reschedule()
+}
+
+func (b *BackgroundSync) reschedule() {
+	select {
+	case b.intervalChanged <- struct{}{}:
+	default:
+	}
+}
+
 func (b *BackgroundSync) Start() {
 	if b.ensureWorkerRunning() {
 		b.queueSync(syncRequest{Type: syncFull})
@@ -72,6 +155,35 @@ func (b *BackgroundSync) SyncPlatforms(platforms []romm.Platform) {
 	b.queueSync(syncRequest{Type: syncPlatformsOnly, Platforms: platforms})
 }
 
+// RefreshPlatforms refetches the given platforms, e.g. after RomM reported changes to
+// them. Platforms requested while a refetch runs are merged into the next one.
+func (b *BackgroundSync) RefreshPlatforms(platforms []romm.Platform) {
+	b.mu.Lock()
+	for _, platform := range platforms {
+		b.pending[platform.ID] = platform
+	}
+	b.mu.Unlock()
+
+	b.ensureWorkerRunning()
+	select {
+	case b.changed <- struct{}{}:
+	default:
+	}
+}
+
+// RefreshSaveStatus re-runs the save status check, e.g. after a save sync. It does
+// nothing before the first sync has started. Requests made while a check is waiting
+// are merged into it.
+func (b *BackgroundSync) RefreshSaveStatus() {
+	if !b.IsRunning() {
+		return
+	}
+	select {
+	case b.saveStatusRequested <- struct{}{}:
+	default:
+	}
+}
+
 // ensureWorkerRunning starts the worker if not running. Returns true if worker was started.
 func (b *BackgroundSync) ensureWorkerRunning() bool {
 	b.mu.Lock()
@@ -125,31 +237,106 @@ func (b *BackgroundSync) worker() {
 	logger := gaba.GetLogger()
 	defer b.wg.Done()
 
+	var next <-chan time.Time
+
 	for {
 		select {
 		case <-b.stop:
 			logger.Debug("BackgroundSync: Worker stopped")
 			return
 		case req := <-b.requests:
-			b.runSync(req)
+			ok := b.runSync(req)
+			if req.Type == syncFull {
+				b.recordOutcome(ok)
+				next = b.nextRefresh()
+			}
+		case <-b.intervalChanged:
+			next = b.nextRefresh()
+		case <-b.changed:
+			b.runChanged()
+		case <-b.saveStatusRequested:
+			b.runSaveStatus()
+		case <-next:
+			b.recordOutcome(b.runScheduled())
+			next = b.nextRefresh()
 		}
 	}
 }
 
-func (b *BackgroundSync) runSync(req syncRequest) {
+func (b *BackgroundSync) recordOutcome(ok bool) {
+	if ok {
+		b.failures = 0
+	} else {
+		b.failures++
+	}
+}
+
+// nextRefresh returns when the next refresh is due, or nil when periodic refreshes
+// are off.
+func (b *BackgroundSync) nextRefresh() <-chan time.Time {
+	b.mu.Lock()
+	interval := b.interval
+	b.mu.Unlock()
+
+	delay := nextRefreshDelay(interval, b.failures)
+	if delay <= 0 {
+		return nil
+	}
+	gaba.GetLogger().Debug("BackgroundSync: Next refresh scheduled", "in", delay, "failures", b.failures)
+	return time.After(delay)
+}
+
+// nextRefreshDelay is the interval after a success and an exponential backoff, starting
+// at minBackoff and capped at the interval, after consecutive failures.
+func nextRefreshDelay(interval time.Duration, failures int) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	if failures == 0 {
+		return interval
+	}
+
+	delay := minBackoff
+	for i := 1; i < failures && delay < interval; i++ {
+		delay *= 2
+	}
+	return min(delay, interval)
+}
+
+// runScheduled refreshes the cache if RomM answers its heartbeat.
+func (b *BackgroundSync) runScheduled() bool {
+	if err := b.heartbeat(); err != nil {
+		gaba.GetLogger().Debug("BackgroundSync: Skipping refresh, RomM unreachable", "error", err)
+		b.icon.SetText(iconAlert)
+		return false
+	}
+	return b.runSync(syncRequest{Type: syncFull})
+}
+
+func checkHeartbeat() error {
+	cm := GetCacheManager()
+	if cm == nil {
+		return errors.New("cache manager not initialized")
+	}
+	_, err := romm.NewClientFromHost(cm.host, heartbeatTimeout).GetHeartbeat()
+	return err
+}
+
+func (b *BackgroundSync) runSync(req syncRequest) (ok bool) {
 	logger := gaba.GetLogger()
 
 	defer func() {
 		if r := recover(); r != nil {
 			logger.Error("BackgroundSync: Panic recovered", "panic", r)
 			b.icon.SetText(iconAlert)
+			ok = false
 		}
 	}()
 
 	// Check if stopped
 	select {
 	case <-b.stop:
-		return
+		return false
 	default:
 	}
 
@@ -159,7 +346,7 @@ func (b *BackgroundSync) runSync(req syncRequest) {
 	if cm == nil {
 		logger.Error("BackgroundSync: Cache manager not initialized")
 		b.icon.SetText(iconAlert)
-		return
+		return false
 	}
 
 	var err error
@@ -190,16 +377,90 @@ func (b *BackgroundSync) runSync(req syncRequest) {
 	// Check if we were stopped mid-sync
 	select {
 	case <-b.stop:
-		return
+		return false
 	default:
 	}
 
 	if err != nil {
 		logger.Error("BackgroundSync: Sync failed", "error", err)
 		b.icon.SetText(iconAlert)
-		return
+		return false
 	}
 
 	b.icon.SetText(iconSynced)
 	logger.Debug("BackgroundSync: Sync completed")
+
+	if req.Type == syncFull {
+		b.runSaveStatus()
+	}
+	return true
+}
+
+// runChanged refetches the platforms waiting in pending.
+func (b *BackgroundSync) runChanged() {
+	logger := gaba.GetLogger()
+
+	b.mu.Lock()
+	platforms := make([]romm.Platform, 0, len(b.pending))
+	for _, platform := range b.pending {
+		platforms = append(platforms, platform)
+	}
+	clear(b.pending)
+	b.mu.Unlock()
+
+	cm := GetCacheManager()
+	if len(platforms) == 0 || cm == nil {
+		return
+	}
+
+	logger.Debug("BackgroundSync: Refetching changed platforms", "platforms", len(platforms))
+	b.icon.SetText(iconSyncing)
+
+	failed := false
+	for _, platform := range platforms {
+		select {
+		case <-b.stop:
+			return
+		default:
+		}
+
+		if _, err := cm.RefetchPlatformGames(platform); err != nil {
+			logger.Error("BackgroundSync: Failed to refetch changed platform", "platform", platform.Name, "error", err)
+			failed = true
+		}
+	}
+
+	if failed {
+		b.icon.SetText(iconAlert)
+		return
+	}
+	b.icon.SetText(iconSynced)
+}
+
+// runSaveStatus updates the save status icon. A failed check leaves the last count shown.
+func (b *BackgroundSync) runSaveStatus() bool {
+	b.mu.Lock()
+	check := b.saveStatus
+	b.mu.Unlock()
+
+	if check == nil {
+		return true
+	}
+
+	newer, err := check()
+	if err != nil {
+		gaba.GetLogger().Debug("BackgroundSync: Save status check failed", "error", err)
+		return false
+	}
+
+	gaba.GetLogger().Debug("BackgroundSync: Save status checked", "newer_on_server", newer)
+	b.saveIcon.SetText(saveStatusText(newer))
+	return true
+}
+
+func saveStatusText(newer int) string {
+	if newer <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s %d", iconSavesNewer, newer)
 }
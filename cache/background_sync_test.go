@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextRefreshDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		failures int
+		want     time.Duration
+	}{
+		{"off", 0, 0, 0},
+		{"off after failures", 0, 3, 0},
+		{"success", 30 * time.Minute, 0, 30 * time.Minute},
+		{"first failure", 30 * time.Minute, 1, time.Minute},
+		{"second failure", 30 * time.Minute, 2, 2 * time.Minute},
+		{"fifth failure", 30 * time.Minute, 5, 16 * time.Minute},
+		{"capped at interval", 30 * time.Minute, 6, 30 * time.Minute},
+		{"many failures", 30 * time.Minute, 200, 30 * time.Minute},
+		{"interval shorter than backoff", 30 * time.Second, 1, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextRefreshDelay(tt.interval, tt.failures); got != tt.want {
+				t.Errorf("nextRefreshDelay(%v, %d) = %v, want %v", tt.interval, tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunScheduledSkipsWhenHeartbeatFails(t *testing.T) {
+	b := NewBackgroundSync(nil)
+	b.heartbeat = func() error { return errors.New("unreachable") }
+
+	checked := false
+	b.SetSaveStatusCheck(func() (int, error) {
+		checked = true
+		return 0, nil
+	})
+
+	if b.runScheduled() {
+		t.Fatal("runScheduled succeeded with a failing heartbeat")
+	}
+	if checked {
+		t.Error("save status was checked without a heartbeat")
+	}
+	if got := b.icon.GetText(); got != iconAlert {
+		t.Errorf("icon = %q, want alert", got)
+	}
+}
+
+func TestSaveStatusIcon(t *testing.T) {
+	b := NewBackgroundSync(nil)
+	newer := 3
+	b.SetSaveStatusCheck(func() (int, error) { return newer, nil })
+
+	if !b.runSaveStatus() {
+		t.Fatal("runSaveStatus failed")
+	}
+	if got, want := b.saveIcon.GetText(), iconSavesNewer+" 3"; got != want {
+		t.Errorf("icon = %q, want %q", got, want)
+	}
+
+	newer = 0
+	b.runSaveStatus()
+	if got := b.saveIcon.GetText(); got != "" {
+		t.Errorf("icon = %q, want empty when nothing is newer", got)
+	}
+}
+
+func TestRefreshSaveStatusDoesNotCrowdOutSyncs(t *testing.T) {
+	b := NewBackgroundSync(nil)
+	b.running = true // as if the worker were busy with a sync
+
+	b.RefreshSaveStatus()
+	b.RefreshSaveStatus()
+	b.SyncCollections()
+
+	select {
+	case req := <-b.requests:
+		if req.Type != syncCollectionsOnly {
+			t.Errorf("queued request = %v, want collections sync", req.Type)
+		}
+	default:
+		t.Fatal("collections sync was dropped behind a save status check")
+	}
+	if got := len(b.saveStatusRequested); got != 1 {
+		t.Errorf("%d save status checks queued, want 1", got)
+	}
+}
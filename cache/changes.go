@@ -0,0 +1,60 @@
+package cache
+
+import "sync"
+
+// changedPlatforms holds the platforms whose games were refetched after a change on
+// the server, until a game list showing them picks up the change.
+var changedPlatforms = struct {
+	mu  sync.Mutex
+	ids map[int]struct{}
+}{ids: make(map[int]struct{})}
+
+// platformChangeSignal is signalled each time a platform is marked changed, so an open
+// game list can refresh in place.
+var platformChangeSignal = make(chan struct{}, 1)
+
+func markPlatformChanged(platformID int) {
+	changedPlatforms.mu.Lock()
+	changedPlatforms.ids[platformID] = struct{}{}
+	changedPlatforms.mu.Unlock()
+
+	select {
+	case platformChangeSignal <- struct{}{}:
+	default:
+	}
+}
+
+// PlatformChangeSignal receives after platforms are marked changed. Signals sent while
+// nothing is listening are merged into one.
+func PlatformChangeSignal() <-chan struct{} {
+	return platformChangeSignal
+}
+
+// HasPlatformChanges reports whether any of the platforms changed, without clearing them.
+func HasPlatformChanges(platformIDs ...int) bool {
+	changedPlatforms.mu.Lock()
+	defer changedPlatforms.mu.Unlock()
+
+	for _, id := range platformIDs {
+		if _, ok := changedPlatforms.ids[id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TakePlatformChanges reports whether any of the platforms changed since the last call
+// that covered them, and clears those platforms.
+func TakePlatformChanges(platformIDs ...int) bool {
+	changedPlatforms.mu.Lock()
+	defer changedPlatforms.mu.Unlock()
+
+	changed := false
+	for _, id := range platformIDs {
+		if _, ok := changedPlatforms.ids[id]; ok {
+			delete(changedPlatforms.ids, id)
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,29 @@
+package cache
+
+import "testing"
+
+func TestPlatformChanges(t *testing.T) {
+	TakePlatformChanges(1, 2)
+	select {
+	case <-PlatformChangeSignal():
+	default:
+	}
+
+	markPlatformChanged(2)
+	markPlatformChanged(2)
+
+	select {
+	case <-PlatformChangeSignal():
+	default:
+		t.Fatal("no signal after a platform changed")
+	}
+	if !HasPlatformChanges(1, 2) {
+		t.Error("HasPlatformChanges = false, want true")
+	}
+	if !TakePlatformChanges(2) {
+		t.Error("HasPlatformChanges cleared the change")
+	}
+	if HasPlatformChanges(1, 2) || TakePlatformChanges(2) {
+		t.Error("change still reported after it was taken")
+	}
+}
@@ -1,10 +1,16 @@
 package cache
 
-import "time"
+import (
+	"grout/romm"
+	"time"
+)
 
 type Config interface {
+	romm.PlatformDirResolver
+	GetPlatformGamelistDirectory(romm.Platform) string
 	GetApiTimeout() time.Duration
 	GetShowCollections() bool
 	GetShowSmartCollections() bool
 	GetShowVirtualCollections() bool
+	GetArtworkCacheBudget() int64 // Bytes; 0 means unbounded
 }
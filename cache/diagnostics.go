@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// Diagnostics is a snapshot of the cache database for bug reports.
+type Diagnostics struct {
+	// SchemaVersion is the version recorded in the database; ExpectedSchemaVersion is
+	// the one this build creates. They differ only if a migration failed.
+	SchemaVersion         string               `json:"schema_version"`
+	ExpectedSchemaVersion int                  `json:"expected_schema_version"`
+	TableRows             map[string]int64     `json:"table_rows"`
+	RefreshTimes          map[string]time.Time `json:"refresh_times"`
+}
+
+// Diagnostics reports the schema version, the row count of every table and the last
+// refresh times.
+func (cm *Manager) Diagnostics() Diagnostics {
+	diagnostics := Diagnostics{ExpectedSchemaVersion: schemaVersion, TableRows: map[string]int64{}}
+	if cm == nil || !cm.initialized {
+		return diagnostics
+	}
+
+	diagnostics.SchemaVersion, _ = cm.GetMetadata("schema_version")
+	diagnostics.RefreshTimes = cm.GetAllRefreshTimes()
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	rows, err := cm.db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		gaba.GetLogger().Error("Failed to list cache tables", "error", err)
+		return diagnostics
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err == nil {
+			tables = append(tables, name)
+		}
+	}
+	rows.Close()
+
+	for _, table := range tables {
+		var count int64
+		// Table names come from sqlite_master, not user input.
+		if err := cm.db.QueryRow(`SELECT COUNT(*) FROM "` + table + `"`).Scan(&count); err != nil {
+			gaba.GetLogger().Warn("Failed to count cache table rows", "table", table, "error", err)
+			continue
+		}
+		diagnostics.TableRows[table] = count
+	}
+
+	return diagnostics
+}
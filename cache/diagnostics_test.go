@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestDiagnostics_CountsRowsPerTable(t *testing.T) {
+	cm := newTestManager(t)
+
+	if err := cm.RecordSaveSync(SaveSyncRecord{RomID: 1, RomName: "Tetris", Action: "upload", DeviceID: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.RecordRefreshTime(MetaKeyGamesRefreshedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics := cm.Diagnostics()
+
+	if diagnostics.SchemaVersion != strconv.Itoa(schemaVersion) {
+		t.Errorf("schema version = %q, want %d", diagnostics.SchemaVersion, schemaVersion)
+	}
+	if got := diagnostics.TableRows["save_sync_history"]; got != 1 {
+		t.Errorf("save_sync_history rows = %d, want 1", got)
+	}
+	if _, ok := diagnostics.TableRows["games"]; !ok {
+		t.Errorf("games table missing from %v", diagnostics.TableRows)
+	}
+	if _, ok := diagnostics.RefreshTimes[MetaKeyGamesRefreshedAt]; !ok {
+		t.Error("games refresh time missing")
+	}
+}
+
+func TestDiagnostics_NilManager(t *testing.T) {
+	var cm *Manager
+	if diagnostics := cm.Diagnostics(); diagnostics.ExpectedSchemaVersion != schemaVersion {
+		t.Errorf("expected schema version = %d", diagnostics.ExpectedSchemaVersion)
+	}
+}
@@ -276,12 +276,27 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
+	cacheKey := GetPlatformCacheKey(platformID)
+
 	tx, err := cm.db.Begin()
 	if err != nil {
-		return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
+		return newCacheError("save", "games", cacheKey, err)
 	}
 	defer tx.Rollback()
 
+	if err := saveGamesTx(tx, cacheKey, games); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCacheError("save", "games", cacheKey, err)
+	}
+
+	return nil
+}
+
+// saveGamesTx writes games and their lookup rows within tx. The caller holds cm.mu.
+func saveGamesTx(tx *sql.Tx, cacheKey string, games []romm.Rom) error {
 	stmt, err := tx.Prepare(`
 		INSERT OR REPLACE INTO games (
 			id, platform_id, platform_fs_slug, name, fs_name, fs_name_no_ext, expected_basename,
@@ -292,18 +307,17 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
-		return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
+		return newCacheError("save", "games", cacheKey, err)
 	}
 	defer stmt.Close()
 
 	basenameStmt, err := tx.Prepare(`INSERT OR IGNORE INTO game_basenames (game_id, platform_fs_slug, basename) VALUES (?, ?, ?)`)
 	if err != nil {
-		return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
+		return newCacheError("save", "games", cacheKey, err)
 	}
 	defer basenameStmt.Close()
 
 	now := nowUTC()
-	cacheKey := GetPlatformCacheKey(platformID)
 	idCache := make(map[string]int64)
 
 	// Structures to hold our mega-batches
@@ -394,10 +408,6 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return newCacheError("save", "games", cacheKey, err)
-	}
-
 	return nil
 }
 
@@ -406,6 +416,10 @@ func (cm *Manager) GetCollectionGames(collection romm.Collection) ([]romm.Rom, e
 		return nil, ErrNotInitialized
 	}
 
+	if IsPlayHistoryCollection(collection) {
+		return cm.getPlayHistoryGames(collection)
+	}
+
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
@@ -777,6 +791,7 @@ type GameFilter struct {
 	MinSizeBytes         int64
 	MaxSizeBytes         int64
 	NameSearch           string
+	Sort                 GameSort
 }
 
 // HasActiveFilters returns true if any filter criteria are set.
@@ -1299,33 +1314,10 @@ func (cm *Manager) PurgeDeletedGames(validIDs []int) (int64, error) {
 	}
 	defer tx.Rollback()
 
-	if _, err := tx.Exec("CREATE TEMP TABLE _valid_game_ids (id INTEGER PRIMARY KEY)"); err != nil {
+	if err := createValidGameIDs(tx, validIDs); err != nil {
 		return 0, newCacheError("purge", "games", "", err)
 	}
 
-	const batchSize = 400
-	for i := 0; i < len(validIDs); i += batchSize {
-		end := i + batchSize
-		if end > len(validIDs) {
-			end = len(validIDs)
-		}
-		batch := validIDs[i:end]
-
-		query := "INSERT OR IGNORE INTO _valid_game_ids (id) VALUES "
-		args := make([]any, len(batch))
-		for j, id := range batch {
-			if j > 0 {
-				query += ", "
-			}
-			query += "(?)"
-			args[j] = id
-		}
-
-		if _, err := tx.Exec(query, args...); err != nil {
-			return 0, newCacheError("purge", "games", "", err)
-		}
-	}
-
 	// Clean up junction tables for deleted games
 	for _, table := range junctionTables {
 		if _, err := tx.Exec("DELETE FROM " + table + " WHERE game_id NOT IN (SELECT id FROM _valid_game_ids)"); err != nil {
@@ -1356,3 +1348,88 @@ func (cm *Manager) PurgeDeletedGames(validIDs []int) (int64, error) {
 
 	return deleted, nil
 }
+
+// PurgePlatformGames removes cached games of one platform whose IDs are not in
+// validIDs, the complete list of that platform's games on the server. Unlike
+// PurgeDeletedGames an empty list is honoured: the platform has no games left.
+func (cm *Manager) PurgePlatformGames(platformID int, validIDs []int) (int64, error) {
+	if cm == nil || !cm.initialized {
+		return 0, ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return 0, newCacheError("purge", "games", "", err)
+	}
+	defer tx.Rollback()
+
+	if err := createValidGameIDs(tx, validIDs); err != nil {
+		return 0, newCacheError("purge", "games", "", err)
+	}
+
+	stale := "SELECT id FROM games WHERE platform_id = ? AND id NOT IN (SELECT id FROM _valid_game_ids)"
+
+	for _, table := range junctionTables {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE game_id IN ("+stale+")", platformID); err != nil {
+			return 0, newCacheError("purge", "games", table, err)
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM game_collections WHERE game_id IN ("+stale+")", platformID); err != nil {
+		return 0, newCacheError("purge", "games", "game_collections", err)
+	}
+
+	result, err := tx.Exec("DELETE FROM games WHERE id IN ("+stale+")", platformID)
+	if err != nil {
+		return 0, newCacheError("purge", "games", "", err)
+	}
+
+	deleted, _ := result.RowsAffected()
+
+	tx.Exec("DROP TABLE IF EXISTS _valid_game_ids")
+
+	if err := tx.Commit(); err != nil {
+		return 0, newCacheError("purge", "games", "", err)
+	}
+
+	if deleted > 0 {
+		gaba.GetLogger().Debug("Purged deleted platform games from cache", "platform_id", platformID, "count", deleted)
+	}
+
+	return deleted, nil
+}
+
+// createValidGameIDs fills the _valid_game_ids temp table that the purges compare
+// cached games against.
+func createValidGameIDs(tx *sql.Tx, validIDs []int) error {
+	if _, err := tx.Exec("CREATE TEMP TABLE _valid_game_ids (id INTEGER PRIMARY KEY)"); err != nil {
+		return err
+	}
+
+	const batchSize = 400
+	for i := 0; i < len(validIDs); i += batchSize {
+		end := i + batchSize
+		if end > len(validIDs) {
+			end = len(validIDs)
+		}
+		batch := validIDs[i:end]
+
+		query := "INSERT OR IGNORE INTO _valid_game_ids (id) VALUES "
+		args := make([]any, len(batch))
+		for j, id := range batch {
+			if j > 0 {
+				query += ", "
+			}
+			query += "(?)"
+			args[j] = id
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -88,3 +88,46 @@ func TestBackfillMetadataJunctions_RebuildsFromDataJSON(t *testing.T) {
 		t.Errorf("after backfill, want companies [Nintendo], got %v", companies)
 	}
 }
+
+// A live refetch of one platform must drop that platform's games the server no longer
+// has, along with their junction rows, and leave every other platform alone.
+func TestPurgePlatformGames_OnlyTouchesThatPlatform(t *testing.T) {
+	cm := newTestManager(t)
+
+	if err := cm.SavePlatformGames(5, []romm.Rom{
+		{ID: 1, PlatformID: 5, PlatformFSSlug: "gba", Name: "Alpha",
+			Metadatum: romm.RomMetadata{Genres: []string{"Action"}}},
+		{ID: 2, PlatformID: 5, PlatformFSSlug: "gba", Name: "Beta",
+			Metadatum: romm.RomMetadata{Genres: []string{"Puzzle"}}},
+	}); err != nil {
+		t.Fatalf("save gba: %v", err)
+	}
+	if err := cm.SavePlatformGames(6, []romm.Rom{
+		{ID: 3, PlatformID: 6, PlatformFSSlug: "snes", Name: "Gamma"},
+	}); err != nil {
+		t.Fatalf("save snes: %v", err)
+	}
+
+	deleted, err := cm.PurgePlatformGames(5, []int{1})
+	if err != nil {
+		t.Fatalf("PurgePlatformGames: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted = %d, want 1", deleted)
+	}
+
+	if got := genreValues(t, cm, 5); len(got) != 1 || got[0] != "Action" {
+		t.Errorf("genres after purge = %v, want [Action]", got)
+	}
+	if games, err := cm.GetPlatformGames(6); err != nil || len(games) != 1 {
+		t.Errorf("other platform games = %v (err %v), want Gamma untouched", games, err)
+	}
+
+	// An empty list means the platform has no games left on the server.
+	if _, err := cm.PurgePlatformGames(5, nil); err != nil {
+		t.Fatalf("PurgePlatformGames(empty): %v", err)
+	}
+	if games, _ := cm.GetPlatformGames(5); len(games) != 0 {
+		t.Errorf("platform games after empty purge = %d, want 0", len(games))
+	}
+}
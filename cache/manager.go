@@ -255,10 +255,12 @@ func (cm *Manager) ClearMetadata() error {
 func (cm *Manager) ClearArtwork() {
 	logger := gaba.GetLogger()
 
-	artworkDir := GetArtworkCacheDir()
-	if fileutil.FileExists(artworkDir) {
-		os.RemoveAll(artworkDir)
+	for _, dir := range []string{GetArtworkCacheDir(), GetArtPiecesDir()} {
+		if fileutil.FileExists(dir) {
+			os.RemoveAll(dir)
+		}
 	}
+	cm.clearArtworkAccess()
 
 	logger.Info("Artwork cache cleared")
 }
@@ -445,6 +447,29 @@ func (cm *Manager) SyncPlatformGames(platforms []romm.Platform) (int, error) {
 	return totalGames, nil
 }
 
+// RefetchPlatformGames re-downloads every game of a platform and drops cached games
+// the server no longer has, then records the platform as changed for open game lists.
+func (cm *Manager) RefetchPlatformGames(platform romm.Platform) (int, error) {
+	if cm == nil || !cm.initialized {
+		return 0, ErrNotInitialized
+	}
+
+	var seen []int
+	count, err := cm.fetchPlatformGames(platform, &fetchOpts{seenIDs: &seen})
+	if err != nil {
+		cm.RecordPlatformSyncFailure(platform.ID)
+		return 0, err
+	}
+
+	if _, err := cm.PurgePlatformGames(platform.ID, seen); err != nil {
+		gaba.GetLogger().Debug("Failed to purge deleted platform games", "platform", platform.Name, "error", err)
+	}
+	cm.RecordPlatformSyncSuccess(platform.ID, count)
+	markPlatformChanged(platform.ID)
+
+	return count, nil
+}
+
 func getCacheDBPath() string {
 	wd, err := os.Getwd()
 	if err != nil {
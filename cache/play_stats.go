@@ -0,0 +1,312 @@
+package cache
+
+import (
+	"cmp"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"grout/internal/gamelist"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// PlayStats is the play history of one game, as recorded by the frontend.
+type PlayStats struct {
+	RomID          int
+	PlatformFSSlug string
+	PlayCount      int
+	LastPlayed     time.Time
+	Favorite       bool
+}
+
+// GameSort is the order the game list shows games in.
+type GameSort string
+
+const (
+	SortByName       GameSort = ""
+	SortByPlayCount  GameSort = "play_count"
+	SortByLastPlayed GameSort = "last_played"
+)
+
+// Virtual IDs of the collections built from the frontend's play history rather than RomM.
+const (
+	FavoritesCollectionID      = "grout-favorites"
+	RecentlyPlayedCollectionID = "grout-recently-played"
+)
+
+const recentlyPlayedLimit = 50
+
+// IsPlayHistoryCollection reports whether c is the Favorites or Recently Played view.
+func IsPlayHistoryCollection(c romm.Collection) bool {
+	return c.ID == 0 && (c.VirtualID == FavoritesCollectionID || c.VirtualID == RecentlyPlayedCollectionID)
+}
+
+// ImportPlayHistory reads play counts, last played times and favorites from the
+// gamelist.xml of each platform, in the background.
+func ImportPlayHistory(platforms []romm.Platform) {
+	go RefreshPlayHistory(platforms)
+}
+
+// RefreshPlayHistory re-reads the play history of every platform from its gamelist.xml.
+func RefreshPlayHistory(platforms []romm.Platform) {
+	cm := GetCacheManager()
+	if cm == nil || cm.config == nil {
+		return
+	}
+
+	imported := 0
+	for _, platform := range platforms {
+		gamelistDir := cm.config.GetPlatformGamelistDirectory(platform)
+		if gamelistDir == "" {
+			continue
+		}
+		n, err := cm.ImportPlayStats(platform.FSSlug, filepath.Join(gamelistDir, string(gamelist.GameListFileName)))
+		if err != nil {
+			gaba.GetLogger().Debug("Failed to import play history", "platform", platform.FSSlug, "error", err)
+			continue
+		}
+		imported += n
+	}
+	gaba.GetLogger().Debug("Imported play history", "games", imported)
+}
+
+// ImportPlayStats replaces the play history of a platform with the one in gamelistPath
+// and returns how many games it matched. Games are matched by their file name.
+func (cm *Manager) ImportPlayStats(platformFSSlug, gamelistPath string) (int, error) {
+	if cm == nil || !cm.initialized {
+		return 0, ErrNotInitialized
+	}
+
+	entries, err := gamelist.ReadPlayStats(gamelistPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var stats []PlayStats
+	for _, entry := range entries {
+		base := filepath.Base(filepath.FromSlash(entry.Path))
+		rom, err := cm.GetRomByFSLookup(platformFSSlug, strings.TrimSuffix(base, filepath.Ext(base)))
+		if err != nil {
+			continue
+		}
+		stats = append(stats, PlayStats{
+			RomID:          rom.ID,
+			PlatformFSSlug: platformFSSlug,
+			PlayCount:      entry.PlayCount,
+			LastPlayed:     entry.LastPlayed,
+			Favorite:       entry.Favorite,
+		})
+	}
+
+	if err := cm.savePlayStats(platformFSSlug, stats); err != nil {
+		return 0, err
+	}
+	return len(stats), nil
+}
+
+func (cm *Manager) savePlayStats(platformFSSlug string, stats []PlayStats) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return newCacheError("save", "play_stats", platformFSSlug, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM play_stats WHERE platform_fs_slug = ?`, platformFSSlug); err != nil {
+		return newCacheError("save", "play_stats", platformFSSlug, err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO play_stats (rom_id, platform_fs_slug, play_count, last_played, favorite, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return newCacheError("save", "play_stats", platformFSSlug, err)
+	}
+	defer stmt.Close()
+
+	now := nowUTC()
+	for _, s := range stats {
+		var lastPlayed sql.NullString
+		if !s.LastPlayed.IsZero() {
+			lastPlayed = sql.NullString{String: s.LastPlayed.UTC().Format(time.RFC3339), Valid: true}
+		}
+		if _, err := stmt.Exec(s.RomID, s.PlatformFSSlug, s.PlayCount, lastPlayed, s.Favorite, now); err != nil {
+			return newCacheError("save", "play_stats", platformFSSlug, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCacheError("save", "play_stats", platformFSSlug, err)
+	}
+	return nil
+}
+
+// GetPlayStats returns the imported play history of the given games, keyed by rom ID.
+// Games that were never played or favorited are left out.
+func (cm *Manager) GetPlayStats(romIDs []int) (map[int]PlayStats, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	result := make(map[int]PlayStats)
+	if len(romIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(romIDs))
+	args := make([]interface{}, len(romIDs))
+	for i, id := range romIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	rows, err := cm.db.Query(`
+		SELECT rom_id, platform_fs_slug, play_count, COALESCE(last_played, ''), favorite
+		FROM play_stats WHERE rom_id IN (`+strings.Join(placeholders, ",")+`)
+	`, args...)
+	if err != nil {
+		return nil, newCacheError("get", "play_stats", "", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanPlayStats(rows)
+		if err != nil {
+			return nil, newCacheError("get", "play_stats", "", err)
+		}
+		result[s.RomID] = s
+	}
+	return result, rows.Err()
+}
+
+// GetFavoriteGames returns the games favorited in the frontend, by name.
+func (cm *Manager) GetFavoriteGames() ([]romm.Rom, error) {
+	ids, err := cm.queryPlayStatsIDs(`SELECT rom_id FROM play_stats WHERE favorite = 1`)
+	if err != nil {
+		return nil, err
+	}
+	return cm.GetGamesByIDs(ids)
+}
+
+// GetRecentlyPlayedGames returns up to limit games, most recently played first.
+func (cm *Manager) GetRecentlyPlayedGames(limit int) ([]romm.Rom, error) {
+	ids, err := cm.queryPlayStatsIDs(`SELECT rom_id FROM play_stats WHERE last_played IS NOT NULL ORDER BY last_played DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	games, err := cm.GetGamesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// GetGamesByIDs sorts by name; restore the play order.
+	byID := make(map[int]romm.Rom, len(games))
+	for _, g := range games {
+		byID[g.ID] = g
+	}
+	ordered := make([]romm.Rom, 0, len(games))
+	for _, id := range ids {
+		if g, ok := byID[id]; ok {
+			ordered = append(ordered, g)
+		}
+	}
+	return ordered, nil
+}
+
+// PlayHistoryCounts returns how many games are favorited and how many have been played.
+func (cm *Manager) PlayHistoryCounts() (favorites, played int) {
+	if cm == nil || !cm.initialized {
+		return 0, 0
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	cm.db.QueryRow(`
+		SELECT COALESCE(SUM(favorite), 0), COALESCE(SUM(play_count > 0 OR last_played IS NOT NULL), 0)
+		FROM play_stats
+	`).Scan(&favorites, &played)
+	return favorites, played
+}
+
+// SortByPlayStats orders games by how often or how recently they were played, most first.
+// Games without play history keep their order after the played ones.
+func (cm *Manager) SortByPlayStats(games []romm.Rom, by GameSort) {
+	if by == SortByName || len(games) < 2 {
+		return
+	}
+
+	ids := make([]int, len(games))
+	for i, g := range games {
+		ids[i] = g.ID
+	}
+	stats, err := cm.GetPlayStats(ids)
+	if err != nil {
+		return
+	}
+
+	slices.SortStableFunc(games, func(a, b romm.Rom) int {
+		sa, sb := stats[a.ID], stats[b.ID]
+		if by == SortByPlayCount {
+			if c := cmp.Compare(sb.PlayCount, sa.PlayCount); c != 0 {
+				return c
+			}
+		}
+		return sb.LastPlayed.Compare(sa.LastPlayed)
+	})
+}
+
+func (cm *Manager) getPlayHistoryGames(c romm.Collection) ([]romm.Rom, error) {
+	if c.VirtualID == FavoritesCollectionID {
+		return cm.GetFavoriteGames()
+	}
+	return cm.GetRecentlyPlayedGames(recentlyPlayedLimit)
+}
+
+func (cm *Manager) queryPlayStatsIDs(query string, args ...interface{}) ([]int, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	rows, err := cm.db.Query(query, args...)
+	if err != nil {
+		return nil, newCacheError("get", "play_stats", "", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, newCacheError("get", "play_stats", "", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func scanPlayStats(rows *sql.Rows) (PlayStats, error) {
+	var s PlayStats
+	var lastPlayed string
+	if err := rows.Scan(&s.RomID, &s.PlatformFSSlug, &s.PlayCount, &lastPlayed, &s.Favorite); err != nil {
+		return s, err
+	}
+	if lastPlayed != "" {
+		s.LastPlayed, _ = time.Parse(time.RFC3339, lastPlayed)
+	}
+	return s, nil
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"grout/romm"
+)
+
+func TestImportPlayStats(t *testing.T) {
+	cm := newTestManager(t)
+	games := []romm.Rom{
+		{ID: 1, PlatformID: 5, PlatformFSSlug: "snes", Name: "Zelda", FsName: "Zelda (USA).sfc", FsNameNoExt: "Zelda (USA)"},
+		{ID: 2, PlatformID: 5, PlatformFSSlug: "snes", Name: "Mario", FsName: "Mario (USA).sfc", FsNameNoExt: "Mario (USA)"},
+		{ID: 3, PlatformID: 5, PlatformFSSlug: "snes", Name: "Metroid", FsName: "Metroid (USA).sfc", FsNameNoExt: "Metroid (USA)"},
+	}
+	if err := cm.SavePlatformGames(5, games); err != nil {
+		t.Fatalf("save games: %v", err)
+	}
+
+	gamelistPath := filepath.Join(t.TempDir(), "gamelist.xml")
+	err := os.WriteFile(gamelistPath, []byte(`<gameList>
+	<game><path>./Zelda (USA).sfc</path><playcount>3</playcount><lastplayed>20260101T100000</lastplayed></game>
+	<game><path>./Mario (USA).sfc</path><favorite>true</favorite><lastplayed>20260201T100000</lastplayed></game>
+	<game><path>./Unknown.sfc</path><playcount>9</playcount></game>
+</gameList>`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := cm.ImportPlayStats("snes", gamelistPath)
+	if err != nil {
+		t.Fatalf("ImportPlayStats: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("imported %d games, want 2", n)
+	}
+
+	stats, err := cm.GetPlayStats([]int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("GetPlayStats: %v", err)
+	}
+	if stats[1].PlayCount != 3 || !stats[2].Favorite {
+		t.Errorf("stats = %+v", stats)
+	}
+	if _, ok := stats[3]; ok {
+		t.Error("unplayed game has play stats")
+	}
+
+	recent, err := cm.GetRecentlyPlayedGames(10)
+	if err != nil {
+		t.Fatalf("GetRecentlyPlayedGames: %v", err)
+	}
+	if len(recent) != 2 || recent[0].ID != 2 || recent[1].ID != 1 {
+		t.Errorf("recently played = %v, want Mario then Zelda", recent)
+	}
+
+	favorites, err := cm.GetFavoriteGames()
+	if err != nil {
+		t.Fatalf("GetFavoriteGames: %v", err)
+	}
+	if len(favorites) != 1 || favorites[0].ID != 2 {
+		t.Errorf("favorites = %v, want Mario", favorites)
+	}
+
+	if favs, played := cm.PlayHistoryCounts(); favs != 1 || played != 2 {
+		t.Errorf("PlayHistoryCounts = %d, %d; want 1, 2", favs, played)
+	}
+
+	recentView, err := cm.GetCollectionGames(romm.Collection{VirtualID: RecentlyPlayedCollectionID})
+	if err != nil || len(recentView) != 2 {
+		t.Errorf("Recently Played view = %v, %v; want both played games", recentView, err)
+	}
+}
+
+func TestSortByPlayStats(t *testing.T) {
+	cm := newTestManager(t)
+	last := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	err := cm.savePlayStats("snes", []PlayStats{
+		{RomID: 1, PlatformFSSlug: "snes", PlayCount: 2, LastPlayed: last},
+		{RomID: 2, PlatformFSSlug: "snes", PlayCount: 5, LastPlayed: last.AddDate(0, -1, 0)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(games []romm.Rom) []int {
+		var out []int
+		for _, g := range games {
+			out = append(out, g.ID)
+		}
+		return out
+	}
+	games := func() []romm.Rom { return []romm.Rom{{ID: 3}, {ID: 1}, {ID: 4}, {ID: 2}} }
+
+	byCount := games()
+	cm.SortByPlayStats(byCount, SortByPlayCount)
+	if got := ids(byCount); !slices.Equal(got, []int{2, 1, 3, 4}) {
+		t.Errorf("by play count = %v, want [2 1 3 4]", got)
+	}
+
+	byLast := games()
+	cm.SortByPlayStats(byLast, SortByLastPlayed)
+	if got := ids(byLast); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("by last played = %v, want [1 2 3 4]", got)
+	}
+}
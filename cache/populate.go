@@ -87,49 +87,29 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 		cm.RecordRefreshTime(MetaKeyPlatformsRefreshedAt)
 	}
 
-	totalExpectedGames := int64(0)
-	for _, p := range platforms {
-		totalExpectedGames += int64(p.ROMCount)
-	}
-	if totalExpectedGames == 0 {
-		totalExpectedGames = int64(len(platforms))
-	}
-
 	// Progress: games 0-85%, collections 85-98%, done 100%
-	gamesFetched := &atomic.Int64{}
-	updateProgress := func(count int) {
-		if progress != nil {
-			fetched := gamesFetched.Add(int64(count))
-			pct := float64(fetched) / float64(totalExpectedGames) * 0.85
-			if pct > 0.85 {
-				pct = 0.85
-			}
-			progress.Store(pct)
-		}
-	}
+	gamesProgress := newPopulateProgress(progress, 0.85, platforms)
 
 	var firstErr error
+	gamesUpdated := 0
 
+	limits := populateLimitsFor(totalMemory())
+	outcomes, peakPages := cm.fetchPlatformsPipelined(client, platforms, updatedAfter, gamesProgress, limits)
+	logger.Debug("Fetched platform games", "workers", limits.workers, "batch_pages", limits.batchPages, "peak_pages", peakPages)
 	for _, p := range platforms {
-		count, err := cm.fetchPlatformGames(p, &fetchOpts{
-			client:       client,
-			onProgress:   updateProgress,
-			updatedAfter: updatedAfter,
-		})
-
-		if err != nil {
-			logger.Error("Failed to fetch/save platform games", "platformID", p.ID, "error", err)
+		outcome := outcomes[p.ID]
+		if outcome.err != nil {
+			logger.Error("Failed to fetch/save platform games", "platformID", p.ID, "error", outcome.err)
 			cm.RecordPlatformSyncFailure(p.ID)
 			if firstErr == nil {
-				firstErr = err
+				firstErr = outcome.err
 			}
-		} else {
-			cm.RecordPlatformSyncSuccess(p.ID, count)
+			continue
 		}
 
-		// Aggressively free memory after saving each platform
-		// Prevents OOM crashes on 128MB devices
-		runtime.GC()
+		cm.RecordPlatformSyncSuccess(p.ID, outcome.count)
+		gamesUpdated += outcome.count
+		logger.Debug("Cached platform games", "platform", p.Name, "count", outcome.count, "updated_after", updatedAfter)
 	}
 
 	// Record refresh time
@@ -151,16 +131,16 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 		progress.Store(1.0)
 	}
 
-	stats.GamesUpdated = int(gamesFetched.Load())
+	stats.GamesUpdated = gamesUpdated
 	logger.Debug("Cache population completed", "platforms", stats.Platforms, "games", stats.GamesUpdated)
 	return stats, firstErr
 }
 
 type fetchOpts struct {
 	client        *romm.Client    // Reusable HTTP client
-	onProgress    func(count int) // Called with count of games fetched (for batch progress)
 	onPctProgress *atomic.Float64 // Set with percentage 0.0-1.0 (for UI progress bars)
 	updatedAfter  string
+	seenIDs       *[]int // Collects the IDs of every fetched game when set
 }
 
 func (cm *Manager) fetchPlatformGames(platform romm.Platform, opts *fetchOpts) (int, error) {
@@ -209,11 +189,13 @@ func (cm *Manager) fetchPlatformGames(platform romm.Platform, opts *fetchOpts) (
 				return 0, err
 			}
 			totalSaved += len(res.Items)
+			if opts.seenIDs != nil {
+				for _, rom := range res.Items {
+					*opts.seenIDs = append(*opts.seenIDs, rom.ID)
+				}
+			}
 		}
 
-		if opts.onProgress != nil && len(res.Items) > 0 {
-			opts.onProgress(len(res.Items))
-		}
 		if opts.onPctProgress != nil && expectedTotal > 0 {
 			pct := float64(totalSaved) / float64(expectedTotal)
 			if pct > 1.0 {
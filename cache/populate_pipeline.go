@@ -0,0 +1,422 @@
+package cache
+
+import (
+	"bufio"
+	"grout/romm"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"go.uber.org/atomic"
+)
+
+// populateLimits bounds how many decoded pages population holds in memory at once: one
+// per fetcher, waiting for the writer to take it, plus the writer's batch. That is at
+// most workers+batchPages pages.
+type populateLimits struct {
+	// workers is how many pages are fetched at once.
+	workers int
+	// batchPages is how many pages the writer commits in one transaction when fetchers
+	// are ahead of it.
+	batchPages int
+}
+
+// populateLimitsFor picks the limits for a device with totalMemory bytes of RAM, or
+// unknown RAM when it is 0. Handhelds with 128-256MB get a couple of fetchers and a
+// page per transaction.
+func populateLimitsFor(totalMemory uint64) populateLimits {
+	switch {
+	case totalMemory == 0:
+		return populateLimits{workers: MaxConcurrentPlatformFetches, batchPages: 4}
+	case totalMemory <= 256<<20:
+		return populateLimits{workers: 2, batchPages: 1}
+	case totalMemory <= 512<<20:
+		return populateLimits{workers: 4, batchPages: 2}
+	case totalMemory <= 1<<30:
+		return populateLimits{workers: 6, batchPages: 4}
+	default:
+		return populateLimits{workers: MaxConcurrentPlatformFetches, batchPages: 4}
+	}
+}
+
+// totalMemory returns the device's RAM from /proc/meminfo, or 0 when it can't be read.
+func totalMemory() uint64 {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0
+			}
+			return kb << 10
+		}
+	}
+	return 0
+}
+
+// pageJob is one page of one platform's games.
+type pageJob struct {
+	platform romm.Platform
+	offset   int
+}
+
+// pageResult tells the coordinator how a page fetch went.
+type pageResult struct {
+	job   pageJob
+	total int
+	err   error
+}
+
+// gamePage is a fetched page on its way to the writer.
+type gamePage struct {
+	platform romm.Platform
+	games    []romm.Rom
+}
+
+// pageGauge counts the decoded pages held in memory and the most held at once.
+type pageGauge struct {
+	held atomic.Int32
+	peak atomic.Int32
+}
+
+func (g *pageGauge) add(delta int) {
+	n := g.held.Add(int32(delta))
+	for {
+		peak := g.peak.Load()
+		if n <= peak || g.peak.CompareAndSwap(peak, n) {
+			return
+		}
+	}
+}
+
+// platformOutcome is how populating one platform went.
+type platformOutcome struct {
+	count int
+	err   error
+}
+
+// fetchPlatformsPipelined fetches the games of all platforms with a bounded pool of page
+// fetchers feeding a single writer. The first page of each platform is fetched first;
+// its total decides which further pages are queued. A platform whose fetch or write
+// fails stops fetching, while the others carry on. It also returns the most decoded
+// pages held at once.
+func (cm *Manager) fetchPlatformsPipelined(client *romm.Client, platforms []romm.Platform, updatedAfter string, progress *populateProgress, limits populateLimits) (map[int]platformOutcome, int) {
+	workers := max(1, min(limits.workers, len(platforms)))
+
+	jobs := make(chan pageJob)
+	results := make(chan pageResult)
+	// Unbuffered, so a fetcher holds its page until the writer takes it instead of
+	// fetching ahead into a queue.
+	pages := make(chan gamePage)
+	gauge := &pageGauge{}
+
+	var fetchers sync.WaitGroup
+	for range workers {
+		fetchers.Add(1)
+		go func() {
+			defer fetchers.Done()
+			for job := range jobs {
+				results <- cm.fetchPage(client, job, updatedAfter, pages, gauge)
+			}
+		}()
+	}
+
+	writer := newPageWriter(cm, progress, max(1, limits.batchPages), gauge)
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		writer.run(pages)
+	}()
+
+	queue := make([]pageJob, 0, len(platforms))
+	for _, p := range platforms {
+		queue = append(queue, pageJob{platform: p})
+	}
+	fetchErrs := make(map[int]error)
+
+	inFlight := 0
+	for len(queue) > 0 || inFlight > 0 {
+		// Skip the remaining pages of platforms that already failed.
+		for len(queue) > 0 && (fetchErrs[queue[0].platform.ID] != nil || writer.failed(queue[0].platform.ID)) {
+			queue = queue[1:]
+		}
+
+		var send chan<- pageJob
+		var next pageJob
+		if len(queue) > 0 {
+			send = jobs
+			next = queue[0]
+		} else if inFlight == 0 {
+			break
+		}
+
+		select {
+		case send <- next:
+			queue = queue[1:]
+			inFlight++
+
+		case res := <-results:
+			inFlight--
+			platformID := res.job.platform.ID
+			if res.err != nil {
+				if fetchErrs[platformID] == nil {
+					gaba.GetLogger().Error("Failed to fetch games",
+						"platform", res.job.platform.Name,
+						"offset", res.job.offset,
+						"error", res.err)
+					fetchErrs[platformID] = res.err
+				}
+				continue
+			}
+			if res.job.offset == 0 {
+				progress.setTotal(platformID, res.total)
+				for offset := DefaultRomPageSize; offset < res.total; offset += DefaultRomPageSize {
+					queue = append(queue, pageJob{platform: res.job.platform, offset: offset})
+				}
+			}
+		}
+	}
+
+	close(jobs)
+	fetchers.Wait()
+	close(pages)
+	<-writerDone
+
+	outcomes := make(map[int]platformOutcome, len(platforms))
+	for _, p := range platforms {
+		outcome := platformOutcome{count: writer.written[p.ID], err: fetchErrs[p.ID]}
+		if outcome.err == nil {
+			outcome.err = writer.errs[p.ID]
+		}
+		if outcome.err != nil {
+			progress.setTotal(p.ID, outcome.count)
+		}
+		outcomes[p.ID] = outcome
+	}
+	return outcomes, int(gauge.peak.Load())
+}
+
+// fetchPage fetches one page and hands its games to the writer.
+func (cm *Manager) fetchPage(client *romm.Client, job pageJob, updatedAfter string, pages chan<- gamePage, gauge *pageGauge) pageResult {
+	res, err := client.GetRoms(romm.GetRomsQuery{
+		PlatformIDs:  []int{job.platform.ID},
+		Offset:       job.offset,
+		Limit:        DefaultRomPageSize,
+		UpdatedAfter: updatedAfter,
+		WithFiles:    true,
+	})
+	if err != nil {
+		return pageResult{job: job, err: err}
+	}
+
+	if len(res.Items) > 0 {
+		gauge.add(1)
+		pages <- gamePage{platform: job.platform, games: res.Items}
+	}
+	return pageResult{job: job, total: res.Total}
+}
+
+// pageWriter is the only goroutine writing games during population. It commits the
+// pages waiting for it in one transaction.
+type pageWriter struct {
+	cm         *Manager
+	progress   *populateProgress
+	batchPages int
+	gauge      *pageGauge
+
+	mu      sync.Mutex
+	written map[int]int
+	errs    map[int]error
+}
+
+func newPageWriter(cm *Manager, progress *populateProgress, batchPages int, gauge *pageGauge) *pageWriter {
+	return &pageWriter{
+		cm:         cm,
+		progress:   progress,
+		batchPages: batchPages,
+		gauge:      gauge,
+		written:    make(map[int]int),
+		errs:       make(map[int]error),
+	}
+}
+
+func (w *pageWriter) failed(platformID int) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.errs[platformID] != nil
+}
+
+func (w *pageWriter) run(pages <-chan gamePage) {
+	for page := range pages {
+		batch := []gamePage{page}
+
+	collect:
+		for len(batch) < w.batchPages {
+			select {
+			case next, ok := <-pages:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, next)
+			default:
+				break collect
+			}
+		}
+
+		w.write(batch)
+		w.gauge.add(-len(batch))
+
+		// Aggressively free memory after each batch
+		// Prevents OOM crashes on 128MB devices
+		runtime.GC()
+	}
+}
+
+// write commits batch in one transaction. If that fails, the pages are written one at
+// a time so only the platforms with a bad page are marked failed.
+func (w *pageWriter) write(batch []gamePage) {
+	// Pages of platforms that failed earlier are dropped rather than written.
+	kept := batch[:0]
+	for _, page := range batch {
+		if !w.failed(page.platform.ID) {
+			kept = append(kept, page)
+		}
+	}
+	batch = kept
+	if len(batch) == 0 {
+		return
+	}
+
+	err := w.cm.saveGamePages(batch)
+	if err == nil {
+		for _, page := range batch {
+			w.record(page, nil)
+		}
+		return
+	}
+	if len(batch) == 1 {
+		w.record(batch[0], err)
+		return
+	}
+
+	gaba.GetLogger().Debug("Batched write failed, writing pages one by one", "pages", len(batch), "error", err)
+	for _, page := range batch {
+		if w.failed(page.platform.ID) {
+			continue
+		}
+		w.record(page, w.cm.SavePlatformGames(page.platform.ID, page.games))
+	}
+}
+
+func (w *pageWriter) record(page gamePage, err error) {
+	w.mu.Lock()
+	if err != nil {
+		gaba.GetLogger().Error("Failed to save platform games", "platform", page.platform.Name, "error", err)
+		w.errs[page.platform.ID] = err
+		w.mu.Unlock()
+		return
+	}
+	w.written[page.platform.ID] += len(page.games)
+	w.mu.Unlock()
+
+	w.progress.addWritten(page.platform.ID, len(page.games))
+}
+
+// saveGamePages writes pages of any platforms in a single transaction.
+func (cm *Manager) saveGamePages(pages []gamePage) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return newCacheError("save", "games", "", err)
+	}
+	defer tx.Rollback()
+
+	for _, page := range pages {
+		if err := saveGamesTx(tx, GetPlatformCacheKey(page.platform.ID), page.games); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCacheError("save", "games", "", err)
+	}
+	return nil
+}
+
+// populateProgress reports games written against games expected on the games part of
+// the progress bar. Each platform is expected to hold its ROM count until its first
+// page reports the real total, which for an incremental update is far smaller.
+type populateProgress struct {
+	progress *atomic.Float64
+	share    float64
+
+	mu       sync.Mutex
+	expected map[int]int
+	written  map[int]int
+}
+
+func newPopulateProgress(progress *atomic.Float64, share float64, platforms []romm.Platform) *populateProgress {
+	p := &populateProgress{
+		progress: progress,
+		share:    share,
+		expected: make(map[int]int, len(platforms)),
+		written:  make(map[int]int, len(platforms)),
+	}
+	for _, platform := range platforms {
+		// Platforms without a ROM count still take a sliver of the bar.
+		p.expected[platform.ID] = max(platform.ROMCount, 1)
+	}
+	return p
+}
+
+func (p *populateProgress) setTotal(platformID, total int) {
+	p.mu.Lock()
+	p.expected[platformID] = total
+	p.mu.Unlock()
+	p.store()
+}
+
+func (p *populateProgress) addWritten(platformID, count int) {
+	p.mu.Lock()
+	p.written[platformID] += count
+	p.mu.Unlock()
+	p.store()
+}
+
+// fraction is the share of expected games written, between 0 and 1.
+func (p *populateProgress) fraction() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	expected, written := 0, 0
+	for id, n := range p.expected {
+		expected += max(n, p.written[id])
+		written += p.written[id]
+	}
+	if expected == 0 {
+		return 1
+	}
+	return float64(written) / float64(expected)
+}
+
+func (p *populateProgress) store() {
+	if p.progress != nil {
+		p.progress.Store(p.fraction() * p.share)
+	}
+}
@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"grout/romm"
+
+	"go.uber.org/atomic"
+)
+
+func TestFetchPlatformsPipelined(t *testing.T) {
+	cm := newTestManager(t)
+
+	platforms := []romm.Platform{
+		{ID: 1, Name: "Big", ROMCount: 600},
+		{ID: 2, Name: "Broken", ROMCount: 600},
+	}
+	for id := 3; id <= 14; id++ {
+		platforms = append(platforms, romm.Platform{ID: id, Name: "Small " + strconv.Itoa(id), ROMCount: 10})
+	}
+	totals := make(map[int]int)
+	for _, p := range platforms {
+		totals[p.ID] = p.ROMCount
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Inc()
+		defer inFlight.Dec()
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		query := r.URL.Query()
+		platformID, _ := strconv.Atoi(query.Get("platform_ids"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		// One platform breaks after its first page; the others must not notice.
+		if platformID == 2 && offset > 0 {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+
+		// Give the fetches a chance to overlap.
+		time.Sleep(5 * time.Millisecond)
+
+		total := totals[platformID]
+		end := min(offset+limit, total)
+		items := make([]romm.Rom, 0, max(end-offset, 0))
+		for i := offset; i < end; i++ {
+			items = append(items, romm.Rom{
+				ID:             platformID*10000 + i,
+				PlatformID:     platformID,
+				PlatformFSSlug: "p" + strconv.Itoa(platformID),
+				Name:           "Game " + strconv.Itoa(i),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(romm.PaginatedRoms{
+			Items: items, Total: total, Limit: limit, Offset: offset,
+		}); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	progress := atomic.NewFloat64(0)
+	outcomes, _ := cm.fetchPlatformsPipelined(romm.NewClient(server.URL), platforms, "", newPopulateProgress(progress, 0.85, platforms), populateLimitsFor(0))
+
+	for _, p := range platforms {
+		outcome := outcomes[p.ID]
+		games, err := cm.GetPlatformGames(p.ID)
+		if err != nil {
+			t.Fatalf("GetPlatformGames(%d): %v", p.ID, err)
+		}
+
+		if p.ID == 2 {
+			if outcome.err == nil {
+				t.Error("broken platform reported success")
+			}
+			if outcome.count != DefaultRomPageSize || len(games) != DefaultRomPageSize {
+				t.Errorf("broken platform: count = %d, cached = %d; want its first page only", outcome.count, len(games))
+			}
+			continue
+		}
+
+		if outcome.err != nil {
+			t.Errorf("platform %d: %v", p.ID, outcome.err)
+		}
+		if outcome.count != p.ROMCount || len(games) != p.ROMCount {
+			t.Errorf("platform %d: count = %d, cached = %d, want %d", p.ID, outcome.count, len(games), p.ROMCount)
+		}
+	}
+
+	if got := maxInFlight.Load(); got > MaxConcurrentPlatformFetches {
+		t.Errorf("max concurrent fetches = %d, want at most %d", got, MaxConcurrentPlatformFetches)
+	}
+
+	// Failed platforms count as done, so the games part of the bar still fills.
+	if got := progress.Load(); math.Abs(got-0.85) > 1e-9 {
+		t.Errorf("progress = %v, want 0.85", got)
+	}
+}
+
+func TestFetchPlatformsPipelinedBoundsPagesHeld(t *testing.T) {
+	cm := newTestManager(t)
+
+	var platforms []romm.Platform
+	for id := 1; id <= 6; id++ {
+		platforms = append(platforms, romm.Platform{ID: id, Name: "Platform " + strconv.Itoa(id), ROMCount: 4 * DefaultRomPageSize})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		platformID, _ := strconv.Atoi(query.Get("platform_ids"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		items := make([]romm.Rom, 0, limit)
+		for i := offset; i < offset+limit; i++ {
+			items = append(items, romm.Rom{ID: platformID*10000 + i, PlatformID: platformID, Name: "Game " + strconv.Itoa(i)})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(romm.PaginatedRoms{
+			Items: items, Total: 4 * DefaultRomPageSize, Limit: limit, Offset: offset,
+		}); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	limits := populateLimits{workers: 3, batchPages: 2}
+	outcomes, peak := cm.fetchPlatformsPipelined(romm.NewClient(server.URL), platforms, "", newPopulateProgress(nil, 0.85, platforms), limits)
+
+	for _, p := range platforms {
+		if outcome := outcomes[p.ID]; outcome.err != nil || outcome.count != p.ROMCount {
+			t.Errorf("platform %d: count = %d, err = %v", p.ID, outcome.count, outcome.err)
+		}
+	}
+	if peak < 1 || peak > limits.workers+limits.batchPages {
+		t.Errorf("peak pages held = %d, want between 1 and %d", peak, limits.workers+limits.batchPages)
+	}
+}
+
+func TestPopulateLimitsFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		memory uint64
+		want   populateLimits
+	}{
+		{"unknown", 0, populateLimits{workers: MaxConcurrentPlatformFetches, batchPages: 4}},
+		{"128MB", 128 << 20, populateLimits{workers: 2, batchPages: 1}},
+		{"512MB", 512 << 20, populateLimits{workers: 4, batchPages: 2}},
+		{"1GB", 1 << 30, populateLimits{workers: 6, batchPages: 4}},
+		{"4GB", 4 << 30, populateLimits{workers: MaxConcurrentPlatformFetches, batchPages: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := populateLimitsFor(tt.memory); got != tt.want {
+				t.Errorf("populateLimitsFor(%d) = %+v, want %+v", tt.memory, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPopulateProgressUsesReportedTotals(t *testing.T) {
+	progress := atomic.NewFloat64(0)
+	p := newPopulateProgress(progress, 0.85, []romm.Platform{
+		{ID: 1, ROMCount: 1000},
+		{ID: 2, ROMCount: 0},
+	})
+
+	// An incremental update reports far fewer games than the platform holds.
+	p.setTotal(1, 20)
+	p.addWritten(1, 20)
+	if got := progress.Load(); math.Abs(got-0.85*20/21) > 1e-9 {
+		t.Errorf("progress = %v, want %v while platform 2 is pending", got, 0.85*20/21)
+	}
+
+	p.setTotal(2, 0)
+	if got := progress.Load(); math.Abs(got-0.85) > 1e-9 {
+		t.Errorf("progress = %v, want 0.85 once every platform is done", got)
+	}
+
+	// More games than reported never push the bar past its share.
+	p.addWritten(2, 5)
+	if got := progress.Load(); got > 0.85+1e-9 {
+		t.Errorf("progress = %v, want at most 0.85", got)
+	}
+}
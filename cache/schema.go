@@ -12,7 +12,7 @@ import (
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
 
-const schemaVersion = 14
+const schemaVersion = 16
 
 // nowUTC returns the current UTC time formatted as RFC3339 for consistent datetime storage
 func nowUTC() string {
@@ -597,6 +597,47 @@ func createTables(db *sql.DB) error {
 		return err
 	}
 
+	// Last access time and size of each cached cover, for evicting the least recently
+	// used ones once the artwork cache outgrows its budget (added in v15).
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS artwork_access (
+			platform_fs_slug TEXT NOT NULL,
+			rom_id INTEGER NOT NULL,
+			size INTEGER NOT NULL DEFAULT 0,
+			last_access TEXT NOT NULL,
+			PRIMARY KEY (platform_fs_slug, rom_id)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_artwork_access_last_access ON artwork_access(last_access)`)
+	if err != nil {
+		return err
+	}
+
+	// Play count, last played time and favorite flag imported from the frontend's
+	// gamelist.xml (added in v16).
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS play_stats (
+			rom_id INTEGER PRIMARY KEY,
+			platform_fs_slug TEXT NOT NULL,
+			play_count INTEGER NOT NULL DEFAULT 0,
+			last_played TEXT,
+			favorite INTEGER NOT NULL DEFAULT 0,
+			updated_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_play_stats_last_played ON play_stats(last_played)`)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(`
 		INSERT OR REPLACE INTO cache_metadata (key, value, updated_at)
 		VALUES ('schema_version', ?, ?)
@@ -52,3 +52,12 @@ func GetManualDirectory(romDir string) string {
 func GetBezelDirectory(romDir string) string {
 	return filepath.Join(romDir, "bezels")
 }
+
+// GetRetroArchDirectory returns the directory holding RetroArch's playlists and thumbnails.
+func GetRetroArchDirectory() string {
+	return "/home/ark/.config/retroarch"
+}
+
+func GetCoreDirectory() string {
+	return filepath.Join(GetRetroArchDirectory(), "cores")
+}
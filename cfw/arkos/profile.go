@@ -0,0 +1,81 @@
+package arkos
+
+import (
+	"grout/cfw/profile"
+	"grout/internal/multidisc"
+	"path/filepath"
+)
+
+// Profile describes ArkOS to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "ARKOS"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+// SaveDirectories returns the platform map, as ArkOS keeps saves alongside the ROMs.
+func (Profile) SaveDirectories() map[string][]string {
+	return Platforms
+}
+
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{
+		Box:       GetArtDirectory(romDir),
+		Marquee:   GetArtDirectory(romDir),
+		Video:     GetVideoDirectory(romDir),
+		Thumbnail: GetArtDirectory(romDir),
+		Bezel:     GetBezelDirectory(romDir),
+		Manual:    GetManualDirectory(romDir),
+		Boxback:   GetArtDirectory(romDir),
+		Fanart:    GetArtDirectory(romDir),
+	}
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataGamelist
+}
+
+// Install leaves out the update asset and launch script, as ArkOS is updated by hand.
+func (Profile) Install() profile.Install {
+	return profile.Install{Depth: 2}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return false
+}
+
+func (Profile) GroutGamelist() (string, string) {
+	return GetGroutGamelist(), "./Grout.sh"
+}
+
+func (Profile) MultiDiscLayout() multidisc.Layout {
+	return multidisc.LayoutHiddenDir
+}
+
+func (Profile) RetroArchDirectories() (profile.RetroArchDirectories, bool) {
+	return profile.RetroArchDirectories{
+		Playlists:  filepath.Join(GetRetroArchDirectory(), "playlists"),
+		Thumbnails: filepath.Join(GetRetroArchDirectory(), "thumbnails"),
+		Cores:      GetCoreDirectory(),
+	}, true
+}
+
+func (Profile) InputMapping() ([]byte, error) {
+	return GetInputMappingBytes()
+}
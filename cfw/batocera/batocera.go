@@ -52,3 +52,12 @@ func GetBezelDirectory(romDir string) string {
 func GetManualDirectory(romDir string) string {
 	return filepath.Join(romDir, "manuals")
 }
+
+// GetRetroArchDirectory returns the directory holding RetroArch's playlists and thumbnails.
+func GetRetroArchDirectory() string {
+	return filepath.Join(GetBasePath(), "system", ".config", "retroarch")
+}
+
+func GetCoreDirectory() string {
+	return "/usr/lib/libretro"
+}
@@ -0,0 +1,95 @@
+package batocera
+
+import (
+	"grout/cfw/profile"
+	"grout/internal/multidisc"
+	"path/filepath"
+	"runtime"
+)
+
+// Profile describes Batocera to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "BATOCERA"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+// SaveDirectories returns the platform map, as Batocera keeps saves alongside the ROMs.
+func (Profile) SaveDirectories() map[string][]string {
+	return Platforms
+}
+
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{
+		Box:       GetArtDirectory(romDir),
+		Marquee:   GetArtDirectory(romDir),
+		Video:     GetVideoDirectory(romDir),
+		Thumbnail: GetArtDirectory(romDir),
+		Bezel:     GetBezelDirectory(romDir),
+		Manual:    GetManualDirectory(romDir),
+		Boxback:   GetArtDirectory(romDir),
+		Fanart:    GetArtDirectory(romDir),
+	}
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataGamelist
+}
+
+func (Profile) Install() profile.Install {
+	return profile.Install{Asset: updateAsset(), LaunchScript: "Grout.sh", Depth: 2}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return false
+}
+
+func (Profile) GroutGamelist() (string, string) {
+	return GetGroutGamelist(), "./Grout/Grout.sh"
+}
+
+// updateAsset returns the release asset built for this machine's architecture.
+func updateAsset() string {
+	switch runtime.GOARCH {
+	case "arm64":
+		return "Grout-Batocera-arm64.zip"
+	case "amd64":
+		return "Grout-Batocera-amd64.zip"
+	case "386":
+		return "Grout-Batocera-x86.zip"
+	default:
+		return ""
+	}
+}
+
+func (Profile) MultiDiscLayout() multidisc.Layout {
+	return multidisc.LayoutHiddenDir
+}
+
+func (Profile) Device() profile.Device {
+	return profile.Device{ScreenWidth: 1920, ScreenHeight: 1080}
+}
+
+func (Profile) RetroArchDirectories() (profile.RetroArchDirectories, bool) {
+	return profile.RetroArchDirectories{
+		Playlists:  filepath.Join(GetRetroArchDirectory(), "playlists"),
+		Thumbnails: filepath.Join(GetRetroArchDirectory(), "thumbnails"),
+		Cores:      GetCoreDirectory(),
+	}, true
+}
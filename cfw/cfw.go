@@ -1,9 +1,15 @@
 package cfw
 
 import (
-	"log"
+	"grout/cfw/profile"
+	"grout/internal/imageutil"
+	"grout/internal/multidisc"
+	"grout/internal/stringutil"
 	"os"
 	"strings"
+	"sync"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
 
 type CFW string
@@ -21,27 +27,115 @@ const (
 	ArkOS    CFW = "ARKOS"
 	Batocera CFW = "BATOCERA"
 	MinUI    CFW = "MINUI"
+	Linux    CFW = "LINUX"
+)
+
+var (
+	detectOnce sync.Once
+	detected   Detection
+	guessed    CFW
 )
 
+// GetCFW returns the CFW Grout is running on. The CFW environment variable wins when
+// it names a known CFW; otherwise the CFW is detected from the filesystem. When the
+// fingerprints tie or nothing matches, the closest match is used and IsGuess reports it.
 func GetCFW() CFW {
 	cfwEnv := strings.ToUpper(os.Getenv("CFW"))
 	cfw := CFW(cfwEnv)
-
-	switch cfw {
-	case MuOS, NextUI, Knulli, Spruce, ROCKNIX, Trimui, Allium, Onion, Koriki, ArkOS, Batocera, MinUI:
+	if cfwEnv != "" && cfw.Profile() != nil {
 		return cfw
-	default:
-		log.SetOutput(os.Stderr)
-		log.Fatalf("Unsupported CFW: '%s'. Valid options: NextUI, muOS, Knulli, Spruce, ROCKNIX, Trimui, Allium, Onion, Koriki, ArkOS, Batocera, MinUI", cfwEnv)
-		return ""
 	}
+
+	detectOnce.Do(func() {
+		detected = DetectCFW()
+		if cfwEnv != "" {
+			gaba.GetLogger().Warn("Unsupported CFW in environment, detecting instead", "cfw", cfwEnv, "detected", detected.CFW)
+		}
+		if detected.CFW == "" {
+			guessed = detected.Guess()
+			gaba.GetLogger().Warn("Unable to detect the CFW, using the closest match", "guess", guessed, "scores", detected.Scores)
+		}
+	})
+	if detected.CFW != "" {
+		return detected.CFW
+	}
+	return guessed
+}
+
+// IsGuess reports whether GetCFW couldn't tell which CFW it is running on and fell back
+// to Detection.Guess.
+func IsGuess() bool {
+	return guessed != ""
 }
 
 func (c CFW) IsBasedOnEmulationStation() bool {
-	switch c {
-	case Knulli, ROCKNIX, ArkOS, Batocera:
-		return true
-	default:
-		return false
+	p := c.Profile()
+	return p != nil && p.Metadata() == profile.MetadataGamelist
+}
+
+// MultiDiscLayout returns where this CFW expects the discs of a multi-disc game to live
+// relative to the .m3u playlist that launches it.
+func (c CFW) MultiDiscLayout() multidisc.Layout {
+	return profile.MultiDiscLayoutOf(c.Profile())
+}
+
+// CompositeTemplate returns the layout used when Grout renders mix images itself.
+func (c CFW) CompositeTemplate() imageutil.CompositeTemplate {
+	if profile.CompositeLayoutOf(c.Profile()) == profile.CompositeSquare {
+		return imageutil.CompositeTemplateSquare
 	}
+	return imageutil.CompositeTemplateMix
+}
+
+// ArtProfile returns how art written for the frontend is sized and encoded on this device.
+func (c CFW) ArtProfile() imageutil.ArtProfile {
+	device := c.Device()
+	return imageutil.NewArtProfile(device.ScreenWidth, device.ScreenHeight)
+}
+
+// Device returns the device Grout is running on, falling back to the most common
+// screen for the CFW when the device can't be told apart.
+func (c CFW) Device() profile.Device {
+	return profile.DeviceOf(c.Profile())
+}
+
+// RomFolderBase reduces a ROM folder name to what identifies its platform on this CFW.
+func (c CFW) RomFolderBase(path string, tagParser func(string) string) string {
+	if matcher, ok := c.Profile().(profile.FolderMatcher); ok {
+		return matcher.RomFolderBase(path, tagParser)
+	}
+	return path
+}
+
+// SaveDirectoryLabel returns the emulator name a save folder is shown under.
+func (c CFW) SaveDirectoryLabel(dir string) string {
+	return profile.SaveDirectoryLabelOf(c.Profile(), dir)
+}
+
+// ShowsPreviewArt reports whether the CFW has a folder for preview art besides box art.
+func (c CFW) ShowsPreviewArt() bool {
+	p := c.Profile()
+	return p != nil && p.ArtDirectories(p.RomDirectory(), "", "").Preview != ""
+}
+
+// UsesNextUITheme reports whether Grout follows NextUI's theme and power button handling.
+func (c CFW) UsesNextUITheme() bool {
+	return profile.UsesNextUITheme(c.Profile())
+}
+
+// InputMapping returns the controller mapping the CFW ships, or nil to use the default one.
+func (c CFW) InputMapping() ([]byte, error) {
+	return profile.InputMappingOf(c.Profile())
+}
+
+// ArtFileName returns the name the frontend looks for a game's art under.
+func (c CFW) ArtFileName(gameName, romFileName string) string {
+	return profile.ArtFileNameOf(c.Profile(), gameName, romFileName)
+}
+
+// matchesRomFoldersByTag reports whether the CFW tells ROM folders apart by the tag in
+// their name, e.g. "Game Boy Advance (GBA)", so renamed folders still match.
+func (c CFW) matchesRomFoldersByTag() bool {
+	const probe = "Platform (TAG)"
+	return c.RomFolderBase(probe, stringutil.ParseTag) != probe
 }
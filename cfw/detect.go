@@ -0,0 +1,224 @@
+package cfw
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"grout/cfw/minui"
+	"grout/cfw/nextui"
+	"grout/cfw/spruce"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Signal is one piece of evidence about which CFW Grout is running on.
+type Signal struct {
+	CFW    CFW
+	Source string
+	Weight int
+}
+
+// Detection is the outcome of looking for CFW fingerprints.
+type Detection struct {
+	// CFW is the best match, or empty when nothing matched or the top matches tied.
+	CFW     CFW
+	Signals []Signal
+	Scores  map[CFW]int
+}
+
+// fingerprint is a file or directory that only exists on one CFW. When contains is
+// set, the file must also contain that text (case-insensitive). Paths are relative to
+// the filesystem root so detection can run against fixture directories.
+type fingerprint struct {
+	cfw      CFW
+	path     string
+	contains string
+	weight   int
+}
+
+// Paths that are unique to one CFW carry most of the weight. Folders several CFWs
+// share (.userdata, .tmp_update) only tip the balance.
+var fingerprints = []fingerprint{
+	{cfw: MuOS, path: "mnt/mmc/MUOS", weight: 10},
+	{cfw: MuOS, path: "opt/muos", weight: 10},
+	{cfw: NextUI, path: "mnt/SDCARD/.system/version.txt", contains: "nextui", weight: 10},
+	{cfw: NextUI, path: "mnt/SDCARD/.userdata", weight: 2},
+	{cfw: MinUI, path: "mnt/SDCARD/.userdata", weight: 2},
+	{cfw: Batocera, path: "userdata/system/batocera.conf", weight: 5},
+	{cfw: Knulli, path: "userdata/system/batocera.conf", weight: 4},
+	{cfw: Knulli, path: "usr/share/batocera/batocera.version", contains: "knulli", weight: 10},
+	{cfw: ROCKNIX, path: "storage/.config/rocknix", weight: 10},
+	{cfw: ArkOS, path: "home/ark", weight: 10},
+	{cfw: Onion, path: "mnt/SDCARD/.tmp_update/onionVersion", weight: 10},
+	{cfw: Onion, path: "mnt/SDCARD/.tmp_update", weight: 2},
+	{cfw: Spruce, path: "mnt/SDCARD/spruce", weight: 10},
+	{cfw: Spruce, path: "mnt/SDCARD/.tmp_update", weight: 1},
+	{cfw: Allium, path: "mnt/SDCARD/.allium", weight: 10},
+	{cfw: Koriki, path: "mnt/SDCARD/Koriki", weight: 10},
+	{cfw: Trimui, path: "usr/trimui/bin/MainUI", weight: 3},
+}
+
+// osReleaseNames maps names found in /etc/os-release ID or NAME to the CFW they identify.
+var osReleaseNames = map[string]CFW{
+	"rocknix":  ROCKNIX,
+	"knulli":   Knulli,
+	"batocera": Batocera,
+	"muos":     MuOS,
+	"arkos":    ArkOS,
+}
+
+// deviceEnvVars are set by the launch scripts of CFWs whose device detection relies on them.
+var deviceEnvVars = map[string]CFW{
+	nextui.DeviceType: NextUI,
+	minui.DeviceType:  MinUI,
+	spruce.DeviceType: Spruce,
+}
+
+// Detector looks for CFW fingerprints under Root, "/" on a device.
+type Detector struct {
+	Root   string
+	Getenv func(string) string
+}
+
+// DetectCFW looks for CFW fingerprints on the running system.
+func DetectCFW() Detection {
+	return Detector{Root: "/", Getenv: os.Getenv}.Detect()
+}
+
+func (d Detector) Detect() Detection {
+	root := d.Root
+	if root == "" {
+		root = "/"
+	}
+	getenv := d.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	var signals []Signal
+	for _, fp := range fingerprints {
+		if fp.matches(root) {
+			source := "/" + fp.path
+			if fp.contains != "" {
+				source += fmt.Sprintf(" contains %q", fp.contains)
+			}
+			signals = append(signals, Signal{CFW: fp.cfw, Source: source, Weight: fp.weight})
+		}
+	}
+
+	signals = append(signals, osReleaseSignals(root)...)
+
+	for _, name := range slices.Sorted(maps.Keys(deviceEnvVars)) {
+		if value := getenv(name); value != "" {
+			signals = append(signals, Signal{CFW: deviceEnvVars[name], Source: fmt.Sprintf("$%s=%s", name, value), Weight: 5})
+		}
+	}
+
+	return newDetection(signals)
+}
+
+func (fp fingerprint) matches(root string) bool {
+	path := filepath.Join(root, filepath.FromSlash(fp.path))
+	if fp.contains == "" {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+	data, err := os.ReadFile(path)
+	return err == nil && bytes.Contains(bytes.ToLower(data), []byte(fp.contains))
+}
+
+// osReleaseSignals reads the ID and NAME fields of /etc/os-release.
+func osReleaseSignals(root string) []Signal {
+	f, err := os.Open(filepath.Join(root, "etc", "os-release"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var signals []Signal
+	seen := make(map[CFW]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || (key != "ID" && key != "NAME") {
+			continue
+		}
+		value = strings.ToLower(strings.Trim(value, `"'`))
+		for _, name := range slices.Sorted(maps.Keys(osReleaseNames)) {
+			c := osReleaseNames[name]
+			if strings.Contains(value, name) && !seen[c] {
+				seen[c] = true
+				signals = append(signals, Signal{CFW: c, Source: fmt.Sprintf("/etc/os-release %s=%s", key, value), Weight: 8})
+			}
+		}
+	}
+	return signals
+}
+
+func newDetection(signals []Signal) Detection {
+	detection := Detection{Signals: signals, Scores: make(map[CFW]int)}
+	for _, s := range signals {
+		detection.Scores[s.CFW] += s.Weight
+	}
+
+	best, tied := 0, false
+	for c, score := range detection.Scores {
+		switch {
+		case score > best:
+			best, tied = score, false
+			detection.CFW = c
+		case score == best:
+			tied = true
+		}
+	}
+	if tied {
+		detection.CFW = ""
+	}
+	return detection
+}
+
+// Guess returns the CFW to run as when detection is inconclusive: the highest-scoring
+// registered CFW, with ties going to the first by name, or Linux when nothing matched.
+func (d Detection) Guess() CFW {
+	guess, best := Linux, 0
+	for _, c := range slices.Sorted(maps.Keys(d.Scores)) {
+		if d.Scores[c] > best && c.Profile() != nil {
+			guess, best = c, d.Scores[c]
+		}
+	}
+	return guess
+}
+
+// WriteReport prints the matched signals and the verdict, for diagnosing launches.
+func (d Detection) WriteReport(w io.Writer) {
+	if len(d.Signals) == 0 {
+		fmt.Fprintln(w, "No CFW fingerprints matched.")
+	}
+	for _, s := range d.Signals {
+		fmt.Fprintf(w, "  %-9s +%-2d %s\n", s.CFW, s.Weight, s.Source)
+	}
+
+	scored := make([]CFW, 0, len(d.Scores))
+	for c := range d.Scores {
+		scored = append(scored, c)
+	}
+	slices.SortFunc(scored, func(a, b CFW) int {
+		if d.Scores[a] != d.Scores[b] {
+			return d.Scores[b] - d.Scores[a]
+		}
+		return strings.Compare(string(a), string(b))
+	})
+	for _, c := range scored {
+		fmt.Fprintf(w, "%s: %d\n", c, d.Scores[c])
+	}
+
+	if d.CFW == "" {
+		fmt.Fprintln(w, "Detected: none")
+		return
+	}
+	fmt.Fprintf(w, "Detected: %s\n", d.CFW)
+}
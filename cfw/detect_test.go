@@ -0,0 +1,144 @@
+package cfw
+
+import (
+	"grout/cfw/minui"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFixture(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if strings.HasSuffix(path, "/") {
+			if err := os.MkdirAll(full, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		env   map[string]string
+		want  CFW
+	}{
+		{
+			name:  "muOS",
+			files: map[string]string{"mnt/mmc/MUOS/": ""},
+			want:  MuOS,
+		},
+		{
+			name:  "NextUI",
+			files: map[string]string{"mnt/SDCARD/.userdata/": "", "mnt/SDCARD/.system/version.txt": "NextUI v6.2.0\n"},
+			want:  NextUI,
+		},
+		{
+			name:  "MinUI by device env",
+			files: map[string]string{"mnt/SDCARD/.userdata/": ""},
+			env:   map[string]string{minui.DeviceType: "tg5040"},
+			want:  MinUI,
+		},
+		{
+			name:  "userdata alone is ambiguous",
+			files: map[string]string{"mnt/SDCARD/.userdata/": ""},
+			want:  "",
+		},
+		{
+			name:  "Batocera",
+			files: map[string]string{"userdata/system/batocera.conf": "", "etc/os-release": "NAME=\"Batocera.linux\"\nID=batocera\n"},
+			want:  Batocera,
+		},
+		{
+			name: "Knulli",
+			files: map[string]string{
+				"userdata/system/batocera.conf":       "",
+				"usr/share/batocera/batocera.version": "knulli-20250301",
+			},
+			want: Knulli,
+		},
+		{
+			name:  "ROCKNIX by os-release",
+			files: map[string]string{"etc/os-release": "NAME=\"ROCKNIX\"\nVERSION=\"20250101\"\n"},
+			want:  ROCKNIX,
+		},
+		{
+			name:  "Onion",
+			files: map[string]string{"mnt/SDCARD/.tmp_update/onionVersion/version.txt": "4.3.1"},
+			want:  Onion,
+		},
+		{
+			name:  "Spruce shares .tmp_update",
+			files: map[string]string{"mnt/SDCARD/.tmp_update/": "", "mnt/SDCARD/spruce/": ""},
+			want:  Spruce,
+		},
+		{
+			name:  "ArkOS",
+			files: map[string]string{"home/ark/": ""},
+			want:  ArkOS,
+		},
+		{
+			name: "nothing",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFixture(t, root, tt.files)
+
+			d := Detector{Root: root, Getenv: func(key string) string { return tt.env[key] }}.Detect()
+			if d.CFW != tt.want {
+				t.Errorf("Detect() = %q, want %q (signals %+v)", d.CFW, tt.want, d.Signals)
+			}
+		})
+	}
+}
+
+func TestDetectionReport(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{"storage/.config/rocknix/": ""})
+
+	var out strings.Builder
+	Detector{Root: root, Getenv: func(string) string { return "" }}.Detect().WriteReport(&out)
+
+	report := out.String()
+	for _, want := range []string{"/storage/.config/rocknix", "Detected: ROCKNIX"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestDetectionGuess(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores map[CFW]int
+		want   CFW
+	}{
+		{"nothing matched", nil, Linux},
+		{"userdata tie goes to the first by name", map[CFW]int{NextUI: 2, MinUI: 2}, MinUI},
+		{"highest score", map[CFW]int{Onion: 2, Spruce: 1}, Onion},
+		{"unregistered CFWs are skipped", map[CFW]int{"UNKNOWN": 9, Spruce: 1}, Spruce},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Detection{Scores: tt.scores}).Guess(); got != tt.want {
+				t.Errorf("Guess() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,102 +1,30 @@
 package cfw
 
 import (
-	"grout/cfw/allium"
-	"grout/cfw/arkos"
-	"grout/cfw/batocera"
-	"grout/cfw/knulli"
-	"grout/cfw/koriki"
-	"grout/cfw/minui"
-	"grout/cfw/muos"
-	"grout/cfw/nextui"
-	"grout/cfw/onion"
-	"grout/cfw/rocknix"
-	"grout/cfw/spruce"
-	"grout/cfw/trimui"
+	"grout/cfw/profile"
 	"path/filepath"
 )
 
 // GetRomDirectory returns the ROM directory for the current CFW.
 func GetRomDirectory() string {
-	switch GetCFW() {
-	case MuOS:
-		return muos.GetRomDirectory()
-	case NextUI:
-		return nextui.GetRomDirectory()
-	case Knulli:
-		return knulli.GetRomDirectory()
-	case Spruce:
-		return spruce.GetRomDirectory()
-	case ROCKNIX:
-		return rocknix.GetRomDirectory()
-	case Trimui:
-		return trimui.GetRomDirectory()
-	case Allium:
-		return allium.GetRomDirectory()
-	case Onion:
-		return onion.GetRomDirectory()
-	case Koriki:
-		return koriki.GetRomDirectory()
-	case ArkOS:
-		return arkos.GetRomDirectory()
-	case Batocera:
-		return batocera.GetRomDirectory()
-	case MinUI:
-		return minui.GetRomDirectory()
-	}
-	return ""
+	return GetCFW().Profile().RomDirectory()
 }
 
 // RomFolderBase returns the base folder name for ROM matching.
 // tagParser is a function that extracts tags from paths (for NextUI).
 func RomFolderBase(path string, tagParser func(string) string) string {
-	switch GetCFW() {
-	case NextUI:
-		return nextui.RomFolderBase(path, tagParser)
-	case MinUI:
-		return minui.RomFolderBase(path, tagParser)
-	}
-	return path
+	return GetCFW().RomFolderBase(path, tagParser)
 }
 
 // GetBIOSDirectory returns the BIOS directory for the current CFW.
 func GetBIOSDirectory() string {
-	switch GetCFW() {
-	case MuOS:
-		return muos.GetBIOSDirectory()
-	case NextUI:
-		return nextui.GetBIOSDirectory()
-	case Knulli:
-		return knulli.GetBIOSDirectory()
-	case Spruce:
-		return spruce.GetBIOSDirectory()
-	case ROCKNIX:
-		return rocknix.GetBIOSDirectory()
-	case Trimui:
-		return trimui.GetBIOSDirectory()
-	case Allium:
-		return allium.GetBIOSDirectory()
-	case Onion:
-		return onion.GetBIOSDirectory()
-	case Koriki:
-		return koriki.GetBIOSDirectory()
-	case ArkOS:
-		return arkos.GetBIOSDirectory()
-	case Batocera:
-		return batocera.GetBIOSDirectory()
-	case MinUI:
-		return minui.GetBIOSDirectory()
-	}
-	return ""
+	return GetCFW().Profile().BIOSDirectory()
 }
 
 // GetBIOSFilePaths returns the BIOS file paths for a given relative path and platform.
 func GetBIOSFilePaths(relativePath string, platformFSSlug string) []string {
-	switch GetCFW() {
-	case NextUI:
-		return nextui.GetBIOSFilePaths(relativePath, platformFSSlug)
-	case MinUI:
-		return minui.GetBIOSFilePaths(relativePath, platformFSSlug)
+	if resolver, ok := GetCFW().Profile().(profile.BIOSPathResolver); ok {
+		return resolver.BIOSFilePaths(relativePath, platformFSSlug)
 	}
 	return []string{filepath.Join(GetBIOSDirectory(), relativePath)}
 }
@@ -112,188 +40,94 @@ func GetPlatformRomDirectory(relativePath, platformFSSlug string) string {
 	return filepath.Join(GetRomDirectory(), rp)
 }
 
+// GetGamelistDirectory returns the folder the frontend reads romDir's gamelist from.
+func GetGamelistDirectory(romDir string) string {
+	return profile.GamelistDirectoryOf(GetCFW().Profile(), romDir)
+}
+
+func artDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return GetCFW().Profile().ArtDirectories(romDir, platformFSSlug, platformName)
+}
+
 // GetArtDirectory returns the artwork directory for a platform.
 func GetArtDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case NextUI:
-		return nextui.GetArtDirectory(romDir)
-	case Knulli:
-		return knulli.GetArtDirectory(romDir)
-	case Spruce:
-		return spruce.GetArtDirectory(romDir)
-	case MuOS:
-		return muos.GetArtDirectory(platformFSSlug, platformName)
-	case ROCKNIX:
-		return rocknix.GetArtDirectory(romDir)
-	case Trimui:
-		return trimui.GetArtDirectory(platformFSSlug, platformName)
-	case Allium:
-		return allium.GetArtDirectory(romDir)
-	case Onion:
-		return onion.GetArtDirectory(romDir)
-	case Koriki:
-		return koriki.GetArtDirectory(romDir)
-	case ArkOS:
-		return arkos.GetArtDirectory(romDir)
-	case Batocera:
-		return batocera.GetArtDirectory(romDir)
-	case MinUI:
-		return minui.GetArtDirectory(romDir)
-	default:
-		return ""
-	}
+	return artDirectories(romDir, platformFSSlug, platformName).Box
 }
 
 func GetArtPreviewDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case MuOS:
-		return muos.GetPreviewDirectory(platformFSSlug, platformName)
-	default:
-		return ""
-	}
+	return artDirectories(romDir, platformFSSlug, platformName).Preview
 }
 
 func GetArtSplashDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case MuOS:
-		return muos.GetSplashDirectory(platformFSSlug, platformName)
-	default:
-		return ""
-	}
+	return artDirectories(romDir, platformFSSlug, platformName).Splash
 }
 
 // BaseSavePath returns the base save path for the current CFW.
 func BaseSavePath() string {
-	switch GetCFW() {
-	case MuOS:
-		return muos.GetBaseSavePath()
-	case NextUI:
-		return nextui.GetBaseSavePath()
-	case Knulli:
-		return knulli.GetBaseSavePath()
-	case Spruce:
-		return spruce.GetBaseSavePath()
-	case ROCKNIX:
-		return rocknix.GetBaseSavePath()
-	case Trimui:
-		return trimui.GetBaseSavePath()
-	case Allium:
-		return allium.GetBaseSavePath()
-	case Onion:
-		return onion.GetBaseSavePath()
-	case Koriki:
-		return koriki.GetBaseSavePath()
-	case ArkOS:
-		return arkos.GetBaseSavePath()
-	case Batocera:
-		return batocera.GetBaseSavePath()
-	case MinUI:
-		return minui.GetBaseSavePath()
-	}
-	return ""
+	return GetCFW().Profile().BaseSavePath()
 }
 
 func GetArtMarqueeDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case ROCKNIX:
-		return rocknix.GetArtDirectory(romDir)
-	case ArkOS:
-		return arkos.GetArtDirectory(romDir)
-	case Knulli:
-		return knulli.GetArtDirectory(romDir)
-	case Batocera:
-		return batocera.GetArtDirectory(romDir)
-	default:
-		return ""
-	}
+	return artDirectories(romDir, platformFSSlug, platformName).Marquee
 }
 
 func GetArtVideoDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case ROCKNIX:
-		return rocknix.GetVideoDirectory(romDir)
-	case ArkOS:
-		return arkos.GetVideoDirectory(romDir)
-	case Knulli:
-		return knulli.GetVideoDirectory(romDir)
-	case Batocera:
-		return batocera.GetVideoDirectory(romDir)
-	default:
-		return ""
-	}
+	return artDirectories(romDir, platformFSSlug, platformName).Video
 }
 
 func GetArtThumbnailDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case ROCKNIX:
-		return rocknix.GetArtDirectory(romDir)
-	case ArkOS:
-		return arkos.GetArtDirectory(romDir)
-	case Knulli:
-		return knulli.GetArtDirectory(romDir)
-	case Batocera:
-		return knulli.GetArtDirectory(romDir)
-	default:
-		return ""
-	}
+	return artDirectories(romDir, platformFSSlug, platformName).Thumbnail
 }
 
 func GetArtBezelDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case ROCKNIX:
-		return rocknix.GetBezelDirectory(romDir)
-	case ArkOS:
-		return arkos.GetBezelDirectory(romDir)
-	case Knulli:
-		return knulli.GetBezelDirectory(romDir)
-	case Batocera:
-		return batocera.GetBezelDirectory(romDir)
-	default:
-		return ""
-	}
+	return artDirectories(romDir, platformFSSlug, platformName).Bezel
 }
 
 func GetManualDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case ROCKNIX:
-		return rocknix.GetManualDirectory(romDir)
-	case ArkOS:
-		return arkos.GetManualDirectory(romDir)
-	case Knulli:
-		return knulli.GetManualDirectory(romDir)
-	case Batocera:
-		return batocera.GetManualDirectory(romDir)
-	default:
-		return ""
-	}
+	return artDirectories(romDir, platformFSSlug, platformName).Manual
 }
 
 func GetBoxbackDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case ROCKNIX:
-		return rocknix.GetArtDirectory(romDir)
-	case ArkOS:
-		return arkos.GetArtDirectory(romDir)
-	case Knulli:
-		return knulli.GetArtDirectory(romDir)
-	case Batocera:
-		return batocera.GetArtDirectory(romDir)
-	default:
-		return ""
-	}
+	return artDirectories(romDir, platformFSSlug, platformName).Boxback
 }
 
 func GetFanartDirectory(romDir string, platformFSSlug, platformName string) string {
-	switch GetCFW() {
-	case ROCKNIX:
-		return rocknix.GetArtDirectory(romDir)
-	case ArkOS:
-		return arkos.GetArtDirectory(romDir)
-	case Knulli:
-		return knulli.GetArtDirectory(romDir)
-	case Batocera:
-		return batocera.GetArtDirectory(romDir)
-	default:
-		return ""
+	return artDirectories(romDir, platformFSSlug, platformName).Fanart
+}
+
+// categoryFolders name the folders that hold a ROM's non-game files. Categories not
+// listed here (hacks, translations, demos, patches) sit beside the ROM, where
+// frontends list them and cores that soft-patch look for them.
+var categoryFolders = map[string]string{
+	"update": "updates",
+	"dlc":    "dlc",
+	"manual": "manuals",
+	"cheat":  "cheats",
+}
+
+// platformCategoryFolders override categoryFolders for emulators that expect
+// add-ons somewhere specific, e.g. RPCS3 installs updates and DLC from .pkg files.
+var platformCategoryFolders = map[string]map[string]string{
+	"ps3": {"update": "packages", "dlc": "packages"},
+}
+
+// GetFileCategoryDirectory returns where a file of the given RomM category belongs.
+// Manuals use the CFW's manual folder when it has one; other add-ons go to a hidden
+// folder inside the platform's ROM directory so they don't show up as games.
+func GetFileCategoryDirectory(romDir string, platformFSSlug, category string) string {
+	if category == "manual" {
+		if dir := GetManualDirectory(romDir, platformFSSlug, ""); dir != "" {
+			return dir
+		}
+	}
+
+	folder, ok := categoryFolders[category]
+	if !ok {
+		return romDir
+	}
+	if override, ok := platformCategoryFolders[platformFSSlug][category]; ok {
+		folder = override
 	}
+
+	return filepath.Join(romDir, profile.HiddenFolderPrefixOf(GetCFW().Profile())+folder)
 }
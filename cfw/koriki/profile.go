@@ -0,0 +1,52 @@
+package koriki
+
+import (
+	"grout/cfw/profile"
+)
+
+// Profile describes Koriki to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "KORIKI"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+func (Profile) SaveDirectories() map[string][]string {
+	return SaveDirectories
+}
+
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{Box: GetArtDirectory(romDir)}
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataMiyooGamelist
+}
+
+func (Profile) Install() profile.Install {
+	return profile.Install{Asset: "Grout-Koriki.zip", LaunchScript: "Grout/launch.sh", Depth: 3}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return false
+}
+
+func (Profile) InputMapping() ([]byte, error) {
+	return GetInputMappingBytes()
+}
@@ -0,0 +1,140 @@
+// Package linux supports desktop Linux machines running RetroArch, optionally behind
+// ES-DE, such as mini PCs and handheld PCs. ROMs follow the ES-DE ROMs/<system>
+// layout, gamelists and art go where ES-DE reads them, and BIOS files and saves go
+// wherever RetroArch keeps them.
+package linux
+
+import (
+	"grout/cfw/batocera"
+	"grout/internal/retroarch"
+	"os"
+	"path/filepath"
+)
+
+// Environment variables that move Grout's folders away from the defaults.
+const (
+	RomDirectoryEnv    = "GROUT_ROM_DIRECTORY"
+	BIOSDirectoryEnv   = "GROUT_BIOS_DIRECTORY"
+	SaveDirectoryEnv   = "GROUT_SAVE_DIRECTORY"
+	RetroArchConfigEnv = "GROUT_RETROARCH_CONFIG"
+	ESDEDirectoryEnv   = "GROUT_ESDE_DIRECTORY"
+)
+
+// esdeAppDataEnv is ES-DE's own setting for where it keeps its data.
+const esdeAppDataEnv = "ESDE_APPDATA_DIR"
+
+// Platforms reuses Batocera's folder names, which match the ES-DE system names.
+var Platforms = batocera.Platforms
+
+// GetBasePath returns the home directory everything else defaults to living under.
+func GetBasePath() string {
+	if basePath := os.Getenv("BASE_PATH"); basePath != "" {
+		return basePath
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return home
+	}
+	return "/"
+}
+
+func GetRomDirectory() string {
+	if dir := os.Getenv(RomDirectoryEnv); dir != "" {
+		return dir
+	}
+	return filepath.Join(GetBasePath(), "ROMs")
+}
+
+// GetESDEDirectory returns ES-DE's data folder, which holds its gamelists and media.
+func GetESDEDirectory() string {
+	if dir := os.Getenv(ESDEDirectoryEnv); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv(esdeAppDataEnv); dir != "" {
+		return dir
+	}
+	return filepath.Join(GetBasePath(), "ES-DE")
+}
+
+// retroArchConfigPaths are where RetroArch keeps its config, native install first.
+func retroArchConfigPaths() []string {
+	base := GetBasePath()
+	return []string{
+		filepath.Join(base, ".config", "retroarch", "retroarch.cfg"),
+		filepath.Join(base, ".var", "app", "org.libretro.RetroArch", "config", "retroarch", "retroarch.cfg"),
+		filepath.Join(base, "snap", "retroarch", "current", ".config", "retroarch", "retroarch.cfg"),
+	}
+}
+
+// GetRetroArchConfigPath returns the retroarch.cfg in use, or where a native install
+// would put it when none exists yet.
+func GetRetroArchConfigPath() string {
+	if path := os.Getenv(RetroArchConfigEnv); path != "" {
+		return path
+	}
+	paths := retroArchConfigPaths()
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return paths[0]
+}
+
+// GetRetroArchDirectory returns the directory holding retroarch.cfg, playlists and thumbnails.
+func GetRetroArchDirectory() string {
+	return filepath.Dir(GetRetroArchConfigPath())
+}
+
+// retroArchDirectory returns a directory setting from retroarch.cfg, or fallback
+// inside the RetroArch directory when it isn't set.
+func retroArchDirectory(key, fallback string) string {
+	dir := GetRetroArchDirectory()
+	if config, err := retroarch.LoadConfig(GetRetroArchConfigPath()); err == nil {
+		if value := config.Directory(key, dir); value != "" {
+			return value
+		}
+	}
+	return filepath.Join(dir, fallback)
+}
+
+// SavesSortedByContent reports whether RetroArch puts saves in one folder per ROM
+// folder, which is what "Sort Saves into Folders by Content Directory" does on its own.
+// Sorting by core as well nests the saves one level deeper, where Grout can't map them.
+func SavesSortedByContent() bool {
+	config, err := retroarch.LoadConfig(GetRetroArchConfigPath())
+	if err != nil {
+		return false
+	}
+	return config.Enabled("sort_savefiles_by_content_enable") && !config.Enabled("sort_savefiles_enable")
+}
+
+func GetBIOSDirectory() string {
+	if dir := os.Getenv(BIOSDirectoryEnv); dir != "" {
+		return dir
+	}
+	return retroArchDirectory("system_directory", "system")
+}
+
+// GetBaseSavePath returns RetroArch's save folder. Saves are only synced when they are
+// in one folder per system, see SavesSortedByContent.
+func GetBaseSavePath() string {
+	if dir := os.Getenv(SaveDirectoryEnv); dir != "" {
+		return dir
+	}
+	return retroArchDirectory("savefile_directory", "saves")
+}
+
+func GetCoreDirectory() string {
+	return retroArchDirectory("libretro_directory", "cores")
+}
+
+// GetGamelistDirectory returns the folder ES-DE reads the gamelist of romDir from.
+func GetGamelistDirectory(romDir string) string {
+	return filepath.Join(GetESDEDirectory(), "gamelists", filepath.Base(romDir))
+}
+
+// GetMediaDirectory returns the folder ES-DE looks in for one kind of media, such as
+// "covers" or "videos", of the games in romDir.
+func GetMediaDirectory(romDir, kind string) string {
+	return filepath.Join(GetESDEDirectory(), "downloaded_media", filepath.Base(romDir), kind)
+}
@@ -0,0 +1,104 @@
+package linux
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultDirectories(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("BASE_PATH", home)
+	t.Setenv(RetroArchConfigEnv, "")
+
+	if got, want := GetRomDirectory(), filepath.Join(home, "ROMs"); got != want {
+		t.Errorf("GetRomDirectory() = %q, want %q", got, want)
+	}
+	raDir := filepath.Join(home, ".config", "retroarch")
+	if got, want := GetBIOSDirectory(), filepath.Join(raDir, "system"); got != want {
+		t.Errorf("GetBIOSDirectory() = %q, want %q", got, want)
+	}
+	if got, want := GetBaseSavePath(), filepath.Join(raDir, "saves"); got != want {
+		t.Errorf("GetBaseSavePath() = %q, want %q", got, want)
+	}
+}
+
+func TestDirectoriesFromRetroArchConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("BASE_PATH", home)
+	t.Setenv(RetroArchConfigEnv, "")
+
+	// A Flatpak install is found when there is no native one.
+	raDir := filepath.Join(home, ".var", "app", "org.libretro.RetroArch", "config", "retroarch")
+	if err := os.MkdirAll(raDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "savefile_directory = \"/data/saves\"\nsystem_directory = \":/bios\"\n"
+	if err := os.WriteFile(filepath.Join(raDir, "retroarch.cfg"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := GetBaseSavePath(), "/data/saves"; got != want {
+		t.Errorf("GetBaseSavePath() = %q, want %q", got, want)
+	}
+	if got, want := GetBIOSDirectory(), filepath.Join(raDir, "bios"); got != want {
+		t.Errorf("GetBIOSDirectory() = %q, want %q", got, want)
+	}
+
+	t.Setenv(SaveDirectoryEnv, "/elsewhere")
+	if got := GetBaseSavePath(); got != "/elsewhere" {
+		t.Errorf("GetBaseSavePath() with %s = %q", SaveDirectoryEnv, got)
+	}
+}
+
+func TestESDEDirectories(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("BASE_PATH", home)
+	t.Setenv(ESDEDirectoryEnv, "")
+	t.Setenv(esdeAppDataEnv, "")
+
+	romDir := filepath.Join(home, "ROMs", "gba")
+	esde := filepath.Join(home, "ES-DE")
+	if got, want := GetGamelistDirectory(romDir), filepath.Join(esde, "gamelists", "gba"); got != want {
+		t.Errorf("GetGamelistDirectory() = %q, want %q", got, want)
+	}
+	art := Profile{}.ArtDirectories(romDir, "gba", "Game Boy Advance")
+	if got, want := art.Box, filepath.Join(esde, "downloaded_media", "gba", "covers"); got != want {
+		t.Errorf("Box = %q, want %q", got, want)
+	}
+	if art.Bezel != "" {
+		t.Errorf("Bezel = %q, want none", art.Bezel)
+	}
+
+	t.Setenv(esdeAppDataEnv, "/data/esde")
+	if got := GetESDEDirectory(); got != "/data/esde" {
+		t.Errorf("GetESDEDirectory() with %s = %q", esdeAppDataEnv, got)
+	}
+	t.Setenv(ESDEDirectoryEnv, "/elsewhere")
+	if got := GetESDEDirectory(); got != "/elsewhere" {
+		t.Errorf("GetESDEDirectory() with %s = %q", ESDEDirectoryEnv, got)
+	}
+}
+
+func TestSaveDirectoriesNeedContentSorting(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("BASE_PATH", home)
+	cfgPath := filepath.Join(home, "retroarch.cfg")
+	t.Setenv(RetroArchConfigEnv, cfgPath)
+
+	for _, tc := range []struct {
+		cfg  string
+		want bool
+	}{
+		{"", false},
+		{"sort_savefiles_by_content_enable = \"true\"\n", true},
+		{"sort_savefiles_by_content_enable = \"true\"\nsort_savefiles_enable = \"true\"\n", false},
+	} {
+		if err := os.WriteFile(cfgPath, []byte(tc.cfg), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := (Profile{}).SaveDirectories() != nil; got != tc.want {
+			t.Errorf("SaveDirectories() with %q: mapped = %v, want %v", tc.cfg, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package linux
+
+import (
+	"grout/cfw/profile"
+	"grout/internal/multidisc"
+	"path/filepath"
+	"runtime"
+)
+
+// Profile describes desktop Linux with RetroArch to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "LINUX"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+// SaveDirectories returns the platform map when RetroArch sorts saves into folders
+// named after the ROM folder, and nil otherwise, since saves can't be told apart by
+// platform in one shared folder.
+func (Profile) SaveDirectories() map[string][]string {
+	if !SavesSortedByContent() {
+		return nil
+	}
+	return Platforms
+}
+
+// ArtDirectories returns ES-DE's media folders. ES-DE has no bezels.
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{
+		Box:       GetMediaDirectory(romDir, "covers"),
+		Marquee:   GetMediaDirectory(romDir, "marquees"),
+		Video:     GetMediaDirectory(romDir, "videos"),
+		Thumbnail: GetMediaDirectory(romDir, "screenshots"),
+		Manual:    GetMediaDirectory(romDir, "manuals"),
+		Boxback:   GetMediaDirectory(romDir, "backcovers"),
+		Fanart:    GetMediaDirectory(romDir, "fanart"),
+	}
+}
+
+func (Profile) GamelistDirectory(romDir string) string {
+	return GetGamelistDirectory(romDir)
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataGamelist
+}
+
+func (Profile) Install() profile.Install {
+	return profile.Install{Asset: updateAsset(), LaunchScript: "Grout.sh", Depth: 2}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return false
+}
+
+// updateAsset returns the release asset built for this machine's architecture.
+func updateAsset() string {
+	switch runtime.GOARCH {
+	case "arm64":
+		return "Grout-Linux-arm64.zip"
+	case "amd64":
+		return "Grout-Linux-amd64.zip"
+	default:
+		return ""
+	}
+}
+
+func (Profile) MultiDiscLayout() multidisc.Layout {
+	return multidisc.LayoutHiddenDir
+}
+
+func (Profile) Device() profile.Device {
+	return profile.Device{ScreenWidth: 1920, ScreenHeight: 1080}
+}
+
+func (Profile) RetroArchDirectories() (profile.RetroArchDirectories, bool) {
+	return profile.RetroArchDirectories{
+		Playlists:  filepath.Join(GetRetroArchDirectory(), "playlists"),
+		Thumbnails: filepath.Join(GetRetroArchDirectory(), "thumbnails"),
+		Cores:      GetCoreDirectory(),
+	}, true
+}
@@ -1,13 +1,15 @@
 package cfw
 
 import (
-	"grout/cfw/arkos"
-	"grout/cfw/batocera"
-	"grout/cfw/knulli"
 	"grout/cfw/muos"
-	"grout/cfw/rocknix"
+	"grout/cfw/profile"
 	"grout/internal/emulationstation"
+	"grout/internal/fileutil"
 	"grout/internal/gamelist"
+	"grout/internal/maptxt"
+	"grout/internal/stringutil"
+	"path/filepath"
+	"strings"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
@@ -19,39 +21,162 @@ func scheduleESRestart() {
 	}
 }
 
+// refreshES tells a running EmulationStation about new games over its HTTP API, adding
+// just the given games when possible, and falls back to restarting ES when Grout exits.
+func refreshES(entries []gamelist.RomGameEntry) {
+	logger := gaba.GetLogger()
+	client := emulationstation.NewClient(emulationstation.DefaultAPIURL)
+	if !client.Available() {
+		scheduleESRestart()
+		return
+	}
+
+	if len(entries) > 0 {
+		err := addGamesToES(client, entries)
+		if err == nil {
+			return
+		}
+		logger.Debug("Unable to add games over the ES API, reloading instead", "error", err)
+	}
+
+	if err := client.ReloadGames(); err != nil {
+		logger.Debug("Unable to reload games over the ES API", "error", err)
+		scheduleESRestart()
+	}
+}
+
+func addGamesToES(client *emulationstation.Client, entries []gamelist.RomGameEntry) error {
+	bySystem := make(map[string]*gamelist.GameList)
+	for _, entry := range entries {
+		if entry.RomDirectory == "" {
+			continue
+		}
+		system := filepath.Base(entry.RomDirectory)
+		gl, exists := bySystem[system]
+		if !exists {
+			gl = gamelist.New()
+			bySystem[system] = gl
+		}
+		gl.AddRomGame(entry)
+	}
+
+	for system, gl := range bySystem {
+		data, err := gl.Bytes()
+		if err != nil {
+			return err
+		}
+		if err := client.AddGames(system, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func AddGroutToGamelist(c CFW) {
-	switch c {
-	case Knulli:
-		gamelist.AddGroutEntry(knulli.GetGroutGamelist(), "./Grout/Grout.sh")
-	case ROCKNIX:
-		gamelist.AddGroutEntry(rocknix.GetGroutGamelist(), "./Grout.sh")
-	case ArkOS:
-		gamelist.AddGroutEntry(arkos.GetGroutGamelist(), "./Grout.sh")
-	case Batocera:
-		gamelist.AddGroutEntry(batocera.GetGroutGamelist(), "./Grout/Grout.sh")
-	default:
+	launcher, ok := c.Profile().(profile.GamelistLauncher)
+	if !ok {
 		return
 	}
-	scheduleESRestart()
+	gamelistPath, launchPath := launcher.GroutGamelist()
+	if gamelistPath == "" {
+		return
+	}
+	gamelist.AddGroutEntry(gamelistPath, launchPath)
+	refreshES(nil)
+}
+
+// RefreshGamelists makes the frontend pick up gamelists that changed outside a download.
+func RefreshGamelists() {
+	if GetCFW().IsBasedOnEmulationStation() {
+		refreshES(nil)
+	}
+}
+
+// GamelistFileName returns the name of the gamelist this CFW keeps in each ROM folder,
+// and false when it doesn't use one.
+func (c CFW) GamelistFileName() (gamelist.FileName, bool) {
+	p := c.Profile()
+	if p == nil {
+		return "", false
+	}
+	switch p.Metadata() {
+	case profile.MetadataGamelist:
+		return gamelist.GameListFileName, true
+	case profile.MetadataMiyooGamelist:
+		return gamelist.MiyooGameListFileName, true
+	default:
+		return "", false
+	}
 }
 
 func FillGamesMetadata(entries []gamelist.RomGameEntry) {
 	logger := gaba.GetLogger()
-	switch GetCFW() {
-	case Knulli, ROCKNIX, ArkOS, Batocera:
+	switch GetCFW().Profile().Metadata() {
+	case profile.MetadataGamelist:
 		if err := gamelist.AddRomGamesToGamelist(entries, gamelist.GameListFileName); err != nil {
 			logger.Warn("Failed to add games to ES gamelist.xml", "error", err)
 		}
-		scheduleESRestart()
-	case Spruce, Allium, Onion, Koriki:
+		refreshES(entries)
+	case profile.MetadataMiyooGamelist:
 		if err := gamelist.AddRomGamesToGamelist(entries, gamelist.MiyooGameListFileName); err != nil {
 			logger.Warn("Failed to add games to miyoogamelist.xml", "error", err)
 		}
-	case MuOS:
+	case profile.MetadataMuOS:
 		for _, entry := range entries {
 			muos.AddGameDescription(entry)
 		}
+	case profile.MetadataMapTxt:
+		updateMapFiles(entries)
 	default:
 		return
 	}
 }
+
+// updateMapFiles gives downloaded games a friendly name in their folder's map.txt and
+// drops the names of games that are no longer there. Names already in map.txt are kept.
+func updateMapFiles(entries []gamelist.RomGameEntry) {
+	logger := gaba.GetLogger()
+
+	byDir := make(map[string][]gamelist.RomGameEntry)
+	for _, entry := range entries {
+		if entry.Game == nil || entry.RomDirectory == "" || entry.GamePath == "" {
+			continue
+		}
+		byDir[entry.RomDirectory] = append(byDir[entry.RomDirectory], entry)
+	}
+
+	for romDir, dirEntries := range byDir {
+		mapPath := filepath.Join(romDir, maptxt.FileName)
+		m, err := maptxt.Load(mapPath)
+		if err != nil {
+			logger.Warn("Failed to read map.txt", "path", mapPath, "error", err)
+			continue
+		}
+
+		for _, entry := range dirEntries {
+			file := mapFileName(romDir, entry.GamePath)
+			if file == "" {
+				continue
+			}
+			m.Add(file, stringutil.PrepareRomName(entry.Game.Name, entry.Game.Regions))
+		}
+		m.Prune(func(file string) bool {
+			return fileutil.FileExists(filepath.Join(romDir, file))
+		})
+
+		if err := m.Save(mapPath); err != nil {
+			logger.Warn("Failed to write map.txt", "path", mapPath, "error", err)
+		}
+	}
+}
+
+// mapFileName returns the name map.txt knows a game by: the file or folder directly
+// inside the ROM folder that contains it.
+func mapFileName(romDir, gamePath string) string {
+	rel, err := filepath.Rel(romDir, gamePath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	first, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	return first
+}
@@ -0,0 +1,92 @@
+package minui
+
+import (
+	"grout/cfw/profile"
+	"grout/internal/multidisc"
+)
+
+// Profile describes MinUI to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "MINUI"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+func (Profile) SaveDirectories() map[string][]string {
+	return SaveDirectories
+}
+
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{Box: GetArtDirectory(romDir)}
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataMapTxt
+}
+
+func (Profile) Install() profile.Install {
+	return profile.Install{Asset: "Grout-MinUI.zip", LaunchScript: "Grout.pak/launch.sh", Depth: 2}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return true
+}
+
+func (Profile) BIOSFilePaths(relativePath, platformFSSlug string) []string {
+	return GetBIOSFilePaths(relativePath, platformFSSlug)
+}
+
+func (Profile) RomFolderBase(path string, tagParser func(string) string) string {
+	return RomFolderBase(path, tagParser)
+}
+
+func (Profile) MultiDiscLayout() multidisc.Layout {
+	return multidisc.LayoutGameFolder
+}
+
+func (Profile) CompositeLayout() profile.CompositeLayout {
+	return profile.CompositeSquare
+}
+
+func (Profile) Device() profile.Device {
+	switch DetectDevice() {
+	case DeviceTrimui:
+		return profile.Device{ScreenWidth: 1280, ScreenHeight: 720}
+	case DeviceTrimuiBrick:
+		return profile.Device{ScreenWidth: 1024, ScreenHeight: 768}
+	case DeviceZero28:
+		return profile.Device{ScreenWidth: 640, ScreenHeight: 480, Rotation: 90}
+	case DeviceMiyooFlip:
+		return profile.Device{ScreenWidth: 640, ScreenHeight: 480, GamepadOnly: true}
+	default:
+		return profile.Device{ScreenWidth: 640, ScreenHeight: 480}
+	}
+}
+
+func (Profile) InputMapping() ([]byte, error) {
+	return GetInputMappingBytes()
+}
+
+// ArtFileName keeps the ROM's extension, since MinUI looks art up by the full file name.
+func (Profile) ArtFileName(gameName, romFileName string) string {
+	if romFileName == "" {
+		return gameName + ".png"
+	}
+	return romFileName + ".png"
+}
@@ -43,6 +43,15 @@ func GetInfoDirectory() string {
 	return filepath.Join(GetBasePath(), "info")
 }
 
+// GetRetroArchDirectory returns the directory holding RetroArch's playlists and thumbnails.
+func GetRetroArchDirectory() string {
+	return GetBasePath()
+}
+
+func GetCoreDirectory() string {
+	return filepath.Join(GetBasePath(), "core")
+}
+
 func GetBaseSavePath() string {
 	return filepath.Join(GetBasePath(), "save")
 }
@@ -0,0 +1,92 @@
+package muos
+
+import (
+	"grout/cfw/profile"
+	"grout/internal/multidisc"
+	"path/filepath"
+	"strings"
+)
+
+// Profile describes muOS to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "MUOS"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+func (Profile) SaveDirectories() map[string][]string {
+	return SaveDirectories
+}
+
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{
+		Box:     GetArtDirectory(platformFSSlug, platformName),
+		Preview: GetPreviewDirectory(platformFSSlug, platformName),
+		Splash:  GetSplashDirectory(platformFSSlug, platformName),
+	}
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataMuOS
+}
+
+func (Profile) Install() profile.Install {
+	return profile.Install{Asset: "Grout.muxapp", LaunchScript: "Grout/mux_launch.sh", Depth: 2}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return false
+}
+
+func (Profile) MultiDiscLayout() multidisc.Layout {
+	return multidisc.LayoutUnderscoreDir
+}
+
+func (Profile) Device() profile.Device {
+	switch DetectDevice() {
+	case DeviceTrimuiSmartPro:
+		return profile.Device{ScreenWidth: 1280, ScreenHeight: 720}
+	case DeviceTrimui:
+		return profile.Device{ScreenWidth: 1024, ScreenHeight: 768}
+	default:
+		return profile.Device{ScreenWidth: 640, ScreenHeight: 480}
+	}
+}
+
+func (Profile) RetroArchDirectories() (profile.RetroArchDirectories, bool) {
+	return profile.RetroArchDirectories{
+		Playlists:  filepath.Join(GetRetroArchDirectory(), "playlist"),
+		Thumbnails: filepath.Join(GetRetroArchDirectory(), "thumbnail"),
+		Cores:      GetCoreDirectory(),
+	}, true
+}
+
+func (Profile) HiddenFolderPrefix() string {
+	return "_"
+}
+
+// SaveDirectoryLabel drops the file/ and /backup parts of muOS save folders.
+func (Profile) SaveDirectoryLabel(dir string) string {
+	dir = strings.ReplaceAll(dir, "file/", "")
+	return strings.ReplaceAll(dir, "/backup", "")
+}
+
+func (Profile) InputMapping() ([]byte, error) {
+	return GetInputMappingBytes()
+}
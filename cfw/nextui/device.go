@@ -3,18 +3,24 @@ package nextui
 import (
 	"os"
 	"runtime"
+	"strings"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
 
 const DeviceType = "NEXTUI_DEVICE"
 
+// devicetreeModelPath is the path to the device-tree model string, which tells the
+// TrimUI Brick apart from the Smart Pro. A variable so tests can point it elsewhere.
+var devicetreeModelPath = "/sys/firmware/devicetree/base/model"
+
 type Device string
 
 const (
-	DeviceMiyooFlip Device = "miyooflip"
-	DeviceTrimui    Device = "trimui"
-	DeviceGeneric   Device = "generic"
+	DeviceMiyooFlip   Device = "miyooflip"
+	DeviceTrimui      Device = "trimui"
+	DeviceTrimuiBrick Device = "trimui-brick"
+	DeviceGeneric     Device = "generic"
 )
 
 func detectDeviceByEnv() Device {
@@ -41,6 +47,14 @@ func DetectDevice() Device {
 	switch deviceType {
 	case DeviceMiyooFlip:
 		return deviceType
+	case DeviceTrimui:
+		// The TrimUI Smart Pro (1280x720) and the Brick (1024x768) both report tg5040.
+		// Only the device-tree model tells them apart; without it the Smart Pro is assumed.
+		model, err := os.ReadFile(devicetreeModelPath)
+		if err == nil && strings.Contains(strings.ToLower(string(model)), "brick") {
+			return DeviceTrimuiBrick
+		}
+		return DeviceTrimui
 	}
 
 	return DeviceGeneric
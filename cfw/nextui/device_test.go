@@ -0,0 +1,42 @@
+package nextui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectDevice(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   string
+		model string // empty for a missing model file
+		want  Device
+	}{
+		{"smart pro", "tg5040", "TrimUI Smart Pro", DeviceTrimui},
+		{"brick", "tg5040", "TrimUI Brick", DeviceTrimuiBrick},
+		{"smart pro s", "tg5050", "TrimUI Smart Pro S", DeviceTrimui},
+		{"tg5040 without model", "tg5040", "", DeviceTrimui},
+		{"miyoo flip", "my355", "", DeviceMiyooFlip},
+		{"unknown", "", "", DeviceGeneric},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DeviceType, tt.env)
+
+			original := devicetreeModelPath
+			t.Cleanup(func() { devicetreeModelPath = original })
+			devicetreeModelPath = filepath.Join(t.TempDir(), "model")
+			if tt.model != "" {
+				if err := os.WriteFile(devicetreeModelPath, []byte(tt.model), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := DetectDevice(); got != tt.want {
+				t.Errorf("DetectDevice() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
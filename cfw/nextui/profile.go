@@ -0,0 +1,81 @@
+package nextui
+
+import (
+	"grout/cfw/profile"
+	"grout/internal/multidisc"
+)
+
+// Profile describes NextUI to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "NEXTUI"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+func (Profile) SaveDirectories() map[string][]string {
+	return SaveDirectories
+}
+
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{Box: GetArtDirectory(romDir)}
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataMapTxt
+}
+
+func (Profile) Install() profile.Install {
+	return profile.Install{Asset: "Grout.pak.zip", LaunchScript: "launch.sh", Depth: 1}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return true
+}
+
+func (Profile) BIOSFilePaths(relativePath, platformFSSlug string) []string {
+	return GetBIOSFilePaths(relativePath, platformFSSlug)
+}
+
+func (Profile) RomFolderBase(path string, tagParser func(string) string) string {
+	return RomFolderBase(path, tagParser)
+}
+
+func (Profile) MultiDiscLayout() multidisc.Layout {
+	return multidisc.LayoutGameFolder
+}
+
+func (Profile) CompositeLayout() profile.CompositeLayout {
+	return profile.CompositeSquare
+}
+
+func (Profile) Device() profile.Device {
+	switch DetectDevice() {
+	case DeviceMiyooFlip:
+		return profile.Device{ScreenWidth: 640, ScreenHeight: 480, GamepadOnly: true}
+	case DeviceTrimuiBrick:
+		return profile.Device{ScreenWidth: 1024, ScreenHeight: 768}
+	default:
+		// Art sized for the larger screen still fits when scaled down.
+		return profile.Device{ScreenWidth: 1280, ScreenHeight: 720}
+	}
+}
+
+func (Profile) NextUITheme() bool {
+	return true
+}
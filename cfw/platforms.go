@@ -1,18 +1,6 @@
 package cfw
 
 import (
-	"grout/cfw/allium"
-	"grout/cfw/arkos"
-	"grout/cfw/batocera"
-	"grout/cfw/knulli"
-	"grout/cfw/koriki"
-	"grout/cfw/minui"
-	"grout/cfw/muos"
-	"grout/cfw/nextui"
-	"grout/cfw/onion"
-	"grout/cfw/rocknix"
-	"grout/cfw/spruce"
-	"grout/cfw/trimui"
 	"strings"
 )
 
@@ -22,19 +10,9 @@ var platformAliasMap = buildPlatformAliasMap()
 
 func buildPlatformAliasMap() map[string][]string {
 	// Combine all platform maps to find aliases across all CFWs
-	allMaps := []map[string][]string{
-		knulli.Platforms,
-		muos.Platforms,
-		nextui.Platforms,
-		rocknix.Platforms,
-		spruce.Platforms,
-		trimui.Platforms,
-		allium.Platforms,
-		onion.Platforms,
-		koriki.Platforms,
-		arkos.Platforms,
-		batocera.Platforms,
-		minui.Platforms,
+	allMaps := make([]map[string][]string, 0, len(builtinProfiles))
+	for _, p := range builtinProfiles {
+		allMaps = append(allMaps, p.Platforms())
 	}
 
 	// Build reverse map: primary folder -> list of RomM slugs that use it as primary
@@ -117,34 +95,11 @@ func GetPlatformAliases(fsSlug string) []string {
 
 // GetPlatformMap returns the platform mapping for the given CFW.
 func GetPlatformMap(c CFW) map[string][]string {
-	switch c {
-	case MuOS:
-		return muos.Platforms
-	case NextUI:
-		return nextui.Platforms
-	case Knulli:
-		return knulli.Platforms
-	case Spruce:
-		return spruce.Platforms
-	case ROCKNIX:
-		return rocknix.Platforms
-	case Trimui:
-		return trimui.Platforms
-	case Allium:
-		return allium.Platforms
-	case Onion:
-		return onion.Platforms
-	case Koriki:
-		return koriki.Platforms
-	case ArkOS:
-		return arkos.Platforms
-	case Batocera:
-		return batocera.Platforms
-	case MinUI:
-		return minui.Platforms
-	default:
+	p := c.Profile()
+	if p == nil {
 		return nil
 	}
+	return p.Platforms()
 }
 
 // RomMFSSlugToCFW converts a RomM filesystem slug to the CFW-specific folder name.
@@ -0,0 +1,300 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"grout/internal/multidisc"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Custom is a profile read from a JSON file, for CFWs Grout doesn't ship with or
+// builds that move things around. A custom profile can extend a built-in one and only
+// list what differs; anything left out comes from the profile it extends.
+//
+// Directories may be absolute or relative to base_path, and BASE_PATH overrides
+// base_path the same way it does for built-in CFWs. Art directories are relative to
+// the platform's ROM folder and may use the {base_path}, {rom_dir}, {platform} (RomM
+// filesystem slug) and {platform_name} placeholders.
+type Custom struct {
+	Name            string              `json:"id"`
+	Extends         string              `json:"extends,omitempty"`
+	BasePath        string              `json:"base_path,omitempty"`
+	Roms            string              `json:"rom_directory,omitempty"`
+	BIOS            string              `json:"bios_directory,omitempty"`
+	Saves           string              `json:"save_directory,omitempty"`
+	Art             ArtDirectories      `json:"art_directories,omitzero"`
+	PlatformFolders map[string][]string `json:"platforms,omitempty"`
+	SaveFolders     map[string][]string `json:"save_directories,omitempty"`
+	MetadataFormat  Metadata            `json:"metadata,omitempty"`
+	UpdateAsset     string              `json:"update_asset,omitempty"`
+	LaunchScript    string              `json:"launch_script,omitempty"`
+	InstallDepth    int                 `json:"install_depth,omitempty"`
+	KeepRomExt      *bool               `json:"keep_rom_ext,omitempty"`
+
+	base Profile
+}
+
+// Parse reads a custom profile. lookup resolves the profile named by "extends".
+func Parse(data []byte, lookup func(id string) (Profile, bool)) (*Custom, error) {
+	var c Custom
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	c.Name = strings.ToUpper(strings.TrimSpace(c.Name))
+	if c.Name == "" {
+		return nil, fmt.Errorf("profile has no id")
+	}
+
+	if c.Extends != "" {
+		base, ok := lookup(strings.ToUpper(c.Extends))
+		if !ok {
+			return nil, fmt.Errorf("profile %s extends unknown CFW %q", c.Name, c.Extends)
+		}
+		c.base = base
+	} else if c.Roms == "" {
+		return nil, fmt.Errorf("profile %s needs rom_directory or extends", c.Name)
+	}
+
+	if c.MetadataFormat != "" && !c.MetadataFormat.valid() {
+		return nil, fmt.Errorf("profile %s has unknown metadata %q", c.Name, c.MetadataFormat)
+	}
+	if c.InstallDepth < 0 {
+		return nil, fmt.Errorf("profile %s has negative install_depth", c.Name)
+	}
+
+	return &c, nil
+}
+
+// Load reads a custom profile from a file.
+func Load(path string, lookup func(id string) (Profile, bool)) (*Custom, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(data, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return c, nil
+}
+
+func (c *Custom) ID() string {
+	return c.Name
+}
+
+func (c *Custom) basePath() string {
+	if basePath := os.Getenv("BASE_PATH"); basePath != "" {
+		return basePath
+	}
+	if c.BasePath != "" {
+		return c.BasePath
+	}
+	return "/mnt/SDCARD"
+}
+
+func (c *Custom) resolve(dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(c.basePath(), dir)
+}
+
+func (c *Custom) RomDirectory() string {
+	if c.Roms == "" {
+		return c.base.RomDirectory()
+	}
+	return c.resolve(c.Roms)
+}
+
+func (c *Custom) BIOSDirectory() string {
+	if c.BIOS == "" {
+		if c.base != nil {
+			return c.base.BIOSDirectory()
+		}
+		return c.resolve("BIOS")
+	}
+	return c.resolve(c.BIOS)
+}
+
+func (c *Custom) BaseSavePath() string {
+	if c.Saves == "" {
+		if c.base != nil {
+			return c.base.BaseSavePath()
+		}
+		return c.resolve("Saves")
+	}
+	return c.resolve(c.Saves)
+}
+
+// Platforms returns the extended profile's platforms with this profile's entries on top.
+func (c *Custom) Platforms() map[string][]string {
+	if c.base == nil {
+		return c.PlatformFolders
+	}
+	return overlay(c.base.Platforms(), c.PlatformFolders)
+}
+
+// SaveDirectories returns the extended profile's save folders with this profile's
+// entries on top. Without either, saves are kept next to the ROMs.
+func (c *Custom) SaveDirectories() map[string][]string {
+	if c.base == nil {
+		if c.SaveFolders == nil {
+			return c.PlatformFolders
+		}
+		return c.SaveFolders
+	}
+	return overlay(c.base.SaveDirectories(), c.SaveFolders)
+}
+
+func overlay(base, top map[string][]string) map[string][]string {
+	if len(top) == 0 {
+		return base
+	}
+	merged := make(map[string][]string, len(base)+len(top))
+	maps.Copy(merged, base)
+	maps.Copy(merged, top)
+	return merged
+}
+
+func (c *Custom) ArtDirectories(romDir, platformFSSlug, platformName string) ArtDirectories {
+	if c.Art == (ArtDirectories{}) {
+		if c.base != nil {
+			return c.base.ArtDirectories(romDir, platformFSSlug, platformName)
+		}
+		return ArtDirectories{}
+	}
+
+	replacer := strings.NewReplacer(
+		"{base_path}", c.basePath(),
+		"{rom_dir}", romDir,
+		"{platform}", platformFSSlug,
+		"{platform_name}", platformName,
+	)
+	expand := func(template string) string {
+		if template == "" {
+			return ""
+		}
+		dir := replacer.Replace(template)
+		if filepath.IsAbs(dir) {
+			return filepath.Clean(dir)
+		}
+		return filepath.Join(romDir, dir)
+	}
+
+	return ArtDirectories{
+		Box:       expand(c.Art.Box),
+		Preview:   expand(c.Art.Preview),
+		Splash:    expand(c.Art.Splash),
+		Marquee:   expand(c.Art.Marquee),
+		Video:     expand(c.Art.Video),
+		Thumbnail: expand(c.Art.Thumbnail),
+		Bezel:     expand(c.Art.Bezel),
+		Manual:    expand(c.Art.Manual),
+		Boxback:   expand(c.Art.Boxback),
+		Fanart:    expand(c.Art.Fanart),
+	}
+}
+
+func (c *Custom) Metadata() Metadata {
+	if c.MetadataFormat != "" {
+		return c.MetadataFormat
+	}
+	if c.base != nil {
+		return c.base.Metadata()
+	}
+	return MetadataNone
+}
+
+func (c *Custom) Install() Install {
+	install := Install{Depth: 2}
+	if c.base != nil {
+		install = c.base.Install()
+	}
+	if c.UpdateAsset != "" {
+		install.Asset = c.UpdateAsset
+	}
+	if c.LaunchScript != "" {
+		install.LaunchScript = c.LaunchScript
+	}
+	if c.InstallDepth != 0 {
+		install.Depth = c.InstallDepth
+	}
+	return install
+}
+
+func (c *Custom) KeepsRomExt() bool {
+	if c.KeepRomExt != nil {
+		return *c.KeepRomExt
+	}
+	return c.base != nil && c.base.KeepsRomExt()
+}
+
+// BIOSFilePaths uses the extended profile's BIOS layout unless this profile moves the
+// BIOS folder, in which case everything goes into that one folder.
+func (c *Custom) BIOSFilePaths(relativePath, platformFSSlug string) []string {
+	if resolver, ok := c.base.(BIOSPathResolver); ok && c.BIOS == "" {
+		return resolver.BIOSFilePaths(relativePath, platformFSSlug)
+	}
+	return []string{filepath.Join(c.BIOSDirectory(), relativePath)}
+}
+
+func (c *Custom) RomFolderBase(path string, tagParser func(string) string) string {
+	if matcher, ok := c.base.(FolderMatcher); ok {
+		return matcher.RomFolderBase(path, tagParser)
+	}
+	return path
+}
+
+func (c *Custom) GroutGamelist() (string, string) {
+	if launcher, ok := c.base.(GamelistLauncher); ok {
+		return launcher.GroutGamelist()
+	}
+	return "", ""
+}
+
+func (c *Custom) RetroArchDirectories() (RetroArchDirectories, bool) {
+	return RetroArchDirectoriesOf(c.base)
+}
+
+func (c *Custom) MultiDiscLayout() multidisc.Layout {
+	return MultiDiscLayoutOf(c.base)
+}
+
+func (c *Custom) CompositeLayout() CompositeLayout {
+	return CompositeLayoutOf(c.base)
+}
+
+func (c *Custom) Device() Device {
+	return DeviceOf(c.base)
+}
+
+func (c *Custom) HiddenFolderPrefix() string {
+	return HiddenFolderPrefixOf(c.base)
+}
+
+func (c *Custom) SaveDirectoryLabel(dir string) string {
+	if l, ok := c.base.(SaveDirectoryLabeler); ok {
+		return l.SaveDirectoryLabel(dir)
+	}
+	return dir
+}
+
+func (c *Custom) NextUITheme() bool {
+	return UsesNextUITheme(c.base)
+}
+
+func (c *Custom) InputMapping() ([]byte, error) {
+	return InputMappingOf(c.base)
+}
+
+func (c *Custom) ArtFileName(gameName, romFileName string) string {
+	return ArtFileNameOf(c.base, gameName, romFileName)
+}
+
+func (c *Custom) GamelistDirectory(romDir string) string {
+	return GamelistDirectoryOf(c.base, romDir)
+}
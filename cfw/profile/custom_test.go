@@ -0,0 +1,207 @@
+package profile
+
+import (
+	"grout/internal/multidisc"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type baseProfile struct{}
+
+func (baseProfile) ID() string            { return "BASE" }
+func (baseProfile) RomDirectory() string  { return "/base/Roms" }
+func (baseProfile) BIOSDirectory() string { return "/base/BIOS" }
+func (baseProfile) BaseSavePath() string  { return "/base/Saves" }
+func (baseProfile) Platforms() map[string][]string {
+	return map[string][]string{"gba": {"GBA"}, "snes": {"SFC"}}
+}
+func (baseProfile) SaveDirectories() map[string][]string {
+	return map[string][]string{"gba": {"GBA"}}
+}
+func (baseProfile) ArtDirectories(romDir, _, _ string) ArtDirectories {
+	return ArtDirectories{Box: filepath.Join(romDir, "Imgs")}
+}
+func (baseProfile) Metadata() Metadata { return MetadataMiyooGamelist }
+func (baseProfile) Install() Install {
+	return Install{Asset: "Grout-Base.zip", LaunchScript: "Grout/launch.sh", Depth: 3}
+}
+func (baseProfile) KeepsRomExt() bool { return false }
+func (baseProfile) BIOSFilePaths(relativePath, _ string) []string {
+	return []string{filepath.Join("/base/BIOS", "a", relativePath), filepath.Join("/base/BIOS", "b", relativePath)}
+}
+
+func (baseProfile) MultiDiscLayout() multidisc.Layout { return multidisc.LayoutUnderscoreDir }
+func (baseProfile) CompositeLayout() CompositeLayout  { return CompositeSquare }
+func (baseProfile) Device() Device {
+	return Device{ScreenWidth: 1024, ScreenHeight: 768, Rotation: 270}
+}
+func (baseProfile) HiddenFolderPrefix() string { return "_" }
+func (baseProfile) RetroArchDirectories() (RetroArchDirectories, bool) {
+	return RetroArchDirectories{Playlists: "/base/RetroArch/playlist"}, true
+}
+func (baseProfile) SaveDirectoryLabel(dir string) string { return filepath.Dir(dir) }
+
+func lookupBase(id string) (Profile, bool) {
+	if id == "BASE" {
+		return baseProfile{}, true
+	}
+	return nil, false
+}
+
+func TestParseValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"missing id", `{"rom_directory": "Roms"}`},
+		{"missing roms", `{"id": "garlic"}`},
+		{"unknown base", `{"id": "garlic", "extends": "nope"}`},
+		{"unknown metadata", `{"id": "garlic", "rom_directory": "Roms", "metadata": "xml"}`},
+		{"negative depth", `{"id": "garlic", "rom_directory": "Roms", "install_depth": -1}`},
+		{"bad json", `{"id": `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.json), lookupBase); err == nil {
+				t.Error("Parse() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestCustomStandalone(t *testing.T) {
+	t.Setenv("BASE_PATH", "/sd")
+
+	c, err := Parse([]byte(`{
+		"id": "garlic",
+		"rom_directory": "Roms",
+		"bios_directory": "/mnt/mmc/bios",
+		"art_directories": {"box": "Imgs", "video": "{base_path}/Videos/{platform}"},
+		"platforms": {"gba": ["GBA"]},
+		"metadata": "miyoogamelist",
+		"launch_script": "Grout/launch.sh"
+	}`), lookupBase)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if c.ID() != "GARLIC" {
+		t.Errorf("ID() = %q, want GARLIC", c.ID())
+	}
+	if got := c.RomDirectory(); got != "/sd/Roms" {
+		t.Errorf("RomDirectory() = %q", got)
+	}
+	if got := c.BIOSDirectory(); got != "/mnt/mmc/bios" {
+		t.Errorf("BIOSDirectory() = %q", got)
+	}
+	if got := c.BaseSavePath(); got != "/sd/Saves" {
+		t.Errorf("BaseSavePath() = %q", got)
+	}
+	if got := c.SaveDirectories(); !reflect.DeepEqual(got, c.Platforms()) {
+		t.Errorf("SaveDirectories() = %v, want the platform map", got)
+	}
+
+	art := c.ArtDirectories("/sd/Roms/GBA", "gba", "Game Boy Advance")
+	if art.Box != "/sd/Roms/GBA/Imgs" {
+		t.Errorf("Box = %q", art.Box)
+	}
+	if art.Video != "/sd/Videos/gba" {
+		t.Errorf("Video = %q", art.Video)
+	}
+	if art.Manual != "" {
+		t.Errorf("Manual = %q, want empty", art.Manual)
+	}
+
+	if got := c.Install(); got != (Install{LaunchScript: "Grout/launch.sh", Depth: 2}) {
+		t.Errorf("Install() = %+v", got)
+	}
+	if path, _ := c.GroutGamelist(); path != "" {
+		t.Errorf("GroutGamelist() path = %q, want empty", path)
+	}
+}
+
+func TestCustomExtends(t *testing.T) {
+	c, err := Parse([]byte(`{
+		"id": "fork",
+		"extends": "base",
+		"platforms": {"snes": ["SNES"], "psx": ["PS"]},
+		"keep_rom_ext": true
+	}`), lookupBase)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := c.RomDirectory(); got != "/base/Roms" {
+		t.Errorf("RomDirectory() = %q", got)
+	}
+	want := map[string][]string{"gba": {"GBA"}, "snes": {"SNES"}, "psx": {"PS"}}
+	if got := c.Platforms(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Platforms() = %v, want %v", got, want)
+	}
+	if got := c.SaveDirectories(); !reflect.DeepEqual(got, map[string][]string{"gba": {"GBA"}}) {
+		t.Errorf("SaveDirectories() = %v", got)
+	}
+	if got := c.ArtDirectories("/base/Roms/GBA", "gba", "").Box; got != "/base/Roms/GBA/Imgs" {
+		t.Errorf("Box = %q", got)
+	}
+	if c.Metadata() != MetadataMiyooGamelist {
+		t.Errorf("Metadata() = %q", c.Metadata())
+	}
+	if got := c.Install(); got.Asset != "Grout-Base.zip" || got.Depth != 3 {
+		t.Errorf("Install() = %+v", got)
+	}
+	if !c.KeepsRomExt() {
+		t.Error("KeepsRomExt() = false, want true")
+	}
+	if got := c.BIOSFilePaths("gba_bios.bin", "gba"); len(got) != 2 {
+		t.Errorf("BIOSFilePaths() = %v, want the base profile's two paths", got)
+	}
+
+	c.BIOS = "/elsewhere"
+	if got := c.BIOSFilePaths("gba_bios.bin", "gba"); !reflect.DeepEqual(got, []string{"/elsewhere/gba_bios.bin"}) {
+		t.Errorf("BIOSFilePaths() with bios_directory = %v", got)
+	}
+}
+
+func TestCustomInheritsFrontendBehaviour(t *testing.T) {
+	garlic, err := Parse([]byte(`{"id": "GARLIC", "extends": "BASE"}`), lookupBase)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	standalone, err := Parse([]byte(`{"id": "MINE", "rom_directory": "Roms"}`), lookupBase)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := MultiDiscLayoutOf(garlic); got != multidisc.LayoutUnderscoreDir {
+		t.Errorf("MultiDiscLayoutOf(extends) = %v", got)
+	}
+	if got := MultiDiscLayoutOf(standalone); got != multidisc.LayoutHiddenSubfolder {
+		t.Errorf("MultiDiscLayoutOf(standalone) = %v", got)
+	}
+	if CompositeLayoutOf(garlic) != CompositeSquare || CompositeLayoutOf(standalone) != CompositeMix {
+		t.Error("CompositeLayoutOf doesn't follow the extended profile")
+	}
+	if got := DeviceOf(garlic); got.ScreenWidth != 1024 || got.Rotation != 270 {
+		t.Errorf("DeviceOf(extends) = %+v", got)
+	}
+	if got := DeviceOf(standalone); got.ScreenWidth != 640 || got.ScreenHeight != 480 {
+		t.Errorf("DeviceOf(standalone) = %+v", got)
+	}
+	if HiddenFolderPrefixOf(garlic) != "_" || HiddenFolderPrefixOf(standalone) != "." {
+		t.Error("HiddenFolderPrefixOf doesn't follow the extended profile")
+	}
+	if dirs, ok := RetroArchDirectoriesOf(garlic); !ok || dirs.Playlists != "/base/RetroArch/playlist" {
+		t.Errorf("RetroArchDirectoriesOf(extends) = %+v, %v", dirs, ok)
+	}
+	if _, ok := RetroArchDirectoriesOf(standalone); ok {
+		t.Error("RetroArchDirectoriesOf(standalone) reports RetroArch")
+	}
+	if got := SaveDirectoryLabelOf(garlic, "mGBA/backup"); got != "mGBA" {
+		t.Errorf("SaveDirectoryLabelOf(extends) = %q", got)
+	}
+	if got := SaveDirectoryLabelOf(standalone, "Saves/GBA"); got != "GBA" {
+		t.Errorf("SaveDirectoryLabelOf(standalone) = %q", got)
+	}
+}
@@ -0,0 +1,259 @@
+// Package profile describes what Grout needs to know about a custom firmware: where it
+// keeps ROMs, BIOS files, saves and art, how it learns about new games, and how Grout
+// is installed on it. Each CFW package provides a Profile, and users can define more
+// in JSON without a new release.
+package profile
+
+import (
+	"grout/internal/multidisc"
+	"path/filepath"
+)
+
+// Metadata is how a CFW is told about the games Grout downloads.
+type Metadata string
+
+const (
+	MetadataNone          Metadata = "none"
+	MetadataGamelist      Metadata = "gamelist"      // EmulationStation gamelist.xml
+	MetadataMiyooGamelist Metadata = "miyoogamelist" // miyoogamelist.xml
+	MetadataMuOS          Metadata = "muos"          // muOS catalogue text files
+	MetadataMapTxt        Metadata = "maptxt"        // MinUI-style map.txt display names
+)
+
+func (m Metadata) valid() bool {
+	switch m {
+	case MetadataNone, MetadataGamelist, MetadataMiyooGamelist, MetadataMuOS, MetadataMapTxt:
+		return true
+	default:
+		return false
+	}
+}
+
+// ArtDirectories are where a CFW looks for each kind of media of one platform.
+// Empty fields mean the CFW has nowhere to show that kind of media.
+type ArtDirectories struct {
+	Box       string `json:"box,omitempty"`
+	Preview   string `json:"preview,omitempty"`
+	Splash    string `json:"splash,omitempty"`
+	Marquee   string `json:"marquee,omitempty"`
+	Video     string `json:"video,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+	Bezel     string `json:"bezel,omitempty"`
+	Manual    string `json:"manual,omitempty"`
+	Boxback   string `json:"boxback,omitempty"`
+	Fanart    string `json:"fanart,omitempty"`
+}
+
+// Install describes how Grout is packaged for a CFW.
+type Install struct {
+	// Asset is the release asset to update from. Empty disables in-app updates.
+	Asset string
+	// LaunchScript is the script that starts Grout, relative to the install root.
+	LaunchScript string
+	// Depth is how many directories the Grout binary sits below the install root.
+	Depth int
+}
+
+// Profile is everything Grout needs to know about one CFW.
+type Profile interface {
+	// ID is the value of the CFW environment variable that selects this profile.
+	ID() string
+	RomDirectory() string
+	BIOSDirectory() string
+	BaseSavePath() string
+	// Platforms maps RomM filesystem slugs to the ROM folders the CFW uses for them.
+	Platforms() map[string][]string
+	// SaveDirectories maps RomM filesystem slugs to emulator save folders under BaseSavePath.
+	SaveDirectories() map[string][]string
+	ArtDirectories(romDir, platformFSSlug, platformName string) ArtDirectories
+	Metadata() Metadata
+	Install() Install
+	// KeepsRomExt is the CFW's default save naming style, see cfw.SaveBasename.
+	KeepsRomExt() bool
+}
+
+// BIOSPathResolver is implemented by profiles that keep BIOS files in more than one
+// place per platform instead of a single BIOS folder.
+type BIOSPathResolver interface {
+	BIOSFilePaths(relativePath, platformFSSlug string) []string
+}
+
+// FolderMatcher is implemented by profiles whose ROM folder names carry more than the
+// platform, e.g. "Game Boy Advance (GBA)", and need reducing before matching.
+type FolderMatcher interface {
+	RomFolderBase(path string, tagParser func(string) string) string
+}
+
+// GamelistLauncher is implemented by profiles that list Grout itself as a game.
+type GamelistLauncher interface {
+	// GroutGamelist returns the gamelist Grout adds itself to and the launch path it uses.
+	GroutGamelist() (gamelistPath, launchPath string)
+}
+
+// RetroArchDirectories are where RetroArch looks for playlists, thumbnails and cores.
+type RetroArchDirectories struct {
+	Playlists  string
+	Thumbnails string
+	Cores      string
+}
+
+// RetroArchFrontend is implemented by profiles where RetroArch is a launcher users
+// browse games in.
+type RetroArchFrontend interface {
+	// RetroArchDirectories returns RetroArch's directories, and false when it has none.
+	RetroArchDirectories() (RetroArchDirectories, bool)
+}
+
+// MultiDiscLayouter is implemented by profiles whose frontend expects the discs of a
+// multi-disc game somewhere other than a hidden subfolder next to its .m3u.
+type MultiDiscLayouter interface {
+	MultiDiscLayout() multidisc.Layout
+}
+
+// CompositeLayout is the shape of the mix images Grout renders for a frontend.
+type CompositeLayout int
+
+const (
+	CompositeMix    CompositeLayout = iota // screenshot with the box and logo on top
+	CompositeSquare                        // square art for list views that show it beside the names
+)
+
+// CompositeLayouter is implemented by profiles whose frontend shows art in a shape
+// other than the default mix image.
+type CompositeLayouter interface {
+	CompositeLayout() CompositeLayout
+}
+
+// Device is what Grout needs to know about the handheld a CFW is running on.
+type Device struct {
+	// ScreenWidth and ScreenHeight size the art written for the frontend.
+	ScreenWidth  int
+	ScreenHeight int
+	// Rotation is how many degrees clockwise the UI is turned to appear upright.
+	Rotation int
+	// GamepadOnly ignores the keyboard and joystick the device reports but doesn't have.
+	GamepadOnly bool
+}
+
+// DeviceDetector is implemented by profiles that know more about their device than
+// the default 640x480 screen.
+type DeviceDetector interface {
+	Device() Device
+}
+
+// HiddenFolderPrefixer is implemented by profiles whose frontend hides folders that
+// start with something other than ".".
+type HiddenFolderPrefixer interface {
+	HiddenFolderPrefix() string
+}
+
+// SaveDirectoryLabeler is implemented by profiles whose save folder paths need trimming
+// before they make sense as an emulator name.
+type SaveDirectoryLabeler interface {
+	SaveDirectoryLabel(dir string) string
+}
+
+// NextUIThemed is implemented by profiles that run under NextUI's theme and power
+// button handling.
+type NextUIThemed interface {
+	NextUITheme() bool
+}
+
+// InputMapper is implemented by profiles that ship their own controller mapping.
+type InputMapper interface {
+	// InputMapping returns the mapping JSON, or nil to use the default mapping.
+	InputMapping() ([]byte, error)
+}
+
+// ArtFileNamer is implemented by profiles whose frontend finds a game's art by a name
+// other than the game's file name without its extension.
+type ArtFileNamer interface {
+	// ArtFileName returns the art file name for a game named gameName (no extension)
+	// whose ROM file is romFileName, which may be empty.
+	ArtFileName(gameName, romFileName string) string
+}
+
+// GamelistLocator is implemented by profiles whose frontend reads a ROM folder's
+// gamelist from somewhere other than the ROM folder itself.
+type GamelistLocator interface {
+	GamelistDirectory(romDir string) string
+}
+
+// RetroArchDirectoriesOf returns RetroArch's directories on p, and false when RetroArch
+// isn't a launcher there.
+func RetroArchDirectoriesOf(p Profile) (RetroArchDirectories, bool) {
+	if f, ok := p.(RetroArchFrontend); ok {
+		return f.RetroArchDirectories()
+	}
+	return RetroArchDirectories{}, false
+}
+
+// MultiDiscLayoutOf returns where p expects the discs of a multi-disc game.
+func MultiDiscLayoutOf(p Profile) multidisc.Layout {
+	if l, ok := p.(MultiDiscLayouter); ok {
+		return l.MultiDiscLayout()
+	}
+	return multidisc.LayoutHiddenSubfolder
+}
+
+// CompositeLayoutOf returns the layout used when Grout renders mix images for p.
+func CompositeLayoutOf(p Profile) CompositeLayout {
+	if l, ok := p.(CompositeLayouter); ok {
+		return l.CompositeLayout()
+	}
+	return CompositeMix
+}
+
+// DeviceOf returns the device p is running on.
+func DeviceOf(p Profile) Device {
+	if d, ok := p.(DeviceDetector); ok {
+		return d.Device()
+	}
+	return Device{ScreenWidth: 640, ScreenHeight: 480}
+}
+
+// HiddenFolderPrefixOf returns the prefix that hides a folder from p's frontend.
+func HiddenFolderPrefixOf(p Profile) string {
+	if h, ok := p.(HiddenFolderPrefixer); ok {
+		return h.HiddenFolderPrefix()
+	}
+	return "."
+}
+
+// SaveDirectoryLabelOf returns the name p's save folder dir is shown under.
+func SaveDirectoryLabelOf(p Profile, dir string) string {
+	if l, ok := p.(SaveDirectoryLabeler); ok {
+		dir = l.SaveDirectoryLabel(dir)
+	}
+	return filepath.Base(dir)
+}
+
+// UsesNextUITheme reports whether p runs under NextUI's theme.
+func UsesNextUITheme(p Profile) bool {
+	t, ok := p.(NextUIThemed)
+	return ok && t.NextUITheme()
+}
+
+// InputMappingOf returns p's controller mapping, or nil when it uses the default one.
+func InputMappingOf(p Profile) ([]byte, error) {
+	if m, ok := p.(InputMapper); ok {
+		return m.InputMapping()
+	}
+	return nil, nil
+}
+
+// ArtFileNameOf returns the name p's frontend looks for a game's art under.
+func ArtFileNameOf(p Profile, gameName, romFileName string) string {
+	if n, ok := p.(ArtFileNamer); ok {
+		return n.ArtFileName(gameName, romFileName)
+	}
+	return gameName + ".png"
+}
+
+// GamelistDirectoryOf returns the folder p's frontend reads romDir's gamelist from.
+func GamelistDirectoryOf(p Profile, romDir string) string {
+	if l, ok := p.(GamelistLocator); ok {
+		return l.GamelistDirectory(romDir)
+	}
+	return romDir
+}
@@ -0,0 +1,114 @@
+package cfw
+
+import (
+	"grout/cfw/allium"
+	"grout/cfw/arkos"
+	"grout/cfw/batocera"
+	"grout/cfw/knulli"
+	"grout/cfw/koriki"
+	"grout/cfw/linux"
+	"grout/cfw/minui"
+	"grout/cfw/muos"
+	"grout/cfw/nextui"
+	"grout/cfw/onion"
+	"grout/cfw/profile"
+	"grout/cfw/rocknix"
+	"grout/cfw/spruce"
+	"grout/cfw/trimui"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// ProfilesDirectory holds user-supplied CFW profiles, one JSON file each, relative to
+// the Grout install directory.
+var ProfilesDirectory = filepath.Join("overrides", "cfw", "profiles")
+
+var builtinProfiles = []profile.Profile{
+	nextui.Profile{},
+	muos.Profile{},
+	knulli.Profile{},
+	spruce.Profile{},
+	rocknix.Profile{},
+	trimui.Profile{},
+	allium.Profile{},
+	onion.Profile{},
+	koriki.Profile{},
+	arkos.Profile{},
+	batocera.Profile{},
+	minui.Profile{},
+	linux.Profile{},
+}
+
+var (
+	registryMu         sync.RWMutex
+	registry           = make(map[CFW]profile.Profile)
+	customProfilesOnce sync.Once
+)
+
+func init() {
+	for _, p := range builtinProfiles {
+		Register(p)
+	}
+}
+
+// Register makes a profile available as the CFW named by its ID, replacing any
+// profile already registered under that name.
+func Register(p profile.Profile) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[CFW(strings.ToUpper(p.ID()))] = p
+}
+
+// Profile returns the profile registered for the CFW, or nil when there is none.
+func (c CFW) Profile() profile.Profile {
+	customProfilesOnce.Do(loadCustomProfiles)
+	p, _ := lookupProfile(string(c))
+	return p
+}
+
+func lookupProfile(id string) (profile.Profile, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	p, ok := registry[CFW(id)]
+	return p, ok
+}
+
+// Registered returns the names of all registered CFWs, sorted.
+func Registered() []CFW {
+	customProfilesOnce.Do(loadCustomProfiles)
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]CFW, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// loadCustomProfiles registers the profiles in ProfilesDirectory. A custom profile
+// with the same ID as a built-in one replaces it. Files are read in name order so a
+// profile can extend one defined in an earlier file.
+func loadCustomProfiles() {
+	entries, err := os.ReadDir(ProfilesDirectory)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			continue
+		}
+		p, err := profile.Load(filepath.Join(ProfilesDirectory, entry.Name()), lookupProfile)
+		if err != nil {
+			gaba.GetLogger().Warn("Skipping CFW profile", "file", entry.Name(), "error", err)
+			continue
+		}
+		Register(p)
+	}
+}
@@ -0,0 +1,14 @@
+package cfw
+
+import (
+	"grout/cfw/profile"
+)
+
+// RetroArchDirectories are where RetroArch on this CFW looks for playlists, thumbnails and cores.
+type RetroArchDirectories = profile.RetroArchDirectories
+
+// RetroArchDirectories returns RetroArch's directories on CFWs where RetroArch is the
+// launcher users browse games in, and false everywhere else.
+func (c CFW) RetroArchDirectories() (RetroArchDirectories, bool) {
+	return profile.RetroArchDirectoriesOf(c.Profile())
+}
@@ -0,0 +1,84 @@
+package rocknix
+
+import (
+	"grout/cfw/profile"
+	"grout/internal/multidisc"
+	"path/filepath"
+)
+
+// Profile describes ROCKNIX to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "ROCKNIX"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+// SaveDirectories returns the platform map, as ROCKNIX keeps saves alongside the ROMs.
+func (Profile) SaveDirectories() map[string][]string {
+	return Platforms
+}
+
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{
+		Box:       GetArtDirectory(romDir),
+		Marquee:   GetArtDirectory(romDir),
+		Video:     GetVideoDirectory(romDir),
+		Thumbnail: GetArtDirectory(romDir),
+		Bezel:     GetBezelDirectory(romDir),
+		Manual:    GetManualDirectory(romDir),
+		Boxback:   GetArtDirectory(romDir),
+		Fanart:    GetArtDirectory(romDir),
+	}
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataGamelist
+}
+
+func (Profile) Install() profile.Install {
+	return profile.Install{Asset: "Grout-ROCKNIX.zip", LaunchScript: "Grout.sh", Depth: 2}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return false
+}
+
+func (Profile) GroutGamelist() (string, string) {
+	return GetGroutGamelist(), "./Grout.sh"
+}
+
+func (Profile) MultiDiscLayout() multidisc.Layout {
+	return multidisc.LayoutHiddenDir
+}
+
+func (Profile) Device() profile.Device {
+	return profile.Device{ScreenWidth: 1280, ScreenHeight: 720}
+}
+
+func (Profile) RetroArchDirectories() (profile.RetroArchDirectories, bool) {
+	return profile.RetroArchDirectories{
+		Playlists:  filepath.Join(GetRetroArchDirectory(), "playlists"),
+		Thumbnails: filepath.Join(GetRetroArchDirectory(), "thumbnails"),
+		Cores:      GetCoreDirectory(),
+	}, true
+}
+
+func (Profile) InputMapping() ([]byte, error) {
+	return GetInputMappingBytes()
+}
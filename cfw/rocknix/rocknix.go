@@ -53,3 +53,12 @@ func GetManualDirectory(romDir string) string {
 func GetBezelDirectory(romDir string) string {
 	return filepath.Join(romDir, "bezels")
 }
+
+// GetRetroArchDirectory returns the directory holding RetroArch's playlists and thumbnails.
+func GetRetroArchDirectory() string {
+	return GetBasePath()
+}
+
+func GetCoreDirectory() string {
+	return "/tmp/cores"
+}
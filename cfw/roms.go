@@ -2,6 +2,7 @@ package cfw
 
 import (
 	"grout/internal/fileutil"
+	"grout/internal/multidisc"
 	"grout/internal/stringutil"
 	"os"
 	"path/filepath"
@@ -57,7 +58,7 @@ func scanRomsByPlatform(baseRomDir string, platformMap map[string][]string, conf
 	logger := gaba.GetLogger()
 	result := make(map[string][]LocalRomFile)
 
-	if currentCFW == NextUI {
+	if currentCFW.matchesRomFoldersByTag() {
 		entries, err := os.ReadDir(baseRomDir)
 		if err != nil {
 			logger.Error("Failed to read ROM directory", "path", baseRomDir, "error", err)
@@ -193,5 +194,18 @@ func scanRomDirectory(fsSlug, romDir string) []LocalRomFile {
 		roms = append(roms, rom)
 	}
 
+	// NextUI and MinUI keep multi-disc games in a folder holding "<folder>/<folder>.m3u"
+	for _, entry := range fileutil.FilterHiddenDirectories(entries) {
+		playlist := entry.Name() + multidisc.PlaylistExtension
+		playlistPath := filepath.Join(romDir, entry.Name(), playlist)
+		if fileutil.FileExists(playlistPath) {
+			roms = append(roms, LocalRomFile{
+				FSSlug:   fsSlug,
+				FileName: playlist,
+				FilePath: playlistPath,
+			})
+		}
+	}
+
 	return roms
 }
@@ -1,18 +1,6 @@
 package cfw
 
 import (
-	"grout/cfw/allium"
-	"grout/cfw/arkos"
-	"grout/cfw/batocera"
-	"grout/cfw/knulli"
-	"grout/cfw/koriki"
-	"grout/cfw/minui"
-	"grout/cfw/muos"
-	"grout/cfw/nextui"
-	"grout/cfw/onion"
-	"grout/cfw/rocknix"
-	"grout/cfw/spruce"
-	"grout/cfw/trimui"
 	"path/filepath"
 	"strings"
 )
@@ -38,44 +26,17 @@ func SaveBasename(keepRomExt bool, romFileName string) string {
 // CFWs (NextUI, MinUI) default to keeping the ROM extension; all others default to the
 // RetroArch convention of stripping it (issue #245).
 func DefaultKeepsRomExt(c CFW) bool {
-	switch c {
-	case NextUI, MinUI:
-		return true
-	default:
-		return false
-	}
+	p := c.Profile()
+	return p != nil && p.KeepsRomExt()
 }
 
 // EmulatorFolderMap returns the emulator/save directory mapping for the given CFW.
 func EmulatorFolderMap(c CFW) map[string][]string {
-	switch c {
-	case MuOS:
-		return muos.SaveDirectories
-	case NextUI:
-		return nextui.SaveDirectories
-	case Knulli:
-		return knulli.SaveDirectories
-	case Spruce:
-		return spruce.SaveDirectories
-	case ROCKNIX:
-		return rocknix.Platforms // ROCKNIX stores saves alongside ROMs
-	case ArkOS:
-		return arkos.Platforms // ArkOS stores saves alongside ROMs
-	case Allium:
-		return allium.SaveDirectories
-	case Onion:
-		return onion.SaveDirectories
-	case Koriki:
-		return koriki.SaveDirectories
-	case Trimui:
-		return trimui.SaveDirectories
-	case Batocera:
-		return batocera.Platforms
-	case MinUI:
-		return minui.SaveDirectories
-	default:
+	p := c.Profile()
+	if p == nil {
 		return nil
 	}
+	return p.SaveDirectories()
 }
 
 // EmulatorFoldersForFSSlug returns the emulator folders for a given filesystem slug.
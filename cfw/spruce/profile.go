@@ -0,0 +1,63 @@
+package spruce
+
+import (
+	"grout/cfw/profile"
+)
+
+// Profile describes Spruce to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "SPRUCE"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+func (Profile) SaveDirectories() map[string][]string {
+	return SaveDirectories
+}
+
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{Box: GetArtDirectory(romDir)}
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataMiyooGamelist
+}
+
+func (Profile) Install() profile.Install {
+	return profile.Install{Asset: "Grout.spruce.zip", LaunchScript: "Grout/launch.sh", Depth: 3}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return false
+}
+
+func (Profile) Device() profile.Device {
+	switch DetectDevice() {
+	case DeviceTrimui:
+		return profile.Device{ScreenWidth: 1280, ScreenHeight: 720}
+	case DeviceA30:
+		return profile.Device{ScreenWidth: 640, ScreenHeight: 480, Rotation: 270}
+	default:
+		return profile.Device{ScreenWidth: 640, ScreenHeight: 480}
+	}
+}
+
+func (Profile) InputMapping() ([]byte, error) {
+	return GetInputMappingBytes()
+}
@@ -0,0 +1,52 @@
+package trimui
+
+import (
+	"grout/cfw/profile"
+)
+
+// Profile describes the stock TrimUI firmware to the rest of Grout.
+type Profile struct{}
+
+func (Profile) ID() string {
+	return "TRIMUI"
+}
+
+func (Profile) RomDirectory() string {
+	return GetRomDirectory()
+}
+
+func (Profile) BIOSDirectory() string {
+	return GetBIOSDirectory()
+}
+
+func (Profile) BaseSavePath() string {
+	return GetBaseSavePath()
+}
+
+func (Profile) Platforms() map[string][]string {
+	return Platforms
+}
+
+func (Profile) SaveDirectories() map[string][]string {
+	return SaveDirectories
+}
+
+func (Profile) ArtDirectories(romDir, platformFSSlug, platformName string) profile.ArtDirectories {
+	return profile.ArtDirectories{Box: GetArtDirectory(platformFSSlug, platformName)}
+}
+
+func (Profile) Metadata() profile.Metadata {
+	return profile.MetadataNone
+}
+
+func (Profile) Install() profile.Install {
+	return profile.Install{Asset: "Grout-Trimui.zip", LaunchScript: "Grout/launch.sh", Depth: 3}
+}
+
+func (Profile) KeepsRomExt() bool {
+	return false
+}
+
+func (Profile) Device() profile.Device {
+	return profile.Device{ScreenWidth: 1280, ScreenHeight: 720}
+}
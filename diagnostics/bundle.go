@@ -0,0 +1,218 @@
+// Package diagnostics collects the state Grout needs for a bug report into a single zip:
+// recent logs, the redacted config, CFW detection, cache and save sync state, the last
+// negotiate exchange and a summary of the ROMs on the device.
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"grout/cache"
+	"grout/cfw"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"grout/sync"
+	"grout/version"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// Directory is where bundles are written, inside Grout's own folder on the SD card.
+const Directory = "diagnostics"
+
+const (
+	logsDirectory = "logs"
+
+	// maxLogBytes keeps the newest part of each log; older lines rarely matter and a
+	// debug log can grow large.
+	maxLogBytes = 2 << 20
+
+	// maxLogAge skips logs from sessions too old to be relevant.
+	maxLogAge = 7 * 24 * time.Hour
+
+	maxHistoryRows       = 200
+	maxUnresolvedPerSlug = 25
+)
+
+// entry is one file in the bundle.
+type entry struct {
+	name string
+	data []byte
+}
+
+// Summary identifies the build and device the bundle came from.
+type Summary struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Version   version.BuildInfo `json:"version"`
+	CFW       cfw.CFW           `json:"cfw"`
+	OS        string            `json:"os"`
+	Arch      string            `json:"arch"`
+	DeviceID  string            `json:"device_id,omitempty"`
+}
+
+// PlatformScan is how many ROM files were found for a platform and how many of them
+// matched a game in the cache.
+type PlatformScan struct {
+	Files      int      `json:"files"`
+	Resolved   int      `json:"resolved"`
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// Export writes a bundle to Directory and returns its path.
+func Export(config *internal.Config, host romm.Host) (string, error) {
+	if err := os.MkdirAll(Directory, 0755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", Directory, err)
+	}
+
+	path := filepath.Join(Directory, "grout-diagnostics-"+time.Now().Format("20060102-150405")+".zip")
+
+	var buf bytes.Buffer
+	if err := writeZip(&buf, collect(config, host)); err != nil {
+		return "", err
+	}
+	if err := fileutil.WriteFileAtomic(path, buf.Bytes(), 0644); err != nil {
+		return "", err
+	}
+
+	gaba.GetLogger().Info("Exported diagnostics", "path", path, "bytes", buf.Len())
+	return path, nil
+}
+
+func collect(config *internal.Config, host romm.Host) []entry {
+	logger := gaba.GetLogger()
+	var entries []entry
+
+	addJSON := func(name string, value any) {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			logger.Warn("Failed to add diagnostics entry", "name", name, "error", err)
+			return
+		}
+		entries = append(entries, entry{name: name, data: data})
+	}
+
+	addJSON("summary.json", Summary{
+		CreatedAt: time.Now().UTC(),
+		Version:   version.Get(),
+		CFW:       cfw.GetCFW(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		DeviceID:  host.DeviceID,
+	})
+	addJSON("config.json", config.ToLoggable())
+
+	var detection bytes.Buffer
+	cfw.DetectCFW().WriteReport(&detection)
+	entries = append(entries, entry{name: "cfw_detection.txt", data: detection.Bytes()})
+
+	cm := cache.GetCacheManager()
+	addJSON("cache.json", cm.Diagnostics())
+
+	history := cm.GetSaveSyncHistory(host.DeviceID)
+	if len(history) > maxHistoryRows {
+		history = history[:maxHistoryRows]
+	}
+	addJSON("save_sync_history.json", history)
+	addJSON("save_sync_state.json", cm.GetSaveStates(host.DeviceID))
+
+	if data, err := os.ReadFile(sync.LastNegotiateFile); err == nil {
+		entries = append(entries, entry{name: "last_negotiate.json", data: data})
+	}
+
+	addJSON("rom_scan.json", summarizeScan(cfw.ScanRoms(config)))
+
+	entries = append(entries, recentLogs(logsDirectory, time.Now())...)
+
+	return entries
+}
+
+func summarizeScan(scan cfw.LocalRomScan) map[string]PlatformScan {
+	resolved := sync.ResolveLocalRoms(scan)
+	resolvedFiles := make(map[string]bool, len(resolved))
+	for _, file := range resolved {
+		resolvedFiles[file.FilePath] = true
+	}
+
+	summary := make(map[string]PlatformScan, len(scan))
+	for slug, files := range scan {
+		platform := PlatformScan{Files: len(files)}
+		for _, file := range files {
+			if resolvedFiles[file.FilePath] {
+				platform.Resolved++
+			} else if len(platform.Unresolved) < maxUnresolvedPerSlug {
+				platform.Unresolved = append(platform.Unresolved, file.FileName)
+			}
+		}
+		summary[slug] = platform
+	}
+	return summary
+}
+
+// recentLogs returns the tail of every log modified within maxLogAge of now.
+func recentLogs(dir string, now time.Time) []entry {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var entries []entry
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil || now.Sub(info.ModTime()) > maxLogAge {
+			continue
+		}
+		data, err := tailFile(filepath.Join(dir, file.Name()), maxLogBytes)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{name: "logs/" + file.Name(), data: data})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	return entries
+}
+
+// tailFile reads at most limit bytes from the end of a file.
+func tailFile(path string, limit int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > limit {
+		if _, err := f.Seek(info.Size()-limit, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(f)
+}
+
+func writeZip(w io.Writer, entries []entry) error {
+	archive := zip.NewWriter(w)
+	for _, e := range entries {
+		f, err := archive.Create(e.name)
+		if err != nil {
+			return fmt.Errorf("adding %s: %w", e.name, err)
+		}
+		if _, err := f.Write(e.data); err != nil {
+			return fmt.Errorf("adding %s: %w", e.name, err)
+		}
+	}
+	return archive.Close()
+}
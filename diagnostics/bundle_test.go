@@ -0,0 +1,77 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTailFile_KeepsNewestBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grout.log")
+	if err := os.WriteFile(path, []byte("old line\nnew line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := tailFile(path, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new line\n" {
+		t.Errorf("tail = %q", data)
+	}
+}
+
+func TestRecentLogs_SkipsOldLogs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for name, age := range map[string]time.Duration{"recent.log": time.Hour, "stale.log": 30 * 24 * time.Hour} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries := recentLogs(dir, now)
+	if len(entries) != 1 || entries[0].name != "logs/recent.log" {
+		t.Errorf("entries = %v", entries)
+	}
+}
+
+func TestWriteZip(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeZip(&buf, []entry{
+		{name: "summary.json", data: []byte(`{"cfw":"NEXTUI"}`)},
+		{name: "logs/grout.log", data: []byte("hello")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+		if f.Name == "logs/grout.log" {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			if string(data) != "hello" {
+				t.Errorf("log contents = %q", data)
+			}
+		}
+	}
+	if strings.Join(names, ",") != "summary.json,logs/grout.log" {
+		t.Errorf("names = %v", names)
+	}
+}
@@ -8,6 +8,7 @@ const (
 	ArtKindBox2D      ArtKind = "Box2D"
 	ArtKindBox3D      ArtKind = "Box3D"
 	ArtKindMixImage   ArtKind = "Miximage"
+	ArtKindComposite  ArtKind = "Composite"
 	ArtKindMarquee    ArtKind = "Marquee"
 	ArtKindLogo       ArtKind = "Logo"
 	ArtKindTitle      ArtKind = "Title"
@@ -6,6 +6,8 @@ import (
 	"grout/cache"
 	"grout/cfw"
 	"grout/internal/artutil"
+	"grout/internal/fileutil"
+	"grout/internal/romset"
 	"grout/romm"
 	"os"
 	"sync/atomic"
@@ -28,7 +30,7 @@ type AdditionalDownloads struct {
 }
 
 // DurationSeconds is a time.Duration that marshals to/from JSON as whole seconds.
-// Existing configs with nanosecond values are handled by detecting large values on unmarshal.
+// Configs from before config_version stored nanoseconds; migrateConfigV0 converts them.
 type DurationSeconds time.Duration
 
 func (d DurationSeconds) MarshalJSON() ([]byte, error) {
@@ -40,13 +42,7 @@ func (d *DurationSeconds) UnmarshalJSON(b []byte) error {
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return err
 	}
-	// Values over 1,000,000 are nanoseconds from old configs (e.g. 1800000000000 = 30min).
-	// Convert them to the equivalent duration directly.
-	if raw > 1_000_000 {
-		*d = DurationSeconds(time.Duration(raw))
-	} else {
-		*d = DurationSeconds(time.Duration(raw) * time.Second)
-	}
+	*d = DurationSeconds(time.Duration(raw) * time.Second)
 	return nil
 }
 
@@ -55,6 +51,7 @@ func (d DurationSeconds) Duration() time.Duration {
 }
 
 type Config struct {
+	ConfigVersion                int                         `json:"config_version"`
 	Hosts                        []romm.Host                 `json:"hosts,omitempty"`
 	DirectoryMappings            map[string]DirectoryMapping `json:"directory_mappings,omitempty"`
 	DownloadArt                  bool                        `json:"download_art,omitempty"`
@@ -75,6 +72,13 @@ type Config struct {
 	DownloadArtScreenshotPreview bool                        `json:"download_art_screenshot_preview,omitempty"`
 	DownloadSplashArt            artutil.ArtKind             `json:"download_splash_art,omitempty"`
 	AdditionalDownloads          AdditionalDownloads         `json:"additional_downloads,omitempty"`
+	OneGameOneRom                bool                        `json:"one_game_one_rom,omitempty"`
+	RegionPriority               []string                    `json:"region_priority,omitempty"`
+	LanguagePriority             []string                    `json:"language_priority,omitempty"`
+	ArtworkCacheSizeMB           int                         `json:"artwork_cache_size_mb,omitempty"` // 0 = default, -1 = unlimited
+	RetroArchPlaylists           bool                        `json:"retroarch_playlists,omitempty"`
+	RefreshIntervalMinutes       int                         `json:"refresh_interval_minutes,omitempty"` // 0 = default, -1 = off
+	LiveUpdates                  bool                        `json:"live_updates,omitempty"`
 
 	SwapFaceButtons       bool              `json:"swap_face_buttons,omitempty"`
 	PlatformOrder         []string          `json:"platform_order,omitempty"`
@@ -83,6 +87,9 @@ type Config struct {
 	SaveBackupLimit       int               `json:"save_backup_limit,omitempty"` // 0 = no limit, 5/10/15 = keep N most recent per game
 
 	PlatformsBinding map[string]string `json:"-"`
+
+	// Problems found while loading config.json; see Validate.
+	Problems []ConfigProblem `json:"-"`
 }
 
 type DirectoryMapping struct {
@@ -90,115 +97,128 @@ type DirectoryMapping struct {
 	RelativePath string `json:"relative_path"`
 }
 
+// ToLoggable returns every setting, including the ones kept outside config.json, with
+// host secrets masked.
 func (c Config) ToLoggable() any {
 	safeHosts := make([]map[string]any, len(c.Hosts))
 	for i, host := range c.Hosts {
 		safeHosts[i] = host.ToLoggable()
 	}
 
-	return map[string]any{
-		"hosts":                   safeHosts,
-		"directory_mappings":      c.DirectoryMappings,
-		"api_timeout":             c.ApiTimeout,
-		"download_timeout":        c.DownloadTimeout,
-		"unzip_downloads":         c.UnzipDownloads,
-		"download_art":            c.DownloadArt,
-		"art_kind":                c.ArtKind,
-		"show_box_art":            c.ShowBoxArt,
-		"collections":             c.ShowRegularCollections,
-		"smart_collections":       c.ShowSmartCollections,
-		"virtual_collections":     c.ShowVirtualCollections,
-		"downloaded_games_action": c.DownloadedGames,
-		"log_level":               c.LogLevel,
+	loggable := map[string]any{}
+	if data, err := json.Marshal(c); err == nil {
+		_ = json.Unmarshal(data, &loggable)
 	}
+	loggable["hosts"] = safeHosts
+	loggable["slot_preferences"] = c.SlotPreferences
+	loggable["platforms_binding"] = c.PlatformsBinding
+
+	return loggable
 }
 
+const (
+	configFile       = "config.json"
+	configBackupFile = "config.json.bak"
+)
+
+// LoadConfig reads config.json, migrates it to CurrentConfigVersion and validates it.
+// Problems found are put on Config.Problems for the caller to report. If config.json is
+// corrupt, the backup kept by SaveConfig is used instead.
 func LoadConfig() (*Config, error) {
-	data, err := os.ReadFile("config.json")
+	data, err := os.ReadFile(configFile)
 	if err != nil {
-		return nil, fmt.Errorf("reading config.json: %w", err)
+		return nil, fmt.Errorf("reading %s: %w", configFile, err)
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("parsing config.json: %w", err)
-	}
-
-	if config.ApiTimeout == 0 {
-		config.ApiTimeout = DurationSeconds(30 * time.Second)
-	}
-
-	if config.DownloadTimeout == 0 {
-		config.DownloadTimeout = DurationSeconds(60 * time.Minute)
-	}
-
-	// Clamp API timeout to valid picker range (15s–300s)
-	if config.ApiTimeout.Duration() > 300*time.Second {
-		config.ApiTimeout = DurationSeconds(30 * time.Second)
+	config, err := parseConfig(data)
+	if err != nil {
+		backup, backupErr := os.ReadFile(configBackupFile)
+		if backupErr != nil {
+			return nil, fmt.Errorf("parsing %s: %w", configFile, err)
+		}
+		config, backupErr = parseConfig(backup)
+		if backupErr != nil {
+			return nil, fmt.Errorf("parsing %s: %w", configFile, err)
+		}
+		gaba.GetLogger().Warn("config.json is corrupt, restored from backup", "error", err)
+		config.Problems = append(config.Problems, ConfigProblem{
+			Field:   configFile,
+			Value:   configBackupFile,
+			Problem: fmt.Sprintf("could not be read (%v), loaded the backup instead", err),
+		})
 	}
 
-	if config.Language == "" {
-		config.Language = "en"
-	}
+	config.Problems = append(config.Problems, config.Validate(cfw.GetPlatformMap(cfw.GetCFW()))...)
 
-	if config.DownloadedGames == "" {
-		config.DownloadedGames = DownloadedGamesModeDoNothing
-	}
+	// Load slot preferences from dedicated file
+	config.SlotPreferences = LoadSlotPreferences()
 
-	if config.CollectionView == "" {
-		config.CollectionView = CollectionViewPlatform
-	}
+	return config, nil
+}
 
-	if config.ArtKind == "" {
-		config.ArtKind = artutil.ArtKindDefault
+func parseConfig(data []byte) (*Config, error) {
+	migrated, version, err := migrateConfig(data)
+	if err != nil {
+		return nil, err
 	}
 
-	if config.AdditionalDownloads.Thumbnail == "" {
-		config.AdditionalDownloads.Thumbnail = artutil.ArtKindNone
+	var config Config
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		return nil, err
 	}
 
-	if config.AdditionalDownloads.Marquee == "" {
-		config.AdditionalDownloads.Marquee = artutil.ArtKindNone
+	if version > CurrentConfigVersion {
+		config.Problems = append(config.Problems, ConfigProblem{
+			Field:   "config_version",
+			Value:   version,
+			Problem: fmt.Sprintf("written by a newer Grout, this one understands up to %d", CurrentConfigVersion),
+		})
 	}
 
-	// Load slot preferences from dedicated file
-	config.SlotPreferences = LoadSlotPreferences()
-
+	config.applyDefaults()
 	return &config, nil
 }
 
-func SaveConfig(config *Config) error {
-	if config.LogLevel == "" {
-		config.LogLevel = LogLevelError
+// applyDefaults fills in settings that are unset.
+func (c *Config) applyDefaults() {
+	if c.ApiTimeout == 0 {
+		c.ApiTimeout = DurationSeconds(30 * time.Second)
 	}
 
-	if config.Language == "" {
-		config.Language = "en"
+	if c.DownloadTimeout == 0 {
+		c.DownloadTimeout = DurationSeconds(60 * time.Minute)
 	}
 
-	if config.DownloadedGames == "" {
-		config.DownloadedGames = DownloadedGamesModeDoNothing
+	if c.LogLevel == "" {
+		c.LogLevel = LogLevelError
 	}
 
-	if config.CollectionView == "" {
-		config.CollectionView = CollectionViewPlatform
+	if c.Language == "" {
+		c.Language = "en"
 	}
 
-	if config.ReleaseChannel == "" {
-		config.ReleaseChannel = ReleaseChannelMatchRomM
+	if c.DownloadedGames == "" {
+		c.DownloadedGames = DownloadedGamesModeDoNothing
 	}
 
-	if config.ArtKind == "" {
-		config.ArtKind = artutil.ArtKindDefault
+	if c.CollectionView == "" {
+		c.CollectionView = CollectionViewPlatform
 	}
 
-	if config.AdditionalDownloads.Thumbnail == "" {
-		config.AdditionalDownloads.Thumbnail = artutil.ArtKindNone
+	if c.ReleaseChannel == "" {
+		c.ReleaseChannel = ReleaseChannelMatchRomM
 	}
 
-	if config.AdditionalDownloads.Marquee == "" {
-		config.AdditionalDownloads.Marquee = artutil.ArtKindNone
+	if c.ArtKind == "" {
+		c.ArtKind = artutil.ArtKindDefault
 	}
+}
+
+// SaveConfig writes config.json atomically, so a power loss mid-save leaves either the
+// old or the new file. The previous config.json is kept as config.json.bak.
+func SaveConfig(config *Config) error {
+	config.applyDefaults()
+	config.ConfigVersion = CurrentConfigVersion
 
 	gaba.SetRawLogLevel(string(config.LogLevel))
 
@@ -206,13 +226,15 @@ func SaveConfig(config *Config) error {
 		gaba.GetLogger().Error("Failed to set language", "error", err, "language", config.Language)
 	}
 
-	pretty, err := json.MarshalIndent(config, "", "  ")
+	pretty, err := json.MarshalIndent(config.persisted(), "", "  ")
 	if err != nil {
 		gaba.GetLogger().Error("Failed to marshal config to JSON", "error", err)
 		return err
 	}
 
-	if err := os.WriteFile("config.json", pretty, 0644); err != nil {
+	backupConfig()
+
+	if err := fileutil.WriteFileAtomic(configFile, pretty, 0644); err != nil {
 		gaba.GetLogger().Error("Failed to write config file", "error", err)
 		return err
 	}
@@ -220,6 +242,18 @@ func SaveConfig(config *Config) error {
 	return nil
 }
 
+// backupConfig copies config.json to config.json.bak, but only when it parses, so a
+// corrupt config never replaces a good backup.
+func backupConfig() {
+	current, err := os.ReadFile(configFile)
+	if err != nil || !json.Valid(current) {
+		return
+	}
+	if err := fileutil.WriteFileAtomic(configBackupFile, current, 0644); err != nil {
+		gaba.GetLogger().Warn("Failed to back up config file", "error", err)
+	}
+}
+
 func InitKidMode(config *Config) {
 	kidModeEnabled.Store(config.KidMode)
 }
@@ -278,7 +312,7 @@ func SaveSlotPreferences(config *Config) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile("save_slots.json", pretty, 0644)
+	return fileutil.WriteFileAtomic("save_slots.json", pretty, 0644)
 }
 
 func (c Config) GetSlotPreference(romID int) string {
@@ -324,6 +358,44 @@ func (c Config) GetShowCollections() bool        { return c.ShowRegularCollectio
 func (c Config) GetShowSmartCollections() bool   { return c.ShowSmartCollections }
 func (c Config) GetShowVirtualCollections() bool { return c.ShowVirtualCollections }
 
+// GetArtworkCacheBudget returns the artwork cache size limit in bytes, or 0 when unlimited.
+func (c Config) GetArtworkCacheBudget() int64 {
+	switch {
+	case c.ArtworkCacheSizeMB < 0:
+		return 0
+	case c.ArtworkCacheSizeMB == 0:
+		return cache.DefaultArtworkCacheBudget
+	default:
+		return int64(c.ArtworkCacheSizeMB) << 20
+	}
+}
+
+// GetRefreshInterval returns how often the cache is refreshed in the background, or 0
+// when periodic refreshes are off.
+func (c Config) GetRefreshInterval() time.Duration {
+	switch {
+	case c.RefreshIntervalMinutes < 0:
+		return 0
+	case c.RefreshIntervalMinutes == 0:
+		return DefaultRefreshInterval
+	default:
+		return time.Duration(c.RefreshIntervalMinutes) * time.Minute
+	}
+}
+
+// RomSetPreferences returns the region and language priorities used to pick the
+// preferred version of a game, falling back to the defaults when unset.
+func (c Config) RomSetPreferences() romset.Preferences {
+	prefs := romset.Preferences{Regions: c.RegionPriority, Languages: c.LanguagePriority}
+	if len(prefs.Regions) == 0 {
+		prefs.Regions = romset.DefaultRegionPriority
+	}
+	if len(prefs.Languages) == 0 {
+		prefs.Languages = romset.DefaultLanguagePriority
+	}
+	return prefs
+}
+
 // ResolveFSSlug returns the effective fs_slug for CFW lookups.
 // If the fs_slug has a binding in PlatformsBinding, the bound value is returned.
 // Otherwise, the original fs_slug is returned.
@@ -366,6 +438,11 @@ func (c Config) GetPlatformRomDirectory(platform romm.Platform) string {
 	return cfw.GetPlatformRomDirectory(rp, effectiveFSSlug)
 }
 
+// GetPlatformGamelistDirectory returns the folder holding the gamelist of a platform.
+func (c Config) GetPlatformGamelistDirectory(platform romm.Platform) string {
+	return cfw.GetGamelistDirectory(c.GetPlatformRomDirectory(platform))
+}
+
 func (c Config) GetArtDirectory(platform romm.Platform) string {
 	romDir := c.GetPlatformRomDirectory(platform)
 	return cfw.GetArtDirectory(romDir, platform.FSSlug, platform.Name)
@@ -406,6 +483,12 @@ func (c Config) GetManualDirectory(platform romm.Platform) string {
 	return cfw.GetManualDirectory(romDir, platform.FSSlug, platform.Name)
 }
 
+// GetFileCategoryDirectory returns where a ROM file of the given category is saved.
+func (c Config) GetFileCategoryDirectory(platform romm.Platform, category romm.FileCategory) string {
+	romDir := c.GetPlatformRomDirectory(platform)
+	return cfw.GetFileCategoryDirectory(romDir, c.ResolveFSSlug(platform.FSSlug), string(category))
+}
+
 func (c Config) GetFanartDirectory(platform romm.Platform) string {
 	romDir := c.GetPlatformRomDirectory(platform)
 	return cfw.GetFanartDirectory(romDir, platform.FSSlug, platform.Name)
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CurrentConfigVersion is the config_version this build writes. Bump it and append a
+// migration to configMigrations whenever the meaning of a stored field changes.
+const CurrentConfigVersion = 1
+
+// configMigration upgrades the raw JSON of a config by one version.
+type configMigration func(raw map[string]any) error
+
+// configMigrations[n] upgrades a version n config to version n+1. Configs written before
+// config_version existed are version 0.
+var configMigrations = []configMigration{
+	migrateConfigV0,
+}
+
+// migrateConfigV0 converts timeouts stored as nanoseconds, which older builds wrote,
+// into seconds. Later unversioned builds already wrote seconds, so the value's
+// magnitude tells them apart: no timeout Grout offers is over a million seconds.
+func migrateConfigV0(raw map[string]any) error {
+	for _, key := range []string{"api_timeout", "download_timeout"} {
+		value, ok := raw[key].(json.Number)
+		if !ok {
+			continue
+		}
+		n, err := value.Int64()
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if n > 1_000_000 {
+			raw[key] = json.Number(strconv.FormatInt(n/int64(time.Second), 10))
+		}
+	}
+	return nil
+}
+
+// migrateConfig brings raw config JSON up to CurrentConfigVersion and returns the
+// version it started at.
+func migrateConfig(data []byte) ([]byte, int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, 0, err
+	}
+
+	version := 0
+	if value, ok := raw["config_version"].(json.Number); ok {
+		n, err := value.Int64()
+		if err != nil || n < 0 {
+			return nil, 0, fmt.Errorf("invalid config_version %q", value)
+		}
+		version = int(n)
+	}
+	if version >= CurrentConfigVersion {
+		return data, version, nil
+	}
+
+	for v := version; v < CurrentConfigVersion; v++ {
+		if err := configMigrations[v](raw); err != nil {
+			return nil, version, fmt.Errorf("migrating config from version %d: %w", v, err)
+		}
+	}
+	raw["config_version"] = CurrentConfigVersion
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, version, err
+	}
+	return migrated, version, nil
+}
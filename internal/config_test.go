@@ -1,6 +1,13 @@
 package internal
 
-import "testing"
+import (
+	"encoding/json"
+	"grout/internal/artutil"
+	"grout/romm"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestSlotPreference_DefaultsToAutosave(t *testing.T) {
 	c := Config{}
@@ -25,3 +32,157 @@ func TestSetSlotPreference_AutosavePersistsAsExplicit(t *testing.T) {
 		t.Errorf("GetSlotPreference = %q, want autosave", got)
 	}
 }
+
+func TestMigrateConfig_ConvertsNanosecondTimeouts(t *testing.T) {
+	migrated, version, err := migrateConfig([]byte(`{"api_timeout": 30000000000, "download_timeout": 3600}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 0 {
+		t.Errorf("starting version = %d, want 0", version)
+	}
+
+	var config Config
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.ConfigVersion != CurrentConfigVersion {
+		t.Errorf("config_version = %d, want %d", config.ConfigVersion, CurrentConfigVersion)
+	}
+	if got := config.ApiTimeout.Duration(); got != 30*time.Second {
+		t.Errorf("api_timeout = %v, want 30s", got)
+	}
+	if got := config.DownloadTimeout.Duration(); got != time.Hour {
+		t.Errorf("download_timeout = %v, want 1h", got)
+	}
+}
+
+func TestMigrateConfig_CurrentVersionUntouched(t *testing.T) {
+	data := []byte(`{"config_version": 1, "api_timeout": 2000000}`)
+	migrated, _, err := migrateConfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(migrated) != string(data) {
+		t.Errorf("current config was rewritten: %s", migrated)
+	}
+}
+
+func TestMigrateConfig_RejectsCorruptJSON(t *testing.T) {
+	if _, _, err := migrateConfig([]byte(`{"hosts": [`)); err == nil {
+		t.Error("expected an error for truncated JSON")
+	}
+}
+
+func TestValidate_ResetsAndReports(t *testing.T) {
+	c := Config{
+		ApiTimeout:      DurationSeconds(2 * time.Second),
+		DownloadTimeout: DurationSeconds(60 * time.Minute),
+		ArtKind:         "Cartoon",
+		DownloadedGames: DownloadedGamesModeMark,
+		CollectionView:  CollectionViewPlatform,
+		LogLevel:        LogLevelInfo,
+		ReleaseChannel:  "nightly",
+
+		RefreshIntervalMinutes: -5,
+
+		DirectoryMappings: map[string]DirectoryMapping{
+			"gba":     {RomMSlug: "gba", RelativePath: "GBA"},
+			"atari99": {RomMSlug: "atari99", RelativePath: "../outside"},
+		},
+	}
+
+	problems := c.Validate(map[string][]string{"gba": {"GBA"}})
+
+	fields := map[string]bool{}
+	for _, problem := range problems {
+		fields[problem.Field] = true
+	}
+	for _, want := range []string{"api_timeout", "art_kind", "release_channel", "refresh_interval_minutes", "directory_mappings.atari99", "directory_mappings.atari99.relative_path"} {
+		if !fields[want] {
+			t.Errorf("expected a problem for %s, got %v", want, problems)
+		}
+	}
+	if len(problems) != 6 {
+		t.Errorf("got %d problems, want 6: %v", len(problems), problems)
+	}
+
+	if c.ApiTimeout.Duration() != 30*time.Second {
+		t.Errorf("api_timeout not reset: %v", c.ApiTimeout.Duration())
+	}
+	if c.ArtKind != artutil.ArtKindDefault {
+		t.Errorf("art_kind not reset: %q", c.ArtKind)
+	}
+	if c.ReleaseChannel != ReleaseChannelMatchRomM {
+		t.Errorf("release_channel not reset: %q", c.ReleaseChannel)
+	}
+	if c.GetRefreshInterval() != DefaultRefreshInterval {
+		t.Errorf("refresh_interval_minutes not reset: %d", c.RefreshIntervalMinutes)
+	}
+	if _, ok := c.DirectoryMappings["atari99"]; !ok {
+		t.Error("unknown mappings should be reported, not removed")
+	}
+}
+
+func TestValidate_ResetsArePersistedOnlyOnceAccepted(t *testing.T) {
+	c := Config{
+		ApiTimeout:      DurationSeconds(2 * time.Second),
+		DownloadTimeout: DurationSeconds(60 * time.Minute),
+		ArtKind:         "Cartoon",
+		LogLevel:        LogLevelInfo,
+		ReleaseChannel:  ReleaseChannelStable,
+		CollectionView:  CollectionViewPlatform,
+		DownloadedGames: DownloadedGamesModeMark,
+	}
+	c.Problems = c.Validate(nil)
+	if !c.HasResets() {
+		t.Fatal("expected api_timeout and art_kind to be reset")
+	}
+
+	c.ArtKind = artutil.ArtKindBox2D // changed in Settings after loading
+	persisted := c.persisted()
+	if persisted.ApiTimeout.Duration() != 2*time.Second {
+		t.Errorf("api_timeout written as %v before the reset was accepted", persisted.ApiTimeout.Duration())
+	}
+	if persisted.ArtKind != artutil.ArtKindBox2D {
+		t.Errorf("art_kind = %q, a value changed since loading should be kept", persisted.ArtKind)
+	}
+	if c.ApiTimeout.Duration() != 30*time.Second {
+		t.Errorf("in-memory api_timeout = %v, want the default", c.ApiTimeout.Duration())
+	}
+
+	c.AcceptResets()
+	if c.HasResets() {
+		t.Error("HasResets after AcceptResets")
+	}
+	if got := c.persisted().ApiTimeout.Duration(); got != 30*time.Second {
+		t.Errorf("api_timeout written as %v after accepting the reset", got)
+	}
+}
+
+func TestToLoggable_MasksTokenAndCoversEverySetting(t *testing.T) {
+	c := Config{
+		Hosts:           []romm.Host{{RootURI: "http://romm", Token: "secret", DeviceID: "dev-1"}},
+		SaveBackupLimit: 10,
+		SlotPreferences: map[string]string{"1": "quicksave"},
+	}
+
+	loggable := c.ToLoggable().(map[string]any)
+
+	data, err := json.Marshal(loggable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("token leaked: %s", data)
+	}
+	for _, key := range []string{"save_backup_limit", "api_timeout", "slot_preferences", "show_collections"} {
+		if _, ok := loggable[key]; !ok {
+			t.Errorf("%s missing from %s", key, data)
+		}
+	}
+	host := loggable["hosts"].([]map[string]any)[0]
+	if host["device_id"] != "dev-1" {
+		t.Errorf("host = %v", host)
+	}
+}
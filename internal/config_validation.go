@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"fmt"
+	"grout/internal/artutil"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The timeout ranges offered in Advanced Settings.
+const (
+	MinApiTimeout      = 15 * time.Second
+	MaxApiTimeout      = 300 * time.Second
+	MinDownloadTimeout = 15 * time.Minute
+	MaxDownloadTimeout = 120 * time.Minute
+)
+
+// DefaultRefreshInterval is how often the cache is refreshed in the background unless
+// refresh_interval_minutes says otherwise.
+const DefaultRefreshInterval = 30 * time.Minute
+
+// ConfigProblem is a value in config.json that Grout can't use.
+type ConfigProblem struct {
+	Field   string
+	Value   any
+	Problem string
+
+	// restore puts the user's value back over the default Validate swapped in, so
+	// SaveConfig doesn't overwrite config.json until the user accepts the reset.
+	restore func(*Config)
+}
+
+// IsReset reports whether Grout is using a default in place of the value.
+func (p ConfigProblem) IsReset() bool {
+	return p.restore != nil
+}
+
+func (p ConfigProblem) String() string {
+	return fmt.Sprintf("%s = %v: %s", p.Field, p.Value, p.Problem)
+}
+
+var validArtKinds = map[artutil.ArtKind]bool{
+	artutil.ArtKindDefault:    true,
+	artutil.ArtKindBox2D:      true,
+	artutil.ArtKindBox3D:      true,
+	artutil.ArtKindMixImage:   true,
+	artutil.ArtKindComposite:  true,
+	artutil.ArtKindMarquee:    true,
+	artutil.ArtKindLogo:       true,
+	artutil.ArtKindTitle:      true,
+	artutil.ArtKindScreenshot: true,
+	artutil.ArtKindVideo:      true,
+}
+
+// Validate reports the values Grout can't use. Values that would break a setting are
+// put back to their defaults so Grout keeps working; the rest are only reported. The
+// defaults are only written to config.json once AcceptResets is called.
+// knownPlatforms is the current CFW's platform map, used to spot mappings for
+// platforms the CFW doesn't know.
+func (c *Config) Validate(knownPlatforms map[string][]string) []ConfigProblem {
+	var problems []ConfigProblem
+	report := func(field string, value any, problem string) {
+		problems = append(problems, ConfigProblem{Field: field, Value: value, Problem: problem})
+	}
+	reset := func(field string, value any, problem string, restore func(*Config)) {
+		problems = append(problems, ConfigProblem{Field: field, Value: value, Problem: problem, restore: restore})
+	}
+
+	if api := c.ApiTimeout.Duration(); api < MinApiTimeout || api > MaxApiTimeout {
+		reset("api_timeout", int64(api.Seconds()), fmt.Sprintf("must be between %d and %d seconds, using 30", int(MinApiTimeout.Seconds()), int(MaxApiTimeout.Seconds())),
+			resetTo(c, func(c *Config) *DurationSeconds { return &c.ApiTimeout }, DurationSeconds(30*time.Second)))
+	}
+	if download := c.DownloadTimeout.Duration(); download < MinDownloadTimeout || download > MaxDownloadTimeout {
+		reset("download_timeout", int64(download.Seconds()), fmt.Sprintf("must be between %d and %d minutes, using 60", int(MinDownloadTimeout.Minutes()), int(MaxDownloadTimeout.Minutes())),
+			resetTo(c, func(c *Config) *DurationSeconds { return &c.DownloadTimeout }, DurationSeconds(60*time.Minute)))
+	}
+
+	if c.RefreshIntervalMinutes < -1 {
+		reset("refresh_interval_minutes", c.RefreshIntervalMinutes, "must be -1 (off) or a number of minutes, using the default",
+			resetTo(c, func(c *Config) *int { return &c.RefreshIntervalMinutes }, 0))
+	}
+
+	checkArtKind := func(field string, kind func(*Config) *artutil.ArtKind, fallback artutil.ArtKind, allowNone bool) {
+		if value := *kind(c); !validArtKinds[value] && !(allowNone && value == artutil.ArtKindNone) {
+			reset(field, value, fmt.Sprintf("unknown art kind, using %q", fallback), resetTo(c, kind, fallback))
+		}
+	}
+	checkArtKind("art_kind", func(c *Config) *artutil.ArtKind { return &c.ArtKind }, artutil.ArtKindDefault, false)
+	checkArtKind("download_splash_art", func(c *Config) *artutil.ArtKind { return &c.DownloadSplashArt }, artutil.ArtKindNone, true)
+	checkArtKind("additional_downloads.marquee", func(c *Config) *artutil.ArtKind { return &c.AdditionalDownloads.Marquee }, artutil.ArtKindNone, true)
+	checkArtKind("additional_downloads.thumbnail", func(c *Config) *artutil.ArtKind { return &c.AdditionalDownloads.Thumbnail }, artutil.ArtKindNone, true)
+
+	switch c.DownloadedGames {
+	case DownloadedGamesModeDoNothing, DownloadedGamesModeMark, DownloadedGamesModeFilter:
+	default:
+		reset("downloaded_games", c.DownloadedGames, fmt.Sprintf("unknown mode, using %q", DownloadedGamesModeDoNothing),
+			resetTo(c, func(c *Config) *DownloadedGamesMode { return &c.DownloadedGames }, DownloadedGamesModeDoNothing))
+	}
+	switch c.CollectionView {
+	case CollectionViewPlatform, CollectionViewUnified:
+	default:
+		reset("collection_view", c.CollectionView, fmt.Sprintf("unknown view, using %q", CollectionViewPlatform),
+			resetTo(c, func(c *Config) *CollectionView { return &c.CollectionView }, CollectionViewPlatform))
+	}
+	switch c.LogLevel {
+	case LogLevelDebug, LogLevelInfo, LogLevelError:
+	default:
+		reset("log_level", c.LogLevel, fmt.Sprintf("unknown level, using %q", LogLevelError),
+			resetTo(c, func(c *Config) *LogLevel { return &c.LogLevel }, LogLevelError))
+	}
+	switch c.ReleaseChannel {
+	case ReleaseChannelMatchRomM, ReleaseChannelStable, ReleaseChannelBeta:
+	default:
+		reset("release_channel", c.ReleaseChannel, fmt.Sprintf("unknown channel, using %q", ReleaseChannelMatchRomM),
+			resetTo(c, func(c *Config) *ReleaseChannel { return &c.ReleaseChannel }, ReleaseChannelMatchRomM))
+	}
+
+	for slug, mapping := range c.DirectoryMappings {
+		field := "directory_mappings." + slug
+		if knownPlatforms != nil {
+			if _, ok := knownPlatforms[slug]; !ok {
+				report(field, slug, "not a platform this CFW knows unless RomM binds it to one")
+			}
+		}
+		if mapping.RomMSlug != "" && mapping.RomMSlug != slug {
+			report(field+".slug", mapping.RomMSlug, "does not match the mapping's platform")
+		}
+		if filepath.IsAbs(mapping.RelativePath) || escapesRoot(mapping.RelativePath) {
+			report(field+".relative_path", mapping.RelativePath, "must stay inside the ROM directory")
+		}
+	}
+
+	return problems
+}
+
+// resetTo swaps the field for fallback and returns a function that puts the old value
+// back, unless the field has been changed again since.
+func resetTo[T comparable](c *Config, field func(*Config) *T, fallback T) func(*Config) {
+	original := *field(c)
+	*field(c) = fallback
+	return func(c *Config) {
+		if *field(c) == fallback {
+			*field(c) = original
+		}
+	}
+}
+
+// HasResets reports whether any values in config.json are being replaced by defaults.
+func (c *Config) HasResets() bool {
+	for _, problem := range c.Problems {
+		if problem.IsReset() {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptResets lets the next SaveConfig write the defaults Validate swapped in.
+func (c *Config) AcceptResets() {
+	for i := range c.Problems {
+		c.Problems[i].restore = nil
+	}
+}
+
+// persisted returns the config as it should be written, with the user's values in place
+// of defaults they haven't accepted.
+func (c *Config) persisted() Config {
+	persisted := *c
+	for _, problem := range c.Problems {
+		if problem.restore != nil {
+			problem.restore(&persisted)
+		}
+	}
+	return persisted
+}
+
+func escapesRoot(relativePath string) bool {
+	clean := filepath.Clean(relativePath)
+	return clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator))
+}
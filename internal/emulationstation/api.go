@@ -0,0 +1,68 @@
+package emulationstation
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultAPIURL is where Batocera-family EmulationStation serves its HTTP API.
+const DefaultAPIURL = "http://127.0.0.1:1234"
+
+const apiTimeout = 3 * time.Second
+
+// Client talks to the HTTP API of a running EmulationStation, which can pick up new
+// games without the restart ScheduleESRestart asks for.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: apiTimeout},
+	}
+}
+
+// Available reports whether EmulationStation is running and answering API requests.
+func (c *Client) Available() bool {
+	resp, err := c.httpClient.Get(c.baseURL + "/caps")
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode == http.StatusOK
+}
+
+// AddGames adds or updates the games in a gamelist.xml document for one system
+// (the name of its ROM folder, e.g. "gba") without reloading anything else.
+func (c *Client) AddGames(system string, gamelistXML []byte) error {
+	resp, err := c.httpClient.Post(c.baseURL+"/addgames/"+url.PathEscape(system), "application/xml", bytes.NewReader(gamelistXML))
+	if err != nil {
+		return fmt.Errorf("unable to add games to %s: %w", system, err)
+	}
+	return checkResponse(resp, "add games to "+system)
+}
+
+// ReloadGames makes EmulationStation re-read every gamelist.
+func (c *Client) ReloadGames() error {
+	resp, err := c.httpClient.Get(c.baseURL + "/reloadgames")
+	if err != nil {
+		return fmt.Errorf("unable to reload games: %w", err)
+	}
+	return checkResponse(resp, "reload games")
+}
+
+func checkResponse(resp *http.Response, action string) error {
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to %s: %s", action, resp.Status)
+	}
+	return nil
+}
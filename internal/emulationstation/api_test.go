@@ -0,0 +1,53 @@
+package emulationstation
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	var added, reloaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/caps":
+			w.Write([]byte(`{"Version":"39"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/addgames/gba":
+			body, _ := io.ReadAll(r.Body)
+			added = string(body)
+		case r.URL.Path == "/reloadgames":
+			reloaded = "yes"
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if !client.Available() {
+		t.Fatal("Available() = false for a running server")
+	}
+	if err := client.AddGames("gba", []byte("<gameList/>")); err != nil {
+		t.Fatalf("AddGames: %v", err)
+	}
+	if added != "<gameList/>" {
+		t.Errorf("server received %q", added)
+	}
+	if err := client.ReloadGames(); err != nil || reloaded != "yes" {
+		t.Errorf("ReloadGames: err=%v, reloaded=%q", err, reloaded)
+	}
+	if err := client.AddGames("snes", nil); err == nil {
+		t.Error("AddGames succeeded on a 404")
+	}
+}
+
+func TestClientUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	if NewClient(url).Available() {
+		t.Error("Available() = true with nothing listening")
+	}
+}
@@ -0,0 +1,403 @@
+package fileutil
+
+import (
+	"bufio"
+	"compress/flate"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+const (
+	zipLocalHeaderSignature   = 0x04034b50
+	zipCentralHeaderSignature = 0x02014b50
+	zipEndSignature           = 0x06054b50
+	zipDataDescriptorSig      = 0x08074b50
+	zipFlagDataDescriptor     = 0x8
+	zip64ExtraID              = 0x0001
+)
+
+// ErrStreamUnsupported is returned when an archive can only be extracted with random
+// access, e.g. stored entries whose size is only known from a trailing data descriptor.
+var ErrStreamUnsupported = errors.New("archive cannot be extracted while streaming")
+
+// ErrHashMismatch is returned when an extracted entry does not match its expected checksum.
+var ErrHashMismatch = errors.New("extracted file does not match expected hash")
+
+// ExpectedHashes are the checksums an extracted file must match. Empty values are not checked.
+type ExpectedHashes struct {
+	CRC32 string
+	MD5   string
+	SHA1  string
+}
+
+// ExpectedKey is the key of an archive entry in a map of ExpectedHashes: its path inside
+// the archive, lower-cased, so files with the same name in different folders stay apart.
+func ExpectedKey(name string) string {
+	name = strings.TrimPrefix(strings.ReplaceAll(name, "\\", "/"), "./")
+	return strings.ToLower(path.Clean(name))
+}
+
+// mismatchedHash returns which of the hashes doesn't match want, or "" when they all do.
+// md5Hash and sha1Hash may be nil when want has no value for them.
+func mismatchedHash(want ExpectedHashes, crc hash.Hash32, md5Hash, sha1Hash hash.Hash) string {
+	switch {
+	case want.CRC32 != "" && !strings.EqualFold(want.CRC32, fmt.Sprintf("%08X", crc.Sum32())):
+		return "crc32"
+	case md5Hash != nil && !strings.EqualFold(want.MD5, fmt.Sprintf("%x", md5Hash.Sum(nil))):
+		return "md5"
+	case sha1Hash != nil && !strings.EqualFold(want.SHA1, fmt.Sprintf("%x", sha1Hash.Sum(nil))):
+		return "sha1"
+	}
+	return ""
+}
+
+// VerifyFiles checks files already extracted into destDir against expected, the way
+// StreamUnzip checks them while extracting. files are relative to destDir.
+func VerifyFiles(destDir string, files []string, expected map[string]ExpectedHashes) error {
+	buffer := make([]byte, DefaultBufferSize)
+	for _, name := range files {
+		want, ok := expected[ExpectedKey(name)]
+		if !ok {
+			continue
+		}
+		if err := verifyFile(filepath.Join(destDir, filepath.FromSlash(name)), want, buffer); err != nil {
+			return fmt.Errorf("failed to verify file %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func verifyFile(filePath string, want ExpectedHashes, buffer []byte) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	crc := crc32.NewIEEE()
+	writers := []io.Writer{crc}
+	var md5Hash, sha1Hash hash.Hash
+	if want.MD5 != "" {
+		md5Hash = md5.New()
+		writers = append(writers, md5Hash)
+	}
+	if want.SHA1 != "" {
+		sha1Hash = sha1.New()
+		writers = append(writers, sha1Hash)
+	}
+
+	if _, err := io.CopyBuffer(io.MultiWriter(writers...), f, buffer); err != nil {
+		return err
+	}
+	if mismatch := mismatchedHash(want, crc, md5Hash, sha1Hash); mismatch != "" {
+		return fmt.Errorf("%w (%s)", ErrHashMismatch, mismatch)
+	}
+	return nil
+}
+
+// StreamUnzip extracts a zip archive read sequentially from r into destDir as the bytes
+// arrive, so the archive itself never touches the disk. totalBytes is the archive size
+// used to report progress. Entries whose ExpectedKey appears in expected are verified
+// against those hashes. It returns the extracted file names relative to destDir.
+func StreamUnzip(r io.Reader, destDir string, totalBytes int64, progress *atomic.Float64, expected map[string]ExpectedHashes) ([]string, error) {
+	counter := &countingReader{reader: r, total: totalBytes, progress: progress}
+	br := bufio.NewReaderSize(counter, DefaultBufferSize)
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	buffer := make([]byte, DefaultBufferSize)
+	var extracted []string
+
+	for {
+		var sig uint32
+		if err := binary.Read(br, binary.LittleEndian, &sig); err != nil {
+			if errors.Is(err, io.EOF) && len(extracted) > 0 {
+				break
+			}
+			return extracted, fmt.Errorf("failed to read zip header: %w", err)
+		}
+
+		if sig == zipCentralHeaderSignature || sig == zipEndSignature {
+			// The central directory repeats what we already extracted.
+			_, _ = io.Copy(io.Discard, br)
+			break
+		}
+		if sig != zipLocalHeaderSignature {
+			return extracted, fmt.Errorf("unexpected zip signature %#x", sig)
+		}
+
+		name, err := streamZipEntry(br, destDir, buffer, expected)
+		if err != nil {
+			return extracted, err
+		}
+		if name != "" {
+			extracted = append(extracted, name)
+		}
+	}
+
+	if progress != nil {
+		progress.Store(1)
+	}
+	return extracted, nil
+}
+
+type zipLocalHeader struct {
+	Version          uint16
+	Flags            uint16
+	Method           uint16
+	ModTime          uint16
+	ModDate          uint16
+	CRC32            uint32
+	CompressedSize   uint32
+	UncompressedSize uint32
+	NameLength       uint16
+	ExtraLength      uint16
+}
+
+func streamZipEntry(br *bufio.Reader, destDir string, buffer []byte, expected map[string]ExpectedHashes) (string, error) {
+	var hdr zipLocalHeader
+	if err := binary.Read(br, binary.LittleEndian, &hdr); err != nil {
+		return "", fmt.Errorf("failed to read zip entry header: %w", err)
+	}
+
+	nameAndExtra := make([]byte, int(hdr.NameLength)+int(hdr.ExtraLength))
+	if _, err := io.ReadFull(br, nameAndExtra); err != nil {
+		return "", fmt.Errorf("failed to read zip entry name: %w", err)
+	}
+	name := strings.ReplaceAll(string(nameAndExtra[:hdr.NameLength]), "\\", "/")
+	extra := nameAndExtra[hdr.NameLength:]
+
+	compressedSize := uint64(hdr.CompressedSize)
+	zip64 := false
+	if hdr.CompressedSize == 0xFFFFFFFF || hdr.UncompressedSize == 0xFFFFFFFF {
+		zip64 = true
+		compressedSize = zip64CompressedSize(extra, hdr)
+	}
+
+	hasDescriptor := hdr.Flags&zipFlagDataDescriptor != 0
+
+	var body io.Reader
+	switch hdr.Method {
+	case 0: // stored
+		if hasDescriptor && compressedSize == 0 {
+			return "", ErrStreamUnsupported
+		}
+		body = io.LimitReader(br, int64(compressedSize))
+	case 8: // deflate: self-terminating, and bufio.Reader keeps flate from over-reading
+		fr := flate.NewReader(br)
+		defer fr.Close()
+		body = fr
+	default:
+		return "", fmt.Errorf("%w: compression method %d", ErrStreamUnsupported, hdr.Method)
+	}
+
+	destPath, err := safeJoin(destDir, name)
+	if err != nil {
+		return "", err
+	}
+
+	isDir := strings.HasSuffix(name, "/")
+	crc := crc32.NewIEEE()
+	if isDir {
+		if err := os.MkdirAll(destPath, 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory %s: %w", destPath, err)
+		}
+		if _, err := io.Copy(io.Discard, body); err != nil {
+			return "", err
+		}
+	} else {
+		want, hasExpected := expected[ExpectedKey(name)]
+		if err := writeStreamedFile(destPath, body, buffer, crc, want, hasExpected); err != nil {
+			return "", fmt.Errorf("failed to extract file %s: %w", name, err)
+		}
+	}
+
+	wantCRC := hdr.CRC32
+	if hasDescriptor {
+		if wantCRC, err = readDataDescriptor(br, zip64); err != nil {
+			return "", err
+		}
+	}
+	if !isDir && crc.Sum32() != wantCRC {
+		os.Remove(destPath)
+		return "", fmt.Errorf("%w: %s failed zip CRC check", ErrHashMismatch, name)
+	}
+
+	if isDir {
+		return "", nil
+	}
+	return name, nil
+}
+
+func writeStreamedFile(destPath string, body io.Reader, buffer []byte, crc hash.Hash32, want ExpectedHashes, hasExpected bool) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return err
+	}
+
+	destFile, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	bufWriter := bufio.NewWriterSize(destFile, SmallBufferSize)
+	writers := []io.Writer{bufWriter, crc}
+
+	var md5Hash, sha1Hash hash.Hash
+	if hasExpected && want.MD5 != "" {
+		md5Hash = md5.New()
+		writers = append(writers, md5Hash)
+	}
+	if hasExpected && want.SHA1 != "" {
+		sha1Hash = sha1.New()
+		writers = append(writers, sha1Hash)
+	}
+
+	if _, err := io.CopyBuffer(io.MultiWriter(writers...), body, buffer); err != nil {
+		return err
+	}
+	if err := bufWriter.Flush(); err != nil {
+		return err
+	}
+
+	if hasExpected {
+		if mismatch := mismatchedHash(want, crc, md5Hash, sha1Hash); mismatch != "" {
+			destFile.Close()
+			os.Remove(destPath)
+			return fmt.Errorf("%w (%s)", ErrHashMismatch, mismatch)
+		}
+	}
+
+	return nil
+}
+
+func zip64CompressedSize(extra []byte, hdr zipLocalHeader) uint64 {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra[0:2])
+		size := int(binary.LittleEndian.Uint16(extra[2:4]))
+		extra = extra[4:]
+		if size > len(extra) {
+			break
+		}
+		if id == zip64ExtraID {
+			field := extra[:size]
+			// Fields only appear for header values that overflowed, in this order.
+			if hdr.UncompressedSize == 0xFFFFFFFF && len(field) >= 8 {
+				field = field[8:]
+			}
+			if hdr.CompressedSize == 0xFFFFFFFF && len(field) >= 8 {
+				return binary.LittleEndian.Uint64(field[:8])
+			}
+			break
+		}
+		extra = extra[size:]
+	}
+	return uint64(hdr.CompressedSize)
+}
+
+func readDataDescriptor(br *bufio.Reader, zip64 bool) (uint32, error) {
+	var first uint32
+	if err := binary.Read(br, binary.LittleEndian, &first); err != nil {
+		return 0, fmt.Errorf("failed to read data descriptor: %w", err)
+	}
+	crc := first
+	if first == zipDataDescriptorSig {
+		if err := binary.Read(br, binary.LittleEndian, &crc); err != nil {
+			return 0, fmt.Errorf("failed to read data descriptor: %w", err)
+		}
+	}
+	sizeBytes := 8
+	if zip64 {
+		sizeBytes = 16
+	}
+	if _, err := br.Discard(sizeBytes); err != nil {
+		return 0, fmt.Errorf("failed to read data descriptor: %w", err)
+	}
+	return crc, nil
+}
+
+// safeJoin joins an archive entry name onto destDir, rejecting names that escape it.
+func safeJoin(destDir, name string) (string, error) {
+	p := filepath.Join(destDir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(destDir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("zip entry %q escapes destination directory", name)
+	}
+	return p, nil
+}
+
+// ProgressReader reports how much of totalBytes has been read from r to progress.
+func ProgressReader(r io.Reader, totalBytes int64, progress *atomic.Float64) io.Reader {
+	return &countingReader{reader: r, total: totalBytes, progress: progress}
+}
+
+type countingReader struct {
+	reader   io.Reader
+	read     int64
+	total    int64
+	progress *atomic.Float64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	if n > 0 {
+		c.read += int64(n)
+		if c.progress != nil && c.total > 0 {
+			c.progress.Store(min(float64(c.read)/float64(c.total), 1))
+		}
+	}
+	return n, err
+}
+
+// ErrStalled is returned when a stream delivers no data for longer than its idle timeout.
+var ErrStalled = errors.New("transfer stalled")
+
+// WithIdleTimeout wraps body so a transfer that stops delivering data fails with
+// ErrStalled after timeout, while a slow but steady one of any size runs to completion.
+// abort must unblock a pending read on body, typically by cancelling its request.
+func WithIdleTimeout(body io.ReadCloser, timeout time.Duration, abort func()) io.ReadCloser {
+	r := &idleTimeoutReader{body: body, timeout: timeout}
+	r.timer = time.AfterFunc(timeout, func() {
+		r.stalled.Store(true)
+		abort()
+	})
+	return r
+}
+
+type idleTimeoutReader struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	stalled atomic.Bool
+}
+
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if n > 0 && !r.stalled.Load() {
+		r.timer.Reset(r.timeout)
+	}
+	if err != nil && err != io.EOF && r.stalled.Load() {
+		return n, ErrStalled
+	}
+	return n, err
+}
+
+func (r *idleTimeoutReader) Close() error {
+	r.timer.Stop()
+	return r.body.Close()
+}
@@ -0,0 +1,211 @@
+package fileutil
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func buildZip(t *testing.T, method uint16, entries map[string]string, order []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range order {
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(entries[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// buildStoredZip writes stored entries with their sizes in the local header, the way
+// RomM's on-the-fly zips are laid out.
+func buildStoredZip(t *testing.T, entries map[string]string, order []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range order {
+		data := []byte(entries[name])
+		fw, err := w.CreateRaw(&zip.FileHeader{
+			Name:               name,
+			Method:             zip.Store,
+			CRC32:              crc32.ChecksumIEEE(data),
+			CompressedSize64:   uint64(len(data)),
+			UncompressedSize64: uint64(len(data)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestStreamUnzip(t *testing.T) {
+	entries := map[string]string{
+		"Game (Disc 1).cue":     "FILE disc1.bin",
+		"Game (Disc 2).cue":     "FILE disc2.bin",
+		"tracks/disc1.bin":      string(bytes.Repeat([]byte("a"), 300_000)),
+		"tracks/":               "",
+		"tracks/nested/x.bin":   "x",
+		"Game (Disc 2) (a).bin": "",
+	}
+	order := []string{"tracks/", "Game (Disc 1).cue", "Game (Disc 2).cue", "tracks/disc1.bin", "tracks/nested/x.bin", "Game (Disc 2) (a).bin"}
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"deflate", buildZip(t, zip.Deflate, entries, order)},
+		{"stored", buildStoredZip(t, entries, order)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dest := t.TempDir()
+			sum := sha1.Sum([]byte(entries["Game (Disc 1).cue"]))
+			expected := map[string]ExpectedHashes{
+				"game (disc 1).cue": {
+					CRC32: fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(entries["Game (Disc 1).cue"]))),
+					SHA1:  fmt.Sprintf("%x", sum),
+				},
+			}
+
+			got, err := StreamUnzip(bytes.NewReader(tc.data), dest, int64(len(tc.data)), nil, expected)
+			if err != nil {
+				t.Fatalf("StreamUnzip: %v", err)
+			}
+
+			want := []string{"Game (Disc 1).cue", "Game (Disc 2).cue", "tracks/disc1.bin", "tracks/nested/x.bin", "Game (Disc 2) (a).bin"}
+			if !slices.Equal(got, want) {
+				t.Errorf("extracted = %v, want %v", got, want)
+			}
+			for _, name := range want {
+				data, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(name)))
+				if err != nil {
+					t.Fatalf("read %s: %v", name, err)
+				}
+				if string(data) != entries[name] {
+					t.Errorf("%s content mismatch", name)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamUnzip_HashMismatch(t *testing.T) {
+	entries := map[string]string{"game.bin": "actual"}
+	data := buildZip(t, zip.Deflate, entries, []string{"game.bin"})
+	dest := t.TempDir()
+
+	_, err := StreamUnzip(bytes.NewReader(data), dest, 0, nil, map[string]ExpectedHashes{
+		"game.bin": {MD5: "00000000000000000000000000000000"},
+	})
+	if !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("StreamUnzip error = %v, want ErrHashMismatch", err)
+	}
+	if FileExists(filepath.Join(dest, "game.bin")) {
+		t.Error("mismatched file was left on disk")
+	}
+}
+
+func TestStreamUnzip_SameNameInSubfolders(t *testing.T) {
+	entries := map[string]string{"disc1/track01.bin": "one", "disc2/track01.bin": "two"}
+	order := []string{"disc1/track01.bin", "disc2/track01.bin"}
+	data := buildZip(t, zip.Deflate, entries, order)
+	expected := map[string]ExpectedHashes{
+		ExpectedKey("disc1/track01.bin"): {CRC32: fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte("one")))},
+		ExpectedKey("disc2/track01.bin"): {CRC32: fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte("two")))},
+	}
+
+	dest := t.TempDir()
+	if _, err := StreamUnzip(bytes.NewReader(data), dest, 0, nil, expected); err != nil {
+		t.Fatalf("StreamUnzip: %v", err)
+	}
+	if err := VerifyFiles(dest, order, expected); err != nil {
+		t.Errorf("VerifyFiles: %v", err)
+	}
+}
+
+func TestVerifyFiles_Mismatch(t *testing.T) {
+	dest := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dest, "disc1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dest, "disc1", "track01.bin"), []byte("actual"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := VerifyFiles(dest, []string{"disc1/track01.bin"}, map[string]ExpectedHashes{
+		"disc1/track01.bin": {MD5: "00000000000000000000000000000000"},
+	})
+	if !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("VerifyFiles error = %v, want ErrHashMismatch", err)
+	}
+}
+
+func TestStreamUnzip_RejectsPathTraversal(t *testing.T) {
+	entries := map[string]string{"../evil.txt": "x"}
+	data := buildStoredZip(t, entries, []string{"../evil.txt"})
+	dest := t.TempDir()
+
+	if _, err := StreamUnzip(bytes.NewReader(data), dest, 0, nil, nil); err == nil {
+		t.Fatal("expected an error for an entry escaping the destination")
+	}
+	if FileExists(filepath.Join(filepath.Dir(dest), "evil.txt")) {
+		t.Error("entry was written outside the destination")
+	}
+}
+
+func TestStreamUnzip_StoredWithDescriptorUnsupported(t *testing.T) {
+	entries := map[string]string{"game.bin": "data"}
+	data := buildZip(t, zip.Store, entries, []string{"game.bin"})
+
+	if _, err := StreamUnzip(bytes.NewReader(data), t.TempDir(), 0, nil, nil); !errors.Is(err, ErrStreamUnsupported) {
+		t.Fatalf("StreamUnzip error = %v, want ErrStreamUnsupported", err)
+	}
+}
+
+func TestWithIdleTimeout(t *testing.T) {
+	pr, pw := io.Pipe()
+	body := WithIdleTimeout(pr, 50*time.Millisecond, func() { pr.CloseWithError(errors.New("aborted")) })
+	defer body.Close()
+
+	// Data that keeps arriving within the timeout is read in full, however long it takes.
+	go func() {
+		for range 5 {
+			time.Sleep(20 * time.Millisecond)
+			pw.Write([]byte("x"))
+		}
+	}()
+	buf := make([]byte, 1)
+	for i := range 5 {
+		if _, err := io.ReadFull(body, buf); err != nil {
+			t.Fatalf("read %d: %v", i, err)
+		}
+	}
+
+	// Then the sender goes quiet.
+	if _, err := body.Read(buf); !errors.Is(err, ErrStalled) {
+		t.Errorf("stalled read error = %v, want ErrStalled", err)
+	}
+}
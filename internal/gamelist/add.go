@@ -6,6 +6,7 @@ import (
 	"grout/internal/stringutil"
 	"grout/romm"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -34,7 +35,10 @@ type RomGameEntry struct {
 	ArtLocation  artLocation
 	GamePath     string
 	RomDirectory string
-	Platform     *romm.Platform
+	// GamelistDirectory is where the gamelist for RomDirectory lives, when the
+	// frontend keeps it outside the ROM folder.
+	GamelistDirectory string
+	Platform          *romm.Platform
 }
 
 func (gl *GameList) AddRomGame(entry RomGameEntry) {
@@ -127,6 +131,14 @@ func (gl *GameList) AddRomGame(entry RomGameEntry) {
 		gameMetadata[CheevosHashElement] = entry.Game.RetroAchievementsHash
 	}
 
+	if entry.RomDirectory != "" {
+		for _, tag := range append([]string{PathElement}, artElements...) {
+			if value, ok := gameMetadata[tag]; ok && filepath.IsAbs(value) {
+				gameMetadata[tag] = relativeGamelistPath(entry.RomDirectory, value)
+			}
+		}
+	}
+
 	gl.AdddOrUpdateEntry(entry.Game.Name, gameMetadata)
 }
 
@@ -136,7 +148,11 @@ func AddRomGamesToGamelist(entry []RomGameEntry, gamelistFilename FileName) erro
 		glEntry, exists := gamelists[game.Platform.FSSlug]
 		if !exists {
 			gl := New()
-			gamelistPath := fmt.Sprintf("%s/%s", game.RomDirectory, gamelistFilename)
+			gamelistDir := game.GamelistDirectory
+			if gamelistDir == "" {
+				gamelistDir = game.RomDirectory
+			}
+			gamelistPath := filepath.Join(gamelistDir, string(gamelistFilename))
 			if fileutil.FileExists(gamelistPath) {
 				data, err := os.ReadFile(gamelistPath)
 				if err != nil {
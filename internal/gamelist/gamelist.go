@@ -30,6 +30,11 @@ const (
 	CheevosHashElement = "cheevosHash"
 	CheevosIDElement   = "cheevosId"
 	ScraperIDElement   = "scraperId"
+	FavoriteElement    = "favorite"
+	PlayCountElement   = "playcount"
+	LastPlayedElement  = "lastplayed"
+	HiddenElement      = "hidden"
+	KidGameElement     = "kidgame"
 )
 
 type FileName string
@@ -111,6 +116,12 @@ func (gl *GameList) Save(filepath string) error {
 	return nil
 }
 
+// Bytes returns the gamelist as an XML document.
+func (gl *GameList) Bytes() ([]byte, error) {
+	gl.document.Indent(4)
+	return gl.document.WriteToBytes()
+}
+
 func (gl *GameList) AddGameEntry(info map[string]string) {
 	root := gl.document.SelectElement(GameListElement)
 	newGame := root.CreateElement(GameElement)
@@ -129,6 +140,9 @@ func (gl *GameList) AdddOrUpdateEntry(name string, info map[string]string) {
 
 	for key, value := range info {
 		if element := game.FindElement(key); element != nil {
+			if ElementOwner(key) == OwnerUser {
+				continue
+			}
 			element.SetText(value)
 		} else {
 			game.CreateElement(key).SetText(value)
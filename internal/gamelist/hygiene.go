@@ -0,0 +1,175 @@
+package gamelist
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// artElements are the elements that point at a file next to the ROMs.
+var artElements = []string{
+	ImageElement,
+	ThumbnailElement,
+	MarqueeElement,
+	VideoElement,
+	BezelElement,
+	ManualElement,
+	BoxbackElement,
+	FanartElement,
+}
+
+// CleanResult counts what Clean changed in a gamelist.
+type CleanResult struct {
+	Removed    int // games whose ROM no longer exists
+	Merged     int // duplicate games folded into the first entry for the same ROM
+	Rewritten  int // paths rewritten to the ./ form
+	MissingArt int // art references dropped because the file is gone
+}
+
+func (r CleanResult) Changed() bool {
+	return r.Removed > 0 || r.Merged > 0 || r.Rewritten > 0 || r.MissingArt > 0
+}
+
+// Clean checks every game against the files in romDir, the folder the gamelist lives in.
+// Games whose ROM is gone are removed, art that is gone is dropped, duplicate games are
+// merged and paths are rewritten relative to romDir ("./game.zip"). Absolute paths from
+// another mount point are recovered when the same file exists under romDir.
+func (gl *GameList) Clean(romDir string) CleanResult {
+	var result CleanResult
+	root := gl.document.SelectElement(GameListElement)
+	if root == nil {
+		return result
+	}
+
+	seen := make(map[string]*etree.Element)
+	for _, game := range root.SelectElements(GameElement) {
+		pathElement := game.SelectElement(PathElement)
+		gamePath, ok := resolveGamelistPath(romDir, elementText(pathElement))
+		if !ok {
+			root.RemoveChild(game)
+			result.Removed++
+			continue
+		}
+		if gamePath != pathElement.Text() {
+			pathElement.SetText(gamePath)
+			result.Rewritten++
+		}
+
+		for _, tag := range artElements {
+			for _, element := range game.SelectElements(tag) {
+				artPath, ok := resolveGamelistPath(romDir, elementText(element))
+				if !ok {
+					game.RemoveChild(element)
+					result.MissingArt++
+					continue
+				}
+				if artPath != element.Text() {
+					element.SetText(artPath)
+					result.Rewritten++
+				}
+			}
+		}
+
+		if first, duplicate := seen[gamePath]; duplicate {
+			mergeGame(first, game)
+			root.RemoveChild(game)
+			result.Merged++
+			continue
+		}
+		seen[gamePath] = game
+	}
+
+	return result
+}
+
+// CleanFile cleans the gamelist at path against the ROM folder romDir, which is usually
+// the folder the gamelist is in. When anything changes, the original is kept next to it
+// as <name>.bak before the file is rewritten.
+func CleanFile(path, romDir string) (CleanResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CleanResult{}, err
+	}
+
+	gl := New()
+	if err := gl.Parse(data); err != nil {
+		return CleanResult{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	result := gl.Clean(romDir)
+	if !result.Changed() {
+		return result, nil
+	}
+
+	if err := os.WriteFile(path+".bak", data, 0644); err != nil {
+		return result, fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	if err := gl.Save(path); err != nil {
+		return result, fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// mergeGame copies the elements of duplicate that game doesn't have yet into game.
+func mergeGame(game, duplicate *etree.Element) {
+	for _, child := range duplicate.ChildElements() {
+		if game.SelectElement(child.Tag) == nil {
+			game.AddChild(child.Copy())
+		}
+	}
+	for _, attr := range duplicate.Attr {
+		if game.SelectAttr(attr.Key) == nil {
+			game.CreateAttr(attr.Key, attr.Value)
+		}
+	}
+}
+
+// resolveGamelistPath returns the path the gamelist should use for p and whether the
+// file exists. Files under romDir get the ./ form; absolute paths that don't exist are
+// looked up under romDir by their trailing path components.
+func resolveGamelistPath(romDir, p string) (string, bool) {
+	if p == "" {
+		return "", false
+	}
+
+	if !filepath.IsAbs(p) {
+		if !fileExists(filepath.Join(romDir, p)) {
+			return p, false
+		}
+		return relativeGamelistPath(romDir, filepath.Join(romDir, p)), true
+	}
+
+	if fileExists(p) {
+		return relativeGamelistPath(romDir, p), true
+	}
+
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(p)), "/")
+	for i := 1; i < len(parts); i++ {
+		candidate := filepath.Join(romDir, filepath.Join(parts[i:]...))
+		if fileExists(candidate) {
+			return relativeGamelistPath(romDir, candidate), true
+		}
+	}
+	return p, false
+}
+
+// relativeGamelistPath returns path relative to romDir in the ./ form, or path unchanged
+// when it is outside romDir.
+func relativeGamelistPath(romDir, path string) string {
+	if romDir == "" {
+		return path
+	}
+	rel, err := filepath.Rel(romDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return "./" + filepath.ToSlash(rel)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,91 @@
+package gamelist
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCleanFile(t *testing.T) {
+	romDir := t.TempDir()
+	for _, f := range []string{"Zelda.sfc", "Mario.sfc", "images/Zelda.png"} {
+		path := filepath.Join(romDir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	original := `<?xml version="1.0"?>
+<gameList>
+	<game><path>/mnt/SDCARD/Roms/SFC/Zelda.sfc</path><name>Zelda</name><image>/mnt/SDCARD/Roms/SFC/images/Zelda.png</image></game>
+	<game><path>./Zelda.sfc</path><name>Zelda</name><playcount>4</playcount></game>
+	<game><path>./Mario.sfc</path><name>Mario</name><video>./videos/Mario.mp4</video></game>
+	<game><path>./Deleted.sfc</path><name>Deleted</name></game>
+</gameList>`
+	gamelistPath := filepath.Join(romDir, string(GameListFileName))
+	if err := os.WriteFile(gamelistPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := CleanFile(gamelistPath, romDir)
+	if err != nil {
+		t.Fatalf("CleanFile: %v", err)
+	}
+	want := CleanResult{Removed: 1, Merged: 1, Rewritten: 2, MissingArt: 1}
+	if result != want {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+
+	backup, err := os.ReadFile(gamelistPath + ".bak")
+	if err != nil || string(backup) != original {
+		t.Errorf("backup missing or changed: %v", err)
+	}
+
+	data, err := os.ReadFile(gamelistPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleaned := string(data)
+	for _, want := range []string{"<path>./Zelda.sfc</path>", "<image>./images/Zelda.png</image>", "<playcount>4</playcount>"} {
+		if !strings.Contains(cleaned, want) {
+			t.Errorf("cleaned gamelist is missing %s:\n%s", want, cleaned)
+		}
+	}
+	for _, gone := range []string{"Deleted", "Mario.mp4", "/mnt/SDCARD"} {
+		if strings.Contains(cleaned, gone) {
+			t.Errorf("cleaned gamelist still contains %s", gone)
+		}
+	}
+	if n := strings.Count(cleaned, "<game>"); n != 2 {
+		t.Errorf("cleaned gamelist has %d games, want 2", n)
+	}
+
+	if result, err := CleanFile(gamelistPath, romDir); err != nil || result.Changed() {
+		t.Errorf("second clean = %+v, %v; want no changes", result, err)
+	}
+}
+
+func TestCleanFileOutsideRomDir(t *testing.T) {
+	romDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(romDir, "Zelda.sfc"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// ES-DE keeps gamelists apart from the ROMs, with paths still relative to the ROM folder.
+	gamelistPath := filepath.Join(t.TempDir(), string(GameListFileName))
+	original := `<?xml version="1.0"?>
+<gameList>
+	<game><path>./Zelda.sfc</path><name>Zelda</name></game>
+</gameList>`
+	if err := os.WriteFile(gamelistPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if result, err := CleanFile(gamelistPath, romDir); err != nil || result.Changed() {
+		t.Errorf("CleanFile = %+v, %v; want no changes", result, err)
+	}
+}
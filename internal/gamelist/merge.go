@@ -0,0 +1,31 @@
+package gamelist
+
+// Owner says who decides the value of a gamelist element once a game is in the gamelist.
+type Owner int
+
+const (
+	// OwnerGrout elements mirror RomM and are rewritten whenever Grout updates the game.
+	OwnerGrout Owner = iota
+	// OwnerUser elements are edited in EmulationStation. Grout fills them in when they
+	// are missing and never overwrites them.
+	OwnerUser
+)
+
+// userOwnedElements are the elements EmulationStation lets users change or tracks on
+// its own. Rating starts out as RomM's rating but becomes the user's once it exists.
+var userOwnedElements = map[string]bool{
+	FavoriteElement:   true,
+	PlayCountElement:  true,
+	LastPlayedElement: true,
+	HiddenElement:     true,
+	KidGameElement:    true,
+	RatingElement:     true,
+}
+
+// ElementOwner returns who owns a gamelist element when Grout updates an existing game.
+func ElementOwner(element string) Owner {
+	if userOwnedElements[element] {
+		return OwnerUser
+	}
+	return OwnerGrout
+}
@@ -0,0 +1,72 @@
+package gamelist
+
+import (
+	"testing"
+	"time"
+)
+
+const editedGamelist = `<?xml version="1.0"?>
+<gameList>
+	<game>
+		<path>./Zelda.sfc</path>
+		<name>Zelda</name>
+		<desc>Old description</desc>
+		<rating>0.9</rating>
+		<favorite>true</favorite>
+		<playcount>12</playcount>
+		<lastplayed>20260115T203000</lastplayed>
+	</game>
+	<game>
+		<path>./Mario.sfc</path>
+		<name>Mario</name>
+	</game>
+</gameList>`
+
+func TestUpdateKeepsUserOwnedElements(t *testing.T) {
+	gl := New()
+	if err := gl.Parse([]byte(editedGamelist)); err != nil {
+		t.Fatal(err)
+	}
+
+	gl.AdddOrUpdateEntry("Zelda", map[string]string{
+		DescElement:     "New description",
+		RatingElement:   "0.5",
+		FavoriteElement: "false",
+	})
+	gl.AdddOrUpdateEntry("Mario", map[string]string{RatingElement: "0.7"})
+
+	zelda := gl.GetGameElementByName("Zelda")
+	if got := zelda.FindElement(DescElement).Text(); got != "New description" {
+		t.Errorf("desc = %q, want Grout's update", got)
+	}
+	if got := zelda.FindElement(RatingElement).Text(); got != "0.9" {
+		t.Errorf("rating = %q, want the user's 0.9", got)
+	}
+	if got := zelda.FindElement(FavoriteElement).Text(); got != "true" {
+		t.Errorf("favorite = %q, want the user's true", got)
+	}
+	if got := gl.GetGameElementByName("Mario").FindElement(RatingElement).Text(); got != "0.7" {
+		t.Errorf("missing rating = %q, want it filled in", got)
+	}
+}
+
+func TestPlayStats(t *testing.T) {
+	gl := New()
+	if err := gl.Parse([]byte(editedGamelist)); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := gl.PlayStats()
+	if len(stats) != 1 {
+		t.Fatalf("got %d play stats, want 1 (unplayed games skipped)", len(stats))
+	}
+	want := PlayStats{
+		Path:       "./Zelda.sfc",
+		PlayCount:  12,
+		LastPlayed: time.Date(2026, 1, 15, 20, 30, 0, 0, time.UTC),
+		Favorite:   true,
+	}
+	if got := stats[0]; got != want {
+		t.Errorf("PlayStats = %+v, want %+v", got, want)
+	}
+}
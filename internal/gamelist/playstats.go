@@ -0,0 +1,68 @@
+package gamelist
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+)
+
+// LastPlayedLayout is the time format EmulationStation uses for lastplayed and releasedate.
+const LastPlayedLayout = "20060102T150405"
+
+// PlayStats is what EmulationStation recorded about playing one game.
+type PlayStats struct {
+	Path       string
+	PlayCount  int
+	LastPlayed time.Time
+	Favorite   bool
+}
+
+// PlayStats returns the play history of every game that has been played or favorited.
+func (gl *GameList) PlayStats() []PlayStats {
+	root := gl.document.SelectElement(GameListElement)
+	if root == nil {
+		return nil
+	}
+
+	var stats []PlayStats
+	for _, game := range root.SelectElements(GameElement) {
+		s := PlayStats{Path: elementText(game.SelectElement(PathElement))}
+		if s.Path == "" {
+			continue
+		}
+		s.PlayCount, _ = strconv.Atoi(elementText(game.SelectElement(PlayCountElement)))
+		if t, err := time.Parse(LastPlayedLayout, elementText(game.SelectElement(LastPlayedElement))); err == nil {
+			s.LastPlayed = t
+		}
+		s.Favorite = strings.EqualFold(elementText(game.SelectElement(FavoriteElement)), "true")
+
+		if s.PlayCount > 0 || !s.LastPlayed.IsZero() || s.Favorite {
+			stats = append(stats, s)
+		}
+	}
+	return stats
+}
+
+// ReadPlayStats reads the play history from a gamelist.xml file.
+func ReadPlayStats(path string) ([]PlayStats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	gl := New()
+	if err := gl.Parse(data); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return gl.PlayStats(), nil
+}
+
+func elementText(e *etree.Element) string {
+	if e == nil {
+		return ""
+	}
+	return strings.TrimSpace(e.Text())
+}
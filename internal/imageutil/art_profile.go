@@ -0,0 +1,192 @@
+package imageutil
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"sort"
+
+	"golang.org/x/image/draw"
+)
+
+// ArtRole is what a piece of art is used for on the device, which decides how large it needs to be.
+type ArtRole string
+
+const (
+	ArtRoleCover   ArtRole = "cover"
+	ArtRolePreview ArtRole = "preview"
+	ArtRoleSplash  ArtRole = "splash"
+	ArtRoleMarquee ArtRole = "marquee"
+	ArtRoleBezel   ArtRole = "bezel"
+	ArtRoleBoxBack ArtRole = "boxback"
+	ArtRoleFanart  ArtRole = "fanart"
+)
+
+// ArtSize is the box art of a role is scaled down to fit. Zero leaves that dimension unbounded.
+type ArtSize struct {
+	MaxWidth  int
+	MaxHeight int
+}
+
+// ArtProfile describes how art written to the device is sized and encoded for its screen.
+type ArtProfile struct {
+	Sizes       map[ArtRole]ArtSize
+	Colors      int // Palette size for quantization; 0 keeps full color
+	Compression png.CompressionLevel
+}
+
+// lowResPixels is the screen area at or below which art is quantized to save space.
+const lowResPixels = 640 * 480
+
+// NewArtProfile derives an art profile from the size of the device screen. Small screens
+// get paletted images, since the extra colors are invisible there but cost SD card space.
+func NewArtProfile(screenWidth, screenHeight int) ArtProfile {
+	p := ArtProfile{
+		Sizes: map[ArtRole]ArtSize{
+			ArtRoleCover:   {screenWidth / 2, screenHeight},
+			ArtRolePreview: {screenWidth, screenHeight},
+			ArtRoleSplash:  {screenWidth, screenHeight},
+			ArtRoleMarquee: {screenWidth / 2, screenHeight / 4},
+			ArtRoleBezel:   {screenWidth, screenHeight},
+			ArtRoleBoxBack: {screenWidth / 2, screenHeight},
+			ArtRoleFanart:  {screenWidth, screenHeight},
+		},
+		Compression: png.DefaultCompression,
+	}
+	if screenWidth*screenHeight <= lowResPixels {
+		p.Colors = 256
+		p.Compression = png.BestCompression
+	}
+	return p
+}
+
+// Process rewrites the image at path as a PNG that fits the profile for role. Images that
+// already fit and are PNGs are left untouched, so processing the same file again is cheap.
+func (p ArtProfile) Process(path string, role ArtRole) error {
+	inputFile, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open image: %w", err)
+	}
+	img, format, err := image.Decode(inputFile)
+	inputFile.Close()
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	processed := fitImage(img, p.Sizes[role])
+	if p.Colors > 0 && !hasPaletteWithin(processed, p.Colors) {
+		processed = quantize(processed, p.Colors)
+	}
+
+	if format == "png" && processed == img {
+		return nil
+	}
+
+	tmpPath := path + ".tmp"
+	outputFile, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	encoder := png.Encoder{CompressionLevel: p.Compression}
+	if err := encoder.Encode(outputFile, processed); err != nil {
+		outputFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	if err := outputFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace image: %w", err)
+	}
+	return nil
+}
+
+// fitImage scales img down to fit size, keeping its aspect ratio. It never scales up and
+// returns img itself when no scaling is needed.
+func fitImage(img image.Image, size ArtSize) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	scale := 1.0
+	if size.MaxWidth > 0 && w > size.MaxWidth {
+		scale = float64(size.MaxWidth) / float64(w)
+	}
+	if size.MaxHeight > 0 && h > size.MaxHeight {
+		scale = min(scale, float64(size.MaxHeight)/float64(h))
+	}
+	if scale >= 1 {
+		return img
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, max(int(float64(w)*scale), 1), max(int(float64(h)*scale), 1)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+func hasPaletteWithin(img image.Image, colors int) bool {
+	paletted, ok := img.(*image.Paletted)
+	return ok && len(paletted.Palette) <= colors
+}
+
+// quantize reduces img to at most colors colors (capped at 256, the PNG palette limit),
+// picking the most common colors and dithering the rest.
+func quantize(img image.Image, colors int) *image.Paletted {
+	colors = min(colors, 256)
+	bounds := img.Bounds()
+
+	// Bucket colors by their top four bits per channel and average each bucket.
+	type bucket struct {
+		r, g, b, a, n uint64
+	}
+	buckets := make(map[uint16]*bucket)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			key := uint16(c.R>>4)<<12 | uint16(c.G>>4)<<8 | uint16(c.B>>4)<<4 | uint16(c.A>>4)
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.r += uint64(c.R)
+			bk.g += uint64(c.G)
+			bk.b += uint64(c.B)
+			bk.a += uint64(c.A)
+			bk.n++
+		}
+	}
+
+	keys := make([]uint16, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if buckets[keys[i]].n != buckets[keys[j]].n {
+			return buckets[keys[i]].n > buckets[keys[j]].n
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > colors {
+		keys = keys[:colors]
+	}
+
+	palette := make(color.Palette, len(keys))
+	for i, k := range keys {
+		bk := buckets[k]
+		palette[i] = color.NRGBA{
+			R: uint8(bk.r / bk.n),
+			G: uint8(bk.g / bk.n),
+			B: uint8(bk.b / bk.n),
+			A: uint8(bk.a / bk.n),
+		}
+	}
+
+	dst := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), palette)
+	draw.FloydSteinberg.Draw(dst, dst.Bounds(), img, bounds.Min)
+	return dst
+}
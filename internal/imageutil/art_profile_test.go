@@ -0,0 +1,142 @@
+package imageutil
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePNG(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func decodeFile(t *testing.T, path string) (image.Image, string) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, format, err := image.Decode(f)
+	if err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+	return img, format
+}
+
+func gradient(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x ^ y), A: 255})
+		}
+	}
+	return img
+}
+
+func TestNewArtProfile(t *testing.T) {
+	low := NewArtProfile(640, 480)
+	if low.Colors != 256 || low.Compression != png.BestCompression {
+		t.Errorf("640x480 profile = %d colors, compression %d; want 256, best", low.Colors, low.Compression)
+	}
+	if got := low.Sizes[ArtRoleCover]; got != (ArtSize{320, 480}) {
+		t.Errorf("640x480 cover size = %v, want {320 480}", got)
+	}
+
+	high := NewArtProfile(1280, 720)
+	if high.Colors != 0 {
+		t.Errorf("1280x720 profile quantizes to %d colors, want full color", high.Colors)
+	}
+}
+
+func TestArtProfileProcess(t *testing.T) {
+	dir := t.TempDir()
+	profile := ArtProfile{
+		Sizes:       map[ArtRole]ArtSize{ArtRoleCover: {MaxWidth: 100, MaxHeight: 100}},
+		Colors:      16,
+		Compression: png.BestCompression,
+	}
+
+	t.Run("scales down and quantizes", func(t *testing.T) {
+		path := filepath.Join(dir, "cover.png")
+		writePNG(t, path, gradient(200, 100))
+
+		if err := profile.Process(path, ArtRoleCover); err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+
+		img, format := decodeFile(t, path)
+		if format != "png" {
+			t.Errorf("format = %s, want png", format)
+		}
+		if got := img.Bounds().Size(); got != image.Pt(100, 50) {
+			t.Errorf("size = %v, want 100x50", got)
+		}
+		paletted, ok := img.(*image.Paletted)
+		if !ok {
+			t.Fatalf("image is %T, want *image.Paletted", img)
+		}
+		if len(paletted.Palette) > 16 {
+			t.Errorf("palette has %d colors, want at most 16", len(paletted.Palette))
+		}
+	})
+
+	t.Run("never scales up and converts to png", func(t *testing.T) {
+		path := filepath.Join(dir, "small.png")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := jpeg.Encode(f, gradient(40, 30), nil); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		if err := (ArtProfile{}).Process(path, ArtRoleCover); err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+
+		img, format := decodeFile(t, path)
+		if format != "png" {
+			t.Errorf("format = %s, want png", format)
+		}
+		if got := img.Bounds().Size(); got != image.Pt(40, 30) {
+			t.Errorf("size = %v, want 40x30", got)
+		}
+	})
+
+	t.Run("leaves fitting art untouched", func(t *testing.T) {
+		path := filepath.Join(dir, "fits.png")
+		writePNG(t, path, gradient(50, 50))
+		if err := profile.Process(path, ArtRoleCover); err != nil {
+			t.Fatal(err)
+		}
+		before, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := profile.Process(path, ArtRoleCover); err != nil {
+			t.Fatalf("second Process: %v", err)
+		}
+		after, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !after.ModTime().Equal(before.ModTime()) || after.Size() != before.Size() {
+			t.Error("already processed art was rewritten")
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package imageutil
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/image/draw"
+)
+
+// Anchor is the corner or edge of the canvas a composite layer is pinned to.
+type Anchor int
+
+const (
+	AnchorCenter Anchor = iota
+	AnchorTopLeft
+	AnchorTopCenter
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomCenter
+	AnchorBottomRight
+)
+
+// Slot is the box a layer is scaled into, keeping its aspect ratio. Width, Height
+// and Margin are fractions of the canvas so templates work at any resolution.
+type Slot struct {
+	Anchor Anchor
+	Width  float64
+	Height float64
+	Margin float64
+}
+
+// CompositeTemplate lays out a Skraper-style mix image: a screenshot behind, the
+// box art in front of it and the logo on top. Uncovered areas stay transparent.
+type CompositeTemplate struct {
+	Width      int
+	Height     int
+	Background Slot
+	Box        Slot
+	Logo       Slot
+}
+
+// CompositeLayers are the images a mix is built from. Any of them may be nil.
+type CompositeLayers struct {
+	Background image.Image
+	Box        image.Image
+	Logo       image.Image
+}
+
+var ErrNoLayers = errors.New("no images to composite")
+
+var (
+	// CompositeTemplateMix matches the 4:3 mix images EmulationStation themes and
+	// most Miyoo frontends are designed around.
+	CompositeTemplateMix = CompositeTemplate{
+		Width:      640,
+		Height:     480,
+		Background: Slot{Anchor: AnchorTopRight, Width: 0.92, Height: 0.88},
+		Box:        Slot{Anchor: AnchorBottomLeft, Width: 0.42, Height: 0.62},
+		Logo:       Slot{Anchor: AnchorBottomRight, Width: 0.5, Height: 0.3, Margin: 0.02},
+	}
+
+	// CompositeTemplateSquare suits frontends that show art beside the game list,
+	// like NextUI and MinUI, where a wide image would be shrunk to a sliver.
+	CompositeTemplateSquare = CompositeTemplate{
+		Width:      512,
+		Height:     512,
+		Background: Slot{Anchor: AnchorTopCenter, Width: 1, Height: 0.75},
+		Box:        Slot{Anchor: AnchorBottomLeft, Width: 0.45, Height: 0.55},
+		Logo:       Slot{Anchor: AnchorTopCenter, Width: 0.8, Height: 0.25, Margin: 0.03},
+	}
+)
+
+// Composite renders layers into a new transparent canvas following t.
+func Composite(t CompositeTemplate, layers CompositeLayers) (*image.NRGBA, error) {
+	if layers.Background == nil && layers.Box == nil && layers.Logo == nil {
+		return nil, ErrNoLayers
+	}
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, t.Width, t.Height))
+	for _, layer := range []struct {
+		img  image.Image
+		slot Slot
+	}{
+		{layers.Background, t.Background},
+		{layers.Box, t.Box},
+		{layers.Logo, t.Logo},
+	} {
+		if layer.img == nil {
+			continue
+		}
+		dst := placeInSlot(canvas.Bounds(), layer.slot, layer.img.Bounds())
+		draw.CatmullRom.Scale(canvas, dst, layer.img, layer.img.Bounds(), draw.Over, nil)
+	}
+
+	return canvas, nil
+}
+
+// CompositeFiles builds a mix from image files and writes it as a PNG to outputPath.
+// Empty or unreadable layer paths are skipped so a game missing a logo still gets art.
+func CompositeFiles(t CompositeTemplate, backgroundPath, boxPath, logoPath, outputPath string) error {
+	layers := CompositeLayers{
+		Background: decodeOptional(backgroundPath),
+		Box:        decodeOptional(boxPath),
+		Logo:       decodeOptional(logoPath),
+	}
+
+	img, err := Composite(t, layers)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	if err := png.Encode(outputFile, img); err != nil {
+		return fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return nil
+}
+
+func decodeOptional(path string) image.Image {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// placeInSlot returns where an image of size src lands when scaled to fit slot.
+func placeInSlot(canvas image.Rectangle, slot Slot, src image.Rectangle) image.Rectangle {
+	cw, ch := float64(canvas.Dx()), float64(canvas.Dy())
+	boxW, boxH := slot.Width*cw, slot.Height*ch
+	margin := int(slot.Margin * cw)
+
+	scale := min(boxW/float64(src.Dx()), boxH/float64(src.Dy()))
+	w := max(int(float64(src.Dx())*scale), 1)
+	h := max(int(float64(src.Dy())*scale), 1)
+
+	var x, y int
+	switch slot.Anchor {
+	case AnchorTopLeft, AnchorBottomLeft:
+		x = margin
+	case AnchorTopRight, AnchorBottomRight:
+		x = canvas.Dx() - w - margin
+	default:
+		x = (canvas.Dx() - w) / 2
+	}
+	switch slot.Anchor {
+	case AnchorTopLeft, AnchorTopCenter, AnchorTopRight:
+		y = margin
+	case AnchorBottomLeft, AnchorBottomCenter, AnchorBottomRight:
+		y = canvas.Dy() - h - margin
+	default:
+		y = (canvas.Dy() - h) / 2
+	}
+
+	return image.Rect(x, y, x+w, y+h).Add(canvas.Min)
+}
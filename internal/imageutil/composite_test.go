@@ -0,0 +1,103 @@
+package imageutil
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestComposite(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	green := color.NRGBA{G: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+
+	tmpl := CompositeTemplate{
+		Width:      100,
+		Height:     100,
+		Background: Slot{Anchor: AnchorTopRight, Width: 0.8, Height: 0.8},
+		Box:        Slot{Anchor: AnchorBottomLeft, Width: 0.4, Height: 0.4},
+		Logo:       Slot{Anchor: AnchorTopCenter, Width: 0.2, Height: 0.2},
+	}
+
+	img, err := Composite(tmpl, CompositeLayers{
+		Background: solid(40, 40, red),
+		Box:        solid(10, 20, green),
+		Logo:       solid(20, 20, blue),
+	})
+	if err != nil {
+		t.Fatalf("Composite: %v", err)
+	}
+	if got := img.Bounds(); got != image.Rect(0, 0, 100, 100) {
+		t.Fatalf("bounds = %v, want 100x100", got)
+	}
+
+	tests := []struct {
+		name string
+		x, y int
+		want color.NRGBA
+	}{
+		{"background", 90, 70, red},
+		{"box over background", 5, 95, green},
+		{"logo on top", 50, 10, blue},
+		{"uncovered area stays transparent", 1, 1, color.NRGBA{}},
+	}
+	for _, tt := range tests {
+		if got := img.NRGBAAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: pixel (%d,%d) = %v, want %v", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	// The box keeps its 1:2 aspect ratio inside its 40x40 slot.
+	if got := img.NRGBAAt(25, 95); got.G == 255 {
+		t.Error("box was stretched beyond its aspect ratio")
+	}
+}
+
+func TestCompositeFiles(t *testing.T) {
+	dir := t.TempDir()
+	boxPath := filepath.Join(dir, "box.png")
+	f, err := os.Create(boxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, solid(8, 8, color.NRGBA{G: 255, A: 255})); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	out := filepath.Join(dir, "out", "mix.png")
+	if err := CompositeFiles(CompositeTemplateMix, "", boxPath, filepath.Join(dir, "missing.png"), out); err != nil {
+		t.Fatalf("CompositeFiles: %v", err)
+	}
+
+	rf, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("open output: %v", err)
+	}
+	defer rf.Close()
+	cfg, format, err := image.DecodeConfig(rf)
+	if err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if format != "png" || cfg.Width != CompositeTemplateMix.Width || cfg.Height != CompositeTemplateMix.Height {
+		t.Errorf("output = %s %dx%d, want png %dx%d", format, cfg.Width, cfg.Height, CompositeTemplateMix.Width, CompositeTemplateMix.Height)
+	}
+
+	if err := CompositeFiles(CompositeTemplateMix, "", "", "", out); !errors.Is(err, ErrNoLayers) {
+		t.Errorf("CompositeFiles with no layers error = %v, want ErrNoLayers", err)
+	}
+}
@@ -0,0 +1,126 @@
+// Package maptxt reads and writes the map.txt files NextUI and MinUI use to show a
+// friendly name for a file in a ROM folder instead of its file name.
+package maptxt
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the name of the alias file inside each ROM folder.
+const FileName = "map.txt"
+
+// Map is the contents of a map.txt. Line order is kept so user edits survive a rewrite.
+type Map struct {
+	lines []line
+}
+
+type line struct {
+	file string
+	name string
+	raw  string // set for lines that aren't a file/name pair, written back unchanged
+}
+
+// Load reads a map.txt, returning an empty map when the file doesn't exist yet.
+func Load(path string) (*Map, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Map{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return Parse(data), nil
+}
+
+// Parse reads map.txt contents. Each line is a file name and a display name separated by a tab.
+func Parse(data []byte) *Map {
+	m := &Map{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		file, name, ok := strings.Cut(text, "\t")
+		if !ok || file == "" {
+			m.lines = append(m.lines, line{raw: text})
+			continue
+		}
+		m.lines = append(m.lines, line{file: file, name: name})
+	}
+	return m
+}
+
+// Name returns the display name for file, if it has one.
+func (m *Map) Name(file string) (string, bool) {
+	for _, l := range m.lines {
+		if l.file == file {
+			return l.name, true
+		}
+	}
+	return "", false
+}
+
+// Add maps file to name unless file already has a name, so names the user chose are
+// never replaced. It reports whether the map changed.
+func (m *Map) Add(file, name string) bool {
+	if _, exists := m.Name(file); exists {
+		return false
+	}
+	m.lines = append(m.lines, line{file: file, name: name})
+	return true
+}
+
+// Prune removes the names of files that no longer exist and returns how many were removed.
+func (m *Map) Prune(exists func(file string) bool) int {
+	kept := m.lines[:0]
+	for _, l := range m.lines {
+		if l.file == "" || exists(l.file) {
+			kept = append(kept, l)
+		}
+	}
+	removed := len(m.lines) - len(kept)
+	m.lines = kept
+	return removed
+}
+
+// Bytes returns the map in map.txt format.
+func (m *Map) Bytes() []byte {
+	var buf bytes.Buffer
+	for _, l := range m.lines {
+		if l.file == "" {
+			buf.WriteString(l.raw)
+		} else {
+			buf.WriteString(l.file + "\t" + l.name)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// Save writes the map, replacing the file atomically. A map with nothing left in it
+// removes the file instead.
+func (m *Map) Save(path string) error {
+	if len(strings.TrimSpace(string(m.Bytes()))) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, m.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
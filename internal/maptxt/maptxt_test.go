@@ -0,0 +1,53 @@
+package maptxt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMapKeepsUserEntries(t *testing.T) {
+	m := Parse([]byte("Zelda (USA).sfc\tMy Zelda\r\n# comment\n"))
+
+	if m.Add("Zelda (USA).sfc", "The Legend of Zelda (USA)") {
+		t.Error("Add replaced a user's name")
+	}
+	if !m.Add("Mario (USA).sfc", "Super Mario World (USA)") {
+		t.Error("Add didn't add a new file")
+	}
+
+	want := "Zelda (USA).sfc\tMy Zelda\n# comment\nMario (USA).sfc\tSuper Mario World (USA)\n"
+	if got := string(m.Bytes()); got != want {
+		t.Errorf("Bytes() = %q, want %q", got, want)
+	}
+}
+
+func TestMapPruneAndSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, []byte("a.gb\tA\nb.gb\tB\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if removed := m.Prune(func(file string) bool { return file == "a.gb" }); removed != 1 {
+		t.Errorf("Prune removed %d, want 1", removed)
+	}
+	if err := m.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "a.gb\tA\n" {
+		t.Errorf("saved %q, want only a.gb", data)
+	}
+
+	m.Prune(func(string) bool { return false })
+	if err := m.Save(path); err != nil {
+		t.Fatalf("Save empty: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("empty map.txt was not removed")
+	}
+}
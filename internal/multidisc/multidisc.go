@@ -0,0 +1,288 @@
+// Package multidisc detects multi-disc games, builds their .m3u playlists and lays
+// the disc files out the way each CFW expects to find them.
+package multidisc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Layout describes where the disc files of a game live relative to its playlist.
+type Layout int
+
+const (
+	// LayoutGameFolder keeps discs in "<game>/" with "<game>/<game>.m3u" beside them (NextUI, MinUI).
+	LayoutGameFolder Layout = iota
+	// LayoutUnderscoreDir moves discs to "_<game>/" with the playlist in the ROM directory (muOS).
+	LayoutUnderscoreDir
+	// LayoutHiddenSubfolder moves discs to ".<game>/" with the playlist in the ROM directory.
+	LayoutHiddenSubfolder
+	// LayoutHiddenDir moves discs to ".hidden/<game>/" with the playlist in the ROM directory.
+	LayoutHiddenDir
+)
+
+const PlaylistExtension = ".m3u"
+
+var ErrNoDiscs = errors.New("no disc images found")
+
+var ErrInvalidGameName = errors.New("invalid game name")
+
+var discRegex = regexp.MustCompile(`(?i)(?:^|[\s(\[_-])(?:disc|disk|cd)(?:[\s_-]*([0-9]+)|[\s_-]+([a-f]))\b`)
+
+// sheetExtensions describe a disc whose data lives in companion track files.
+var sheetExtensions = []string{".cue", ".gdi", ".ccd", ".mds", ".toc"}
+
+// imageExtensions are self-contained disc images.
+var imageExtensions = []string{".chd", ".iso", ".cso", ".pbp", ".rvz", ".gcz", ".wbfs", ".cdi", ".zso", ".ecm"}
+
+// trackExtensions are only referenced through a sheet and never listed in a playlist.
+var trackExtensions = []string{".bin", ".img", ".sub", ".raw", ".wav", ".mdf"}
+
+// DiscNumber extracts the disc number from names such as "Game (Disc 2).chd",
+// "Game CD2.iso" or "Game (Disk B).cue". Letters are numbered from 1.
+func DiscNumber(name string) (int, bool) {
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	m := discRegex.FindStringSubmatch(base)
+	if m == nil {
+		return 0, false
+	}
+	if m[1] != "" {
+		n, err := strconv.Atoi(m[1])
+		return n, err == nil
+	}
+	return int(strings.ToLower(m[2])[0]-'a') + 1, true
+}
+
+// IsDiscSet reports whether the files describe two or more numbered discs.
+func IsDiscSet(files []string) bool {
+	seen := make(map[int]bool)
+	for _, f := range PlaylistEntries(files) {
+		if n, ok := DiscNumber(f); ok {
+			seen[n] = true
+		}
+	}
+	return len(seen) > 1
+}
+
+// PlaylistEntries picks the files that belong in a playlist, ordered by disc number.
+// When cue sheets (or similar) are present their track files are skipped.
+func PlaylistEntries(files []string) []string {
+	hasSheet := slices.ContainsFunc(files, func(f string) bool {
+		return slices.Contains(sheetExtensions, strings.ToLower(filepath.Ext(f)))
+	})
+
+	var entries []string
+	for _, f := range files {
+		ext := strings.ToLower(filepath.Ext(f))
+		switch {
+		case slices.Contains(sheetExtensions, ext):
+			entries = append(entries, f)
+		case slices.Contains(imageExtensions, ext):
+			entries = append(entries, f)
+		case !hasSheet && slices.Contains(trackExtensions, ext):
+			entries = append(entries, f)
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b string) int {
+		na, _ := DiscNumber(a)
+		nb, _ := DiscNumber(b)
+		if na != nb {
+			return na - nb
+		}
+		return strings.Compare(a, b)
+	})
+	return entries
+}
+
+// ParsePlaylist returns the non-comment entries of an .m3u file.
+func ParsePlaylist(data []byte) []string {
+	var entries []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "\uFEFF"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return entries
+}
+
+// BuildPlaylist renders entries as an .m3u file.
+func BuildPlaylist(entries []string) []byte {
+	return []byte(strings.Join(entries, "\n") + "\n")
+}
+
+// PlaylistPath returns where the playlist for gameName is written under layout.
+func PlaylistPath(romDirectory, gameName string, layout Layout) string {
+	if layout == LayoutGameFolder {
+		return filepath.Join(romDirectory, gameName, gameName+PlaylistExtension)
+	}
+	return filepath.Join(romDirectory, gameName+PlaylistExtension)
+}
+
+// DiscDirectory returns the folder holding the disc files for gameName under layout.
+func DiscDirectory(romDirectory, gameName string, layout Layout) string {
+	switch layout {
+	case LayoutUnderscoreDir:
+		return filepath.Join(romDirectory, "_"+gameName)
+	case LayoutHiddenSubfolder:
+		return filepath.Join(romDirectory, "."+gameName)
+	case LayoutHiddenDir:
+		return filepath.Join(romDirectory, ".hidden", gameName)
+	default:
+		return filepath.Join(romDirectory, gameName)
+	}
+}
+
+// ValidateGameName rejects names that would not resolve to a single entry inside the
+// ROM directory. Organize clears the disc directory it derives from the name, so an
+// empty name, a path separator or ".." must never reach it.
+func ValidateGameName(gameName string) error {
+	if strings.TrimSpace(gameName) == "" || gameName == "." || gameName == ".." || strings.ContainsAny(gameName, `/\`) {
+		return fmt.Errorf("%w: %q", ErrInvalidGameName, gameName)
+	}
+	return nil
+}
+
+// isInside reports whether target lies strictly below dir.
+func isInside(dir, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == "." || filepath.IsAbs(rel) {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// HasPlaylist reports whether dir contains an .m3u file at any depth.
+func HasPlaylist(dir string) bool {
+	files, err := listFiles(dir)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(files, isPlaylist)
+}
+
+// Organize moves the extracted contents of a multi-disc game from extractDir into
+// the location dictated by layout and writes a playlist pointing at every disc.
+// A playlist shipped inside the archive is kept when its entries resolve, otherwise
+// one is generated from the disc files. It returns the playlist path.
+func Organize(extractDir, romDirectory, gameName string, layout Layout) (string, error) {
+	if err := ValidateGameName(gameName); err != nil {
+		return "", err
+	}
+
+	files, err := listFiles(extractDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read extracted directory: %w", err)
+	}
+
+	entries := repairPlaylist(extractDir, files)
+	if len(entries) == 0 {
+		entries = PlaylistEntries(files)
+	}
+	if len(entries) == 0 {
+		return "", ErrNoDiscs
+	}
+
+	for _, f := range files {
+		if isPlaylist(f) {
+			if err := os.Remove(filepath.Join(extractDir, filepath.FromSlash(f))); err != nil {
+				return "", fmt.Errorf("failed to remove original playlist: %w", err)
+			}
+		}
+	}
+
+	discDir := DiscDirectory(romDirectory, gameName, layout)
+	if !isInside(romDirectory, discDir) {
+		return "", fmt.Errorf("%w: %s is outside %s", ErrInvalidGameName, discDir, romDirectory)
+	}
+	if filepath.Clean(discDir) != filepath.Clean(extractDir) {
+		if err := os.RemoveAll(discDir); err != nil {
+			return "", fmt.Errorf("failed to clear %s: %w", discDir, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(discDir), 0755); err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(discDir), err)
+		}
+		if err := os.Rename(extractDir, discDir); err != nil {
+			return "", fmt.Errorf("failed to move discs to %s: %w", discDir, err)
+		}
+	}
+
+	playlistPath := PlaylistPath(romDirectory, gameName, layout)
+	if prefix, err := filepath.Rel(filepath.Dir(playlistPath), discDir); err == nil && prefix != "." {
+		for i, entry := range entries {
+			entries[i] = path.Join(filepath.ToSlash(prefix), entry)
+		}
+	}
+
+	if err := os.WriteFile(playlistPath, BuildPlaylist(entries), 0644); err != nil {
+		return "", fmt.Errorf("failed to write playlist: %w", err)
+	}
+
+	return playlistPath, nil
+}
+
+// repairPlaylist resolves the entries of the first playlist found in files against
+// the files actually extracted, dropping any that point nowhere. Entries are
+// returned relative to extractDir.
+func repairPlaylist(extractDir string, files []string) []string {
+	idx := slices.IndexFunc(files, isPlaylist)
+	if idx < 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(extractDir, filepath.FromSlash(files[idx])))
+	if err != nil {
+		return nil
+	}
+
+	playlistDir := path.Dir(files[idx])
+	var entries []string
+	for _, entry := range ParsePlaylist(data) {
+		entry = strings.ReplaceAll(entry, "\\", "/")
+		candidate := path.Clean(path.Join(playlistDir, entry))
+		if slices.Contains(files, candidate) {
+			entries = append(entries, candidate)
+			continue
+		}
+		base := path.Base(entry)
+		if i := slices.IndexFunc(files, func(f string) bool { return strings.EqualFold(path.Base(f), base) }); i >= 0 {
+			entries = append(entries, files[i])
+		}
+	}
+	return entries
+}
+
+func isPlaylist(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), PlaylistExtension)
+}
+
+// listFiles returns every regular file under dir as a slash-separated relative path.
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	slices.Sort(files)
+	return files, err
+}
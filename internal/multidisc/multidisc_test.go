@@ -0,0 +1,176 @@
+package multidisc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDiscNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   int
+		wantOK bool
+	}{
+		{"Final Fantasy VII (USA) (Disc 2).chd", 2, true},
+		{"Metal Gear Solid CD1.iso", 1, true},
+		{"Game (Disk B).cue", 2, true},
+		{"Game_disc_3.bin", 3, true},
+		{"Disco Elysium.iso", 0, false},
+		{"Crash Bandicoot (USA).cue", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := DiscNumber(tt.name)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("DiscNumber(%q) = %d, %v; want %d, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestPlaylistEntries(t *testing.T) {
+	files := []string{
+		"Game (Disc 2).cue", "Game (Disc 2).bin",
+		"Game (Disc 1).cue", "Game (Disc 1) (Track 1).bin", "Game (Disc 1) (Track 2).bin",
+		"readme.txt",
+	}
+	want := []string{"Game (Disc 1).cue", "Game (Disc 2).cue"}
+	if got := PlaylistEntries(files); !slices.Equal(got, want) {
+		t.Errorf("PlaylistEntries() = %v, want %v", got, want)
+	}
+
+	if !IsDiscSet(files) {
+		t.Error("IsDiscSet() = false for a two disc set")
+	}
+	if IsDiscSet([]string{"Game.cue", "Game (Track 1).bin", "Game (Track 2).bin"}) {
+		t.Error("IsDiscSet() = true for a single disc with several tracks")
+	}
+}
+
+func TestParsePlaylist(t *testing.T) {
+	data := []byte("\uFEFF#EXTM3U\r\nGame (Disc 1).chd\r\n\r\n# comment\nGame (Disc 2).chd")
+	want := []string{"Game (Disc 1).chd", "Game (Disc 2).chd"}
+	if got := ParsePlaylist(data); !slices.Equal(got, want) {
+		t.Errorf("ParsePlaylist() = %v, want %v", got, want)
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOrganize(t *testing.T) {
+	const game = "Game (USA)"
+	tests := []struct {
+		layout       Layout
+		wantPlaylist string
+		wantDisc     string
+		wantEntries  []string
+	}{
+		{LayoutGameFolder, "Game (USA)/Game (USA).m3u", "Game (USA)/Game (USA) (Disc 1).chd",
+			[]string{"Game (USA) (Disc 1).chd", "Game (USA) (Disc 2).chd"}},
+		{LayoutUnderscoreDir, "Game (USA).m3u", "_Game (USA)/Game (USA) (Disc 1).chd",
+			[]string{"_Game (USA)/Game (USA) (Disc 1).chd", "_Game (USA)/Game (USA) (Disc 2).chd"}},
+		{LayoutHiddenSubfolder, "Game (USA).m3u", ".Game (USA)/Game (USA) (Disc 1).chd",
+			[]string{".Game (USA)/Game (USA) (Disc 1).chd", ".Game (USA)/Game (USA) (Disc 2).chd"}},
+		{LayoutHiddenDir, "Game (USA).m3u", ".hidden/Game (USA)/Game (USA) (Disc 1).chd",
+			[]string{".hidden/Game (USA)/Game (USA) (Disc 1).chd", ".hidden/Game (USA)/Game (USA) (Disc 2).chd"}},
+	}
+	for _, tt := range tests {
+		romDir := t.TempDir()
+		extractDir := filepath.Join(romDir, game)
+		writeFiles(t, extractDir, map[string]string{
+			"Game (USA) (Disc 2).chd": "2",
+			"Game (USA) (Disc 1).chd": "1",
+		})
+
+		got, err := Organize(extractDir, romDir, game, tt.layout)
+		if err != nil {
+			t.Fatalf("Organize(layout %d): %v", tt.layout, err)
+		}
+		if want := filepath.Join(romDir, filepath.FromSlash(tt.wantPlaylist)); got != want {
+			t.Errorf("Organize(layout %d) playlist = %q, want %q", tt.layout, got, want)
+		}
+		if _, err := os.Stat(filepath.Join(romDir, filepath.FromSlash(tt.wantDisc))); err != nil {
+			t.Errorf("Organize(layout %d) disc not moved: %v", tt.layout, err)
+		}
+		data, err := os.ReadFile(got)
+		if err != nil {
+			t.Fatalf("read playlist: %v", err)
+		}
+		if entries := ParsePlaylist(data); !slices.Equal(entries, tt.wantEntries) {
+			t.Errorf("Organize(layout %d) entries = %v, want %v", tt.layout, entries, tt.wantEntries)
+		}
+	}
+}
+
+func TestOrganizeRepairsShippedPlaylist(t *testing.T) {
+	romDir := t.TempDir()
+	extractDir := filepath.Join(romDir, "Game")
+	writeFiles(t, extractDir, map[string]string{
+		"Game.m3u":             "C:\\dump\\Game (Disc 2).cue\nGame (Disc 1).cue\nmissing.cue\n",
+		"Game (Disc 1).cue":    "",
+		"Game (Disc 1).bin":    "",
+		"Game (Disc 2).cue":    "",
+		"Game (Disc 2).bin":    "",
+		"extras/readme.txt":    "",
+		"extras/cover art.png": "",
+	})
+
+	got, err := Organize(extractDir, romDir, "Game", LayoutUnderscoreDir)
+	if err != nil {
+		t.Fatalf("Organize: %v", err)
+	}
+	data, err := os.ReadFile(got)
+	if err != nil {
+		t.Fatalf("read playlist: %v", err)
+	}
+	want := []string{"_Game/Game (Disc 2).cue", "_Game/Game (Disc 1).cue"}
+	if entries := ParsePlaylist(data); !slices.Equal(entries, want) {
+		t.Errorf("entries = %v, want %v", entries, want)
+	}
+	if _, err := os.Stat(filepath.Join(romDir, "_Game", "Game.m3u")); !os.IsNotExist(err) {
+		t.Error("shipped playlist was left inside the disc directory")
+	}
+}
+
+func TestOrganizeNoDiscs(t *testing.T) {
+	romDir := t.TempDir()
+	extractDir := filepath.Join(romDir, "Game")
+	writeFiles(t, extractDir, map[string]string{"game.exe": ""})
+
+	if _, err := Organize(extractDir, romDir, "Game", LayoutHiddenDir); err != ErrNoDiscs {
+		t.Errorf("Organize() error = %v, want ErrNoDiscs", err)
+	}
+}
+
+func TestOrganizeRejectsUnsafeNames(t *testing.T) {
+	for _, name := range []string{"", " ", ".", "..", "../Other", "sub/Game", `sub\Game`} {
+		romDir := t.TempDir()
+		extractDir := filepath.Join(romDir, "Game")
+		writeFiles(t, extractDir, map[string]string{"Game (Disc 1).chd": "1", "Game (Disc 2).chd": "2"})
+		keep := filepath.Join(romDir, "Keep.gba")
+		writeFiles(t, romDir, map[string]string{"Keep.gba": ""})
+
+		if _, err := Organize(extractDir, romDir, name, LayoutGameFolder); !errors.Is(err, ErrInvalidGameName) {
+			t.Errorf("Organize(%q) error = %v, want ErrInvalidGameName", name, err)
+		}
+		if _, err := os.Stat(keep); err != nil {
+			t.Errorf("Organize(%q) touched the ROM directory: %v", name, err)
+		}
+	}
+
+	if err := ValidateGameName("Game... Special Edition (Disc 1)"); err != nil {
+		t.Errorf("ValidateGameName rejected a name with dots: %v", err)
+	}
+}
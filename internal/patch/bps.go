@@ -0,0 +1,181 @@
+package patch
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+)
+
+const (
+	bpsSourceRead = iota
+	bpsTargetRead
+	bpsSourceCopy
+	bpsTargetCopy
+)
+
+// checksumFooterSize is the source, target and patch CRC32 trailer shared by BPS and UPS.
+const checksumFooterSize = 12
+
+const (
+	// maxTargetSize caps the ROM a patch may declare, whatever the source size.
+	maxTargetSize = 1 << 30
+	// maxTargetGrowth is how many times larger than the source, plus minTargetLimit,
+	// a patched ROM may be. Real patches expand ROMs by a few megabytes at most, so a
+	// larger size means the header is corrupt or hostile.
+	maxTargetGrowth = 4
+	minTargetLimit  = 64 << 20
+)
+
+// checkTargetSize rejects target sizes no real patch produces before they are allocated.
+func checkTargetSize(targetSize uint64, sourceSize int) (int, error) {
+	limit := min(uint64(sourceSize)*maxTargetGrowth+minTargetLimit, maxTargetSize)
+	if targetSize > limit {
+		return 0, ErrCorruptPatch
+	}
+	return int(targetSize), nil
+}
+
+// ApplyBPS applies a BPS patch, verifying the source, target and patch checksums.
+func ApplyBPS(source, patch []byte) ([]byte, error) {
+	if DetectFormat(patch) != FormatBPS {
+		return nil, ErrUnknownFormat
+	}
+	sourceCRC, targetCRC, err := checkFooter(patch)
+	if err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(source) != sourceCRC {
+		return nil, ErrSourceMismatch
+	}
+
+	r := &varintReader{data: patch[:len(patch)-checksumFooterSize], pos: 4}
+	sourceSize := r.next()
+	targetSize := r.next()
+	metadataSize := r.nextInt()
+	if r.err != nil || sourceSize != uint64(len(source)) || metadataSize > len(r.data)-r.pos {
+		return nil, ErrCorruptPatch
+	}
+	r.pos += metadataSize
+
+	size, err := checkTargetSize(targetSize, len(source))
+	if err != nil {
+		return nil, err
+	}
+	target := make([]byte, size)
+	var out, sourceRel, targetRel int
+
+	// Lengths and offsets are compared against what is left rather than added first,
+	// so a hostile patch can't overflow int on 32-bit devices.
+	for r.pos < len(r.data) {
+		data := r.nextInt()
+		if r.err != nil {
+			return nil, ErrCorruptPatch
+		}
+		length := data>>2 + 1
+		if length > len(target)-out {
+			return nil, ErrCorruptPatch
+		}
+
+		switch data & 3 {
+		case bpsSourceRead:
+			if length > len(source)-out {
+				return nil, ErrCorruptPatch
+			}
+			copy(target[out:], source[out:out+length])
+		case bpsTargetRead:
+			if length > len(r.data)-r.pos {
+				return nil, ErrCorruptPatch
+			}
+			copy(target[out:], r.data[r.pos:r.pos+length])
+			r.pos += length
+		case bpsSourceCopy:
+			offset := r.signed()
+			if r.err != nil || offset < -sourceRel || offset > len(source)-sourceRel {
+				return nil, ErrCorruptPatch
+			}
+			sourceRel += offset
+			if length > len(source)-sourceRel {
+				return nil, ErrCorruptPatch
+			}
+			copy(target[out:], source[sourceRel:sourceRel+length])
+			sourceRel += length
+		case bpsTargetCopy:
+			offset := r.signed()
+			if r.err != nil || offset < -targetRel || offset >= out-targetRel {
+				return nil, ErrCorruptPatch
+			}
+			targetRel += offset
+			// Byte by byte on purpose: the ranges may overlap to repeat a pattern.
+			for i := 0; i < length; i++ {
+				target[out+i] = target[targetRel]
+				targetRel++
+			}
+		}
+		out += length
+	}
+
+	if out != len(target) {
+		return nil, ErrCorruptPatch
+	}
+	if crc32.ChecksumIEEE(target) != targetCRC {
+		return nil, ErrTargetMismatch
+	}
+	return target, nil
+}
+
+// checkFooter verifies the patch's own CRC and returns the source and target CRCs.
+func checkFooter(patch []byte) (sourceCRC, targetCRC uint32, err error) {
+	if len(patch) < 4+checksumFooterSize {
+		return 0, 0, ErrCorruptPatch
+	}
+	footer := patch[len(patch)-checksumFooterSize:]
+	if crc32.ChecksumIEEE(patch[:len(patch)-4]) != binary.LittleEndian.Uint32(footer[8:]) {
+		return 0, 0, ErrPatchMismatch
+	}
+	return binary.LittleEndian.Uint32(footer[0:]), binary.LittleEndian.Uint32(footer[4:]), nil
+}
+
+// varintReader decodes the variable-length integers used by BPS and UPS.
+type varintReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *varintReader) next() uint64 {
+	var value, shift uint64 = 0, 1
+	for {
+		if r.pos >= len(r.data) || shift > 1<<56 {
+			r.err = ErrCorruptPatch
+			return 0
+		}
+		b := r.data[r.pos]
+		r.pos++
+		value += uint64(b&0x7f) * shift
+		if b&0x80 != 0 {
+			return value
+		}
+		shift <<= 7
+		value += shift
+	}
+}
+
+// nextInt decodes a varint used as a length or offset. Values above math.MaxInt32 are
+// corrupt: no patch addresses that much, and int may only be 32 bits wide.
+func (r *varintReader) nextInt() int {
+	v := r.next()
+	if v > math.MaxInt32 {
+		r.err = ErrCorruptPatch
+		return 0
+	}
+	return int(v)
+}
+
+// signed decodes a relative offset whose lowest bit is the sign.
+func (r *varintReader) signed() int {
+	v := r.nextInt()
+	if v&1 != 0 {
+		return -(v >> 1)
+	}
+	return v >> 1
+}
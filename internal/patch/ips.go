@@ -0,0 +1,65 @@
+package patch
+
+const ipsEOF = 0x454F46 // "EOF"
+
+// ApplyIPS applies an IPS patch, including RLE records and the optional truncation
+// length that may follow the EOF marker.
+func ApplyIPS(source, patch []byte) ([]byte, error) {
+	if DetectFormat(patch) != FormatIPS {
+		return nil, ErrUnknownFormat
+	}
+
+	target := append([]byte(nil), source...)
+	pos := 5
+
+	for {
+		if pos+3 > len(patch) {
+			return nil, ErrCorruptPatch
+		}
+		offset := int(patch[pos])<<16 | int(patch[pos+1])<<8 | int(patch[pos+2])
+		pos += 3
+		if offset == ipsEOF {
+			break
+		}
+
+		if pos+2 > len(patch) {
+			return nil, ErrCorruptPatch
+		}
+		size := int(patch[pos])<<8 | int(patch[pos+1])
+		pos += 2
+
+		var data []byte
+		if size == 0 {
+			if pos+3 > len(patch) {
+				return nil, ErrCorruptPatch
+			}
+			size = int(patch[pos])<<8 | int(patch[pos+1])
+			value := patch[pos+2]
+			pos += 3
+			data = make([]byte, size)
+			for i := range data {
+				data[i] = value
+			}
+		} else {
+			if pos+size > len(patch) {
+				return nil, ErrCorruptPatch
+			}
+			data = patch[pos : pos+size]
+			pos += size
+		}
+
+		if end := offset + len(data); end > len(target) {
+			target = append(target, make([]byte, end-len(target))...)
+		}
+		copy(target[offset:], data)
+	}
+
+	if pos+3 <= len(patch) {
+		truncate := int(patch[pos])<<16 | int(patch[pos+1])<<8 | int(patch[pos+2])
+		if truncate < len(target) {
+			target = target[:truncate]
+		}
+	}
+
+	return target, nil
+}
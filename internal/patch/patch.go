@@ -0,0 +1,95 @@
+// Package patch applies IPS, BPS and UPS patches to ROM images so translations and
+// hacks can be played on cores that cannot soft-patch.
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type Format int
+
+const (
+	FormatUnknown Format = iota
+	FormatIPS
+	FormatBPS
+	FormatUPS
+)
+
+var (
+	ErrUnknownFormat  = errors.New("unknown patch format")
+	ErrCorruptPatch   = errors.New("patch is corrupt or truncated")
+	ErrSourceMismatch = errors.New("ROM does not match the checksum the patch expects")
+	ErrTargetMismatch = errors.New("patched ROM does not match the checksum the patch expects")
+	ErrPatchMismatch  = errors.New("patch file failed its own checksum")
+)
+
+var extensions = map[string]Format{
+	".ips": FormatIPS,
+	".bps": FormatBPS,
+	".ups": FormatUPS,
+}
+
+func (f Format) String() string {
+	switch f {
+	case FormatIPS:
+		return "IPS"
+	case FormatBPS:
+		return "BPS"
+	case FormatUPS:
+		return "UPS"
+	default:
+		return "unknown"
+	}
+}
+
+// IsPatchFile reports whether name has the extension of a supported patch format.
+func IsPatchFile(name string) bool {
+	_, ok := extensions[strings.ToLower(filepath.Ext(name))]
+	return ok
+}
+
+// DetectFormat identifies a patch from its magic bytes.
+func DetectFormat(patch []byte) Format {
+	switch {
+	case len(patch) >= 5 && string(patch[:5]) == "PATCH":
+		return FormatIPS
+	case len(patch) >= 4 && string(patch[:4]) == "BPS1":
+		return FormatBPS
+	case len(patch) >= 4 && string(patch[:4]) == "UPS1":
+		return FormatUPS
+	default:
+		return FormatUnknown
+	}
+}
+
+// Apply patches source with whichever format patch is in and returns the result.
+// BPS and UPS checksums are verified; IPS carries none.
+func Apply(source, patch []byte) ([]byte, error) {
+	switch DetectFormat(patch) {
+	case FormatIPS:
+		return ApplyIPS(source, patch)
+	case FormatBPS:
+		return ApplyBPS(source, patch)
+	case FormatUPS:
+		return ApplyUPS(source, patch)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// PatchedFileName names the output of applying patchFileName to romFileName, e.g.
+// "Mother 3 (Japan).gba" + "Mother 3 English v1.3.ups" becomes
+// "Mother 3 (Japan) [Mother 3 English v1.3].gba".
+func PatchedFileName(romFileName, patchFileName string) string {
+	romExt := filepath.Ext(romFileName)
+	romBase := strings.TrimSuffix(romFileName, romExt)
+	patchBase := strings.TrimSuffix(filepath.Base(patchFileName), filepath.Ext(patchFileName))
+	patchBase = strings.NewReplacer("[", "(", "]", ")").Replace(strings.TrimSpace(patchBase))
+	if patchBase == "" {
+		patchBase = "Patched"
+	}
+	return fmt.Sprintf("%s [%s]%s", romBase, patchBase, romExt)
+}
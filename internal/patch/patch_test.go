@@ -0,0 +1,280 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+	"testing"
+)
+
+func encodeVarint(buf *bytes.Buffer, v uint64) {
+	for {
+		x := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			buf.WriteByte(0x80 | x)
+			return
+		}
+		buf.WriteByte(x)
+		v--
+	}
+}
+
+func encodeSigned(buf *bytes.Buffer, v int) {
+	if v < 0 {
+		encodeVarint(buf, uint64(-v)<<1|1)
+		return
+	}
+	encodeVarint(buf, uint64(v)<<1)
+}
+
+func appendFooter(buf *bytes.Buffer, source, target []byte) []byte {
+	_ = binary.Write(buf, binary.LittleEndian, crc32.ChecksumIEEE(source))
+	_ = binary.Write(buf, binary.LittleEndian, crc32.ChecksumIEEE(target))
+	_ = binary.Write(buf, binary.LittleEndian, crc32.ChecksumIEEE(buf.Bytes()))
+	return buf.Bytes()
+}
+
+func TestApplyIPS(t *testing.T) {
+	source := []byte("Hello, world!")
+	var p bytes.Buffer
+	p.WriteString("PATCH")
+	p.Write([]byte{0, 0, 7, 0, 5})
+	p.WriteString("there")
+	// RLE record extending the file.
+	p.Write([]byte{0, 0, 13, 0, 0, 0, 3, '!'})
+	p.WriteString("EOF")
+
+	got, err := ApplyIPS(source, p.Bytes())
+	if err != nil {
+		t.Fatalf("ApplyIPS: %v", err)
+	}
+	if want := "Hello, there!!!!"; string(got) != want {
+		t.Errorf("ApplyIPS() = %q, want %q", got, want)
+	}
+	if string(source) != "Hello, world!" {
+		t.Error("ApplyIPS modified the source")
+	}
+
+	// Truncation after EOF.
+	p.Write([]byte{0, 0, 5})
+	got, err = ApplyIPS(source, p.Bytes())
+	if err != nil {
+		t.Fatalf("ApplyIPS with truncation: %v", err)
+	}
+	if string(got) != "Hello" {
+		t.Errorf("ApplyIPS() with truncation = %q, want %q", got, "Hello")
+	}
+
+	if _, err := ApplyIPS(source, []byte("PATCH\x00\x00\x01\x00\x09ab")); !errors.Is(err, ErrCorruptPatch) {
+		t.Errorf("truncated IPS error = %v, want ErrCorruptPatch", err)
+	}
+}
+
+func buildBPS(source, target []byte) []byte {
+	var p bytes.Buffer
+	p.WriteString("BPS1")
+	encodeVarint(&p, uint64(len(source)))
+	encodeVarint(&p, uint64(len(target)))
+	encodeVarint(&p, 0)
+
+	// "ABCDEFGH" -> "ABCDxyxyxyEFGH"
+	encodeVarint(&p, uint64(4-1)<<2|bpsSourceRead)
+	encodeVarint(&p, uint64(2-1)<<2|bpsTargetRead)
+	p.WriteString("xy")
+	encodeVarint(&p, uint64(4-1)<<2|bpsTargetCopy)
+	encodeSigned(&p, 4)
+	encodeVarint(&p, uint64(4-1)<<2|bpsSourceCopy)
+	encodeSigned(&p, 4)
+
+	return appendFooter(&p, source, target)
+}
+
+func TestApplyBPS(t *testing.T) {
+	source := []byte("ABCDEFGH")
+	target := []byte("ABCDxyxyxyEFGH")
+	p := buildBPS(source, target)
+
+	got, err := Apply(source, p)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !bytes.Equal(got, target) {
+		t.Errorf("Apply() = %q, want %q", got, target)
+	}
+
+	if _, err := Apply([]byte("ABCDEFGX"), p); !errors.Is(err, ErrSourceMismatch) {
+		t.Errorf("wrong source error = %v, want ErrSourceMismatch", err)
+	}
+
+	corrupt := append([]byte(nil), p...)
+	corrupt[len(corrupt)-14] ^= 0xff
+	if _, err := Apply(source, corrupt); !errors.Is(err, ErrPatchMismatch) {
+		t.Errorf("corrupt patch error = %v, want ErrPatchMismatch", err)
+	}
+}
+
+func TestApplyUPS(t *testing.T) {
+	source := []byte("ABCDEFGH")
+	target := []byte("ABzDEFGHIJ")
+
+	var p bytes.Buffer
+	p.WriteString("UPS1")
+	encodeVarint(&p, uint64(len(source)))
+	encodeVarint(&p, uint64(len(target)))
+	encodeVarint(&p, 2)
+	p.Write([]byte{'C' ^ 'z', 0})
+	encodeVarint(&p, 4)
+	p.Write([]byte{'I', 'J', 0})
+	data := appendFooter(&p, source, target)
+
+	got, err := Apply(source, data)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !bytes.Equal(got, target) {
+		t.Errorf("Apply() = %q, want %q", got, target)
+	}
+}
+
+func TestApplyUnknownFormat(t *testing.T) {
+	if _, err := Apply([]byte("rom"), []byte("VCDIFF")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Apply() error = %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestPatchedFileName(t *testing.T) {
+	tests := []struct{ rom, patch, want string }{
+		{"Mother 3 (Japan).gba", "Mother 3 English v1.3.ups", "Mother 3 (Japan) [Mother 3 English v1.3].gba"},
+		{"Game.sfc", "patches/Fix [v2].ips", "Game [Fix (v2)].sfc"},
+	}
+	for _, tt := range tests {
+		if got := PatchedFileName(tt.rom, tt.patch); got != tt.want {
+			t.Errorf("PatchedFileName(%q, %q) = %q, want %q", tt.rom, tt.patch, got, tt.want)
+		}
+	}
+	if !IsPatchFile("Translation.BPS") || IsPatchFile("Game.sfc") {
+		t.Error("IsPatchFile misidentified a file")
+	}
+}
+
+func TestApplyMalformed(t *testing.T) {
+	source := []byte("ABCDEFGH")
+
+	bps := func(targetSize, metadataSize uint64, actions func(p *bytes.Buffer)) []byte {
+		var p bytes.Buffer
+		p.WriteString("BPS1")
+		encodeVarint(&p, uint64(len(source)))
+		encodeVarint(&p, targetSize)
+		encodeVarint(&p, metadataSize)
+		if actions != nil {
+			actions(&p)
+		}
+		return appendFooter(&p, source, nil)
+	}
+	ups := func(targetSize uint64, body func(p *bytes.Buffer)) []byte {
+		var p bytes.Buffer
+		p.WriteString("UPS1")
+		encodeVarint(&p, uint64(len(source)))
+		encodeVarint(&p, targetSize)
+		if body != nil {
+			body(&p)
+		}
+		return appendFooter(&p, source, nil)
+	}
+
+	tests := []struct {
+		name  string
+		patch []byte
+	}{
+		{"bps huge target", bps(1<<40, 0, nil)},
+		{"bps target beyond limit", bps(maxTargetSize+1, 0, nil)},
+		{"bps huge metadata", bps(8, 1<<62, nil)},
+		{"bps metadata past end", bps(8, 100, nil)},
+		{"bps length above int32", bps(8, 0, func(p *bytes.Buffer) {
+			encodeVarint(p, uint64(math.MaxInt32)<<2|bpsTargetRead)
+		})},
+		{"bps length past target", bps(8, 0, func(p *bytes.Buffer) {
+			encodeVarint(p, uint64(9-1)<<2|bpsSourceRead)
+		})},
+		{"bps target read past patch", bps(8, 0, func(p *bytes.Buffer) {
+			encodeVarint(p, uint64(8-1)<<2|bpsTargetRead)
+			p.WriteString("xy")
+		})},
+		{"bps source copy before start", bps(8, 0, func(p *bytes.Buffer) {
+			encodeVarint(p, uint64(1-1)<<2|bpsSourceCopy)
+			encodeSigned(p, -1)
+		})},
+		{"bps source copy huge offset", bps(8, 0, func(p *bytes.Buffer) {
+			encodeVarint(p, uint64(1-1)<<2|bpsSourceCopy)
+			encodeVarint(p, 1<<62)
+		})},
+		{"bps source copy past end", bps(8, 0, func(p *bytes.Buffer) {
+			encodeVarint(p, uint64(4-1)<<2|bpsSourceCopy)
+			encodeSigned(p, 6)
+		})},
+		{"bps target copy of unwritten data", bps(8, 0, func(p *bytes.Buffer) {
+			encodeVarint(p, uint64(2-1)<<2|bpsSourceRead)
+			encodeVarint(p, uint64(1-1)<<2|bpsTargetCopy)
+			encodeSigned(p, 2)
+		})},
+		{"bps target copy before start", bps(8, 0, func(p *bytes.Buffer) {
+			encodeVarint(p, uint64(2-1)<<2|bpsSourceRead)
+			encodeVarint(p, uint64(1-1)<<2|bpsTargetCopy)
+			encodeSigned(p, -1)
+		})},
+		{"ups huge target", ups(1<<40, nil)},
+		{"ups skip above int32", ups(8, func(p *bytes.Buffer) {
+			encodeVarint(p, 1<<40)
+			p.WriteByte(0)
+		})},
+		{"ups skip past both files", ups(8, func(p *bytes.Buffer) {
+			encodeVarint(p, 9)
+			p.WriteByte(0)
+		})},
+		{"ups unterminated run", ups(8, func(p *bytes.Buffer) {
+			encodeVarint(p, 0)
+			p.WriteByte('x')
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Apply(source, tt.patch); !errors.Is(err, ErrCorruptPatch) {
+				t.Errorf("Apply() error = %v, want ErrCorruptPatch", err)
+			}
+		})
+	}
+}
+
+// FuzzApply feeds arbitrary patches to the decoders, which must reject them with an
+// error rather than panic or allocate without bound.
+func FuzzApply(f *testing.F) {
+	source := []byte("ABCDEFGH")
+	f.Add(buildBPS(source, []byte("ABCDxyxyxyEFGH")))
+	f.Add([]byte("PATCH\x00\x00\x07\x00\x02hiEOF"))
+
+	var ups bytes.Buffer
+	ups.WriteString("UPS1")
+	encodeVarint(&ups, uint64(len(source)))
+	encodeVarint(&ups, 10)
+	encodeVarint(&ups, 2)
+	ups.Write([]byte{'C' ^ 'z', 0})
+	f.Add(appendFooter(&ups, source, []byte("ABzDEFGHIJ")))
+
+	f.Fuzz(func(t *testing.T, patch []byte) {
+		// Fix the footer up so the fuzzer reaches the decoders instead of
+		// stopping at the checksums.
+		if len(patch) >= 4+checksumFooterSize && DetectFormat(patch) != FormatIPS {
+			footer := patch[len(patch)-checksumFooterSize:]
+			binary.LittleEndian.PutUint32(footer, crc32.ChecksumIEEE(source))
+			binary.LittleEndian.PutUint32(footer[8:], crc32.ChecksumIEEE(patch[:len(patch)-4]))
+		}
+		target, err := Apply(source, patch)
+		if err == nil && len(target) > maxTargetSize {
+			t.Errorf("Apply() returned %d bytes, above the %d byte limit", len(target), maxTargetSize)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package patch
+
+import "hash/crc32"
+
+// ApplyUPS applies a UPS patch, verifying the source, target and patch checksums.
+func ApplyUPS(source, patch []byte) ([]byte, error) {
+	if DetectFormat(patch) != FormatUPS {
+		return nil, ErrUnknownFormat
+	}
+	sourceCRC, targetCRC, err := checkFooter(patch)
+	if err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(source) != sourceCRC {
+		return nil, ErrSourceMismatch
+	}
+
+	r := &varintReader{data: patch[:len(patch)-checksumFooterSize], pos: 4}
+	sourceSize := r.next()
+	targetSize := r.next()
+	if r.err != nil || sourceSize != uint64(len(source)) {
+		return nil, ErrCorruptPatch
+	}
+
+	size, err := checkTargetSize(targetSize, len(source))
+	if err != nil {
+		return nil, err
+	}
+	target := make([]byte, size)
+	copy(target, source)
+
+	out := 0
+	for r.pos < len(r.data) {
+		// A run never starts past both files, which also keeps out from overflowing.
+		skip := r.nextInt()
+		if r.err != nil || skip > max(len(target), len(source))-out {
+			return nil, ErrCorruptPatch
+		}
+		out += skip
+		// XOR runs end with a zero byte, which also consumes one position.
+		for {
+			if r.pos >= len(r.data) {
+				return nil, ErrCorruptPatch
+			}
+			x := r.data[r.pos]
+			r.pos++
+			if out < len(target) {
+				var s byte
+				if out < len(source) {
+					s = source[out]
+				}
+				target[out] = s ^ x
+			}
+			out++
+			if x == 0 {
+				break
+			}
+		}
+	}
+
+	if crc32.ChecksumIEEE(target) != targetCRC {
+		return nil, ErrTargetMismatch
+	}
+	return target, nil
+}
@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grout/cfw"
+	"grout/romm"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProvisioningFile is the name of the file that sets Grout up on first launch without
+// anyone typing on the device.
+const ProvisioningFile = "grout-provision.json"
+
+// ConsumedSuffix is appended to a provisioning file once it has been applied, so it is
+// never applied twice and its pairing code doesn't linger under its original name.
+const ConsumedSuffix = ".consumed"
+
+// Provisioning is the contents of grout-provision.json.
+type Provisioning struct {
+	// URL of the RomM server, including the scheme and, if needed, the port.
+	URL                string `json:"url"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+
+	// PairingCode is exchanged for an API token. When it is empty, Grout starts
+	// device pairing instead and waits for the request to be approved in RomM.
+	PairingCode string `json:"pairing_code,omitempty"`
+
+	// DeviceName names the device in RomM. It may use {hostname}, {cfw} and {id}
+	// (a short identifier unique to this device).
+	DeviceName string `json:"device_name,omitempty"`
+
+	// RegisterDevice registers the device for save sync after a pairing code login.
+	// Device pairing always registers the device.
+	RegisterDevice bool `json:"register_device,omitempty"`
+
+	// Settings holds config.json keys that are applied over the defaults, such as
+	// directory_mappings, art_kind or save_backup_limit.
+	Settings json.RawMessage `json:"settings,omitempty"`
+
+	path string
+}
+
+// FindProvisioning looks for grout-provision.json in Grout's own directory and then at
+// the root of the SD card, taken to be the parent of the ROM directory.
+func FindProvisioning() (*Provisioning, error) {
+	var candidates []string
+	if cwd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, filepath.Join(cwd, ProvisioningFile))
+	}
+	if romDir := cfw.GetRomDirectory(); romDir != "" {
+		candidates = append(candidates, filepath.Join(filepath.Dir(romDir), ProvisioningFile))
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		return LoadProvisioning(path)
+	}
+	return nil, nil
+}
+
+// LoadProvisioning reads and checks a provisioning file.
+func LoadProvisioning(path string) (*Provisioning, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var p Provisioning
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	p.path = path
+
+	if _, err := p.Host(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(p.Settings) > 0 {
+		var settings map[string]json.RawMessage
+		if err := json.Unmarshal(p.Settings, &settings); err != nil {
+			return nil, fmt.Errorf("%s: settings: %w", path, err)
+		}
+		if _, ok := settings["hosts"]; ok {
+			return nil, fmt.Errorf("%s: settings can't contain hosts, use url and pairing_code", path)
+		}
+	}
+
+	return &p, nil
+}
+
+// Path is where the provisioning file was found.
+func (p *Provisioning) Path() string {
+	return p.path
+}
+
+// Host returns the RomM host described by the file, without credentials.
+func (p *Provisioning) Host() (romm.Host, error) {
+	u, err := url.Parse(strings.TrimSpace(p.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return romm.Host{}, errors.New("url must be a full http:// or https:// address")
+	}
+
+	host := romm.Host{
+		RootURI:            u.Scheme + "://" + u.Hostname(),
+		InsecureSkipVerify: p.InsecureSkipVerify,
+	}
+	if port := u.Port(); port != "" {
+		host.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return romm.Host{}, fmt.Errorf("invalid port %q", port)
+		}
+	}
+	return host, nil
+}
+
+// Apply overlays the file's settings on config. Hosts are left alone; they come from
+// logging in.
+func (p *Provisioning) Apply(config *Config) error {
+	if len(p.Settings) == 0 {
+		return nil
+	}
+	hosts := config.Hosts
+	if err := json.Unmarshal(p.Settings, config); err != nil {
+		return fmt.Errorf("applying provisioning settings: %w", err)
+	}
+	config.Hosts = hosts
+	return nil
+}
+
+// Language returns the language the file sets, if any, so the language picker can be
+// skipped.
+func (p *Provisioning) Language() string {
+	var settings struct {
+		Language string `json:"language"`
+	}
+	_ = json.Unmarshal(p.Settings, &settings)
+	return settings.Language
+}
+
+// ExpandDeviceName fills in the placeholders of the device name template. An empty
+// template falls back to the hostname.
+func (p *Provisioning) ExpandDeviceName(clientDeviceID string) string {
+	hostname, _ := os.Hostname()
+
+	template := p.DeviceName
+	if template == "" {
+		template = "{hostname}"
+	}
+
+	id := clientDeviceID
+	if len(id) > 6 {
+		id = id[:6]
+	}
+
+	name := strings.NewReplacer("{hostname}", hostname, "{id}", id).Replace(template)
+	if strings.Contains(name, "{cfw}") {
+		name = strings.ReplaceAll(name, "{cfw}", string(cfw.GetCFW()))
+	}
+
+	if name = strings.TrimSpace(name); name == "" {
+		return hostname
+	}
+	return name
+}
+
+// MarkConsumed renames the file so it isn't applied again.
+func (p *Provisioning) MarkConsumed() error {
+	if p.path == "" {
+		return nil
+	}
+	return os.Rename(p.path, p.path+ConsumedSuffix)
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeProvisioning(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ProvisioningFile)
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadProvisioning_Host(t *testing.T) {
+	path := writeProvisioning(t, `{"url": "https://romm.club.lan:8443", "insecure_skip_verify": true, "pairing_code": "ABCD"}`)
+
+	p, err := LoadProvisioning(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, err := p.Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host.RootURI != "https://romm.club.lan" || host.Port != 8443 || !host.InsecureSkipVerify {
+		t.Errorf("host = %+v", host)
+	}
+	if host.Token != "" {
+		t.Error("the pairing code must not end up in the host")
+	}
+}
+
+func TestLoadProvisioning_Rejects(t *testing.T) {
+	for name, contents := range map[string]string{
+		"no scheme":      `{"url": "romm.club.lan"}`,
+		"hosts settings": `{"url": "http://romm", "settings": {"hosts": []}}`,
+		"bad json":       `{"url": "http://romm"`,
+	} {
+		if _, err := LoadProvisioning(writeProvisioning(t, contents)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestProvisioningApply_OverlaysSettings(t *testing.T) {
+	path := writeProvisioning(t, `{
+		"url": "http://romm",
+		"settings": {
+			"language": "de",
+			"art_kind": "Box3D",
+			"download_timeout": 5400,
+			"save_backup_limit": 10,
+			"directory_mappings": {"gba": {"slug": "gba", "relative_path": "GBA"}}
+		}
+	}`)
+	p, err := LoadProvisioning(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{ShowRegularCollections: true, ApiTimeout: DurationSeconds(30 * time.Second)}
+	if err := p.Apply(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.ArtKind != "Box3D" || config.SaveBackupLimit != 10 || p.Language() != "de" {
+		t.Errorf("settings not applied: %+v", config)
+	}
+	if config.DownloadTimeout.Duration() != 90*time.Minute {
+		t.Errorf("download_timeout = %v, want 90m", config.DownloadTimeout.Duration())
+	}
+	if config.DirectoryMappings["gba"].RelativePath != "GBA" {
+		t.Errorf("directory mappings = %v", config.DirectoryMappings)
+	}
+	if !config.ShowRegularCollections || config.ApiTimeout.Duration() != 30*time.Second {
+		t.Error("settings missing from the file should keep their values")
+	}
+}
+
+func TestExpandDeviceName(t *testing.T) {
+	hostname, _ := os.Hostname()
+
+	p := Provisioning{DeviceName: "club-{id}"}
+	if got := p.ExpandDeviceName("a1b2c3d4e5f6"); got != "club-a1b2c3" {
+		t.Errorf("got %q, want club-a1b2c3", got)
+	}
+
+	p = Provisioning{}
+	if got := p.ExpandDeviceName("a1b2c3"); got != hostname {
+		t.Errorf("empty template = %q, want hostname %q", got, hostname)
+	}
+}
+
+func TestMarkConsumed(t *testing.T) {
+	path := writeProvisioning(t, `{"url": "http://romm"}`)
+	p, err := LoadProvisioning(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.MarkConsumed(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("provisioning file should be gone")
+	}
+	if _, err := os.Stat(path + ConsumedSuffix); err != nil {
+		t.Error("consumed file missing")
+	}
+	if !strings.HasSuffix(p.Path(), ProvisioningFile) {
+		t.Errorf("path = %q", p.Path())
+	}
+}
@@ -0,0 +1,64 @@
+package retroarch
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config holds the settings from a retroarch.cfg.
+type Config map[string]string
+
+// LoadConfig reads a retroarch.cfg. Lines are `key = "value"`; comments and lines
+// that aren't settings are skipped.
+func LoadConfig(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	config := make(Config)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		config[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return config, scanner.Err()
+}
+
+// Directory returns the directory setting for key, resolved the way RetroArch does:
+// "~" is the home directory and ":" is the directory holding retroarch.cfg. It
+// returns "" when the setting is missing or left at "default".
+func (c Config) Directory(key, configDir string) string {
+	value := c[key]
+	if value == "" || value == "default" {
+		return ""
+	}
+
+	switch {
+	case value == "~" || strings.HasPrefix(value, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(home, strings.TrimPrefix(value, "~"))
+	case strings.HasPrefix(value, ":"):
+		return filepath.Join(configDir, strings.TrimPrefix(value, ":"))
+	default:
+		return filepath.Clean(value)
+	}
+}
+
+// Enabled reports whether a boolean setting is "true".
+func (c Config) Enabled(key string) bool {
+	return c[key] == "true"
+}
@@ -0,0 +1,51 @@
+package retroarch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "retroarch.cfg")
+	cfg := `# RetroArch config
+savefile_directory = "~/saves"
+system_directory = ":/system"
+libretro_directory = "/usr/lib/libretro"
+screenshot_directory = "default"
+sort_savefiles_by_content_enable = "true"
+not a setting
+`
+	if err := os.WriteFile(path, []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"savefile_directory":   filepath.Join(home, "saves"),
+		"system_directory":     filepath.Join(dir, "system"),
+		"libretro_directory":   "/usr/lib/libretro",
+		"screenshot_directory": "",
+		"missing_directory":    "",
+	}
+	for key, want := range tests {
+		if got := config.Directory(key, dir); got != want {
+			t.Errorf("Directory(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	if !config.Enabled("sort_savefiles_by_content_enable") {
+		t.Error("Enabled(sort_savefiles_by_content_enable) = false, want true")
+	}
+	if config.Enabled("sort_savefiles_enable") {
+		t.Error("Enabled(sort_savefiles_enable) = true for a missing setting")
+	}
+}
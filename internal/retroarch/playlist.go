@@ -0,0 +1,181 @@
+// Package retroarch reads RetroArch's config and reads and writes its playlists (.lpl), naming
+// thumbnails the way RetroArch looks them up, so downloaded games show up in its own menus.
+package retroarch
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grout/internal/jsonutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed data/*.json
+var embeddedFiles embed.FS
+
+// Systems maps RomM platform slugs to the libretro database name RetroArch uses for
+// playlist file names, db_name and thumbnail folders.
+var Systems = jsonutil.MustLoadJSONMap[string, string](embeddedFiles, "data/systems.json")
+
+// CoreDetect tells RetroArch to pick the core associated with the playlist when launching.
+const CoreDetect = "DETECT"
+
+// Thumbnail folders inside thumbnails/<system>/.
+const (
+	ThumbnailBoxart = "Named_Boxarts"
+	ThumbnailSnap   = "Named_Snaps"
+	ThumbnailTitle  = "Named_Titles"
+)
+
+// Playlist is the JSON playlist format RetroArch has written since 1.7.6.
+type Playlist struct {
+	Version              string `json:"version"`
+	DefaultCorePath      string `json:"default_core_path"`
+	DefaultCoreName      string `json:"default_core_name"`
+	BaseContentDirectory string `json:"base_content_directory,omitempty"`
+	LabelDisplayMode     int    `json:"label_display_mode"`
+	RightThumbnailMode   int    `json:"right_thumbnail_mode"`
+	LeftThumbnailMode    int    `json:"left_thumbnail_mode"`
+	SortMode             int    `json:"sort_mode"`
+	Items                []Item `json:"items"`
+}
+
+// Item is one game in a playlist.
+type Item struct {
+	Path     string `json:"path"`
+	Label    string `json:"label"`
+	CorePath string `json:"core_path"`
+	CoreName string `json:"core_name"`
+	CRC32    string `json:"crc32"`
+	DBName   string `json:"db_name"`
+}
+
+// SystemName returns the libretro system name for a RomM platform slug.
+func SystemName(platformFSSlug string) (string, bool) {
+	name, ok := Systems[platformFSSlug]
+	return name, ok
+}
+
+// PlaylistFileName returns the playlist file name for a libretro system, which is
+// also the db_name of its entries.
+func PlaylistFileName(system string) string {
+	return system + ".lpl"
+}
+
+// FormatCRC32 formats a hex CRC the way playlist entries store it, or "DETECT" when unknown.
+func FormatCRC32(crc string) string {
+	if crc == "" {
+		return CoreDetect
+	}
+	return strings.ToUpper(crc) + "|crc"
+}
+
+// ThumbnailName returns the file name RetroArch looks for when showing a thumbnail
+// for an entry with the given label.
+func ThumbnailName(label string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune("&*/:`<>?\\|", r) {
+			return '_'
+		}
+		return r
+	}, label) + ".png"
+}
+
+// ThumbnailPath returns where RetroArch looks for a thumbnail of the given kind.
+func ThumbnailPath(thumbnailDir, system, kind, label string) string {
+	return filepath.Join(thumbnailDir, system, kind, ThumbnailName(label))
+}
+
+// Load reads a playlist, returning an empty one when the file doesn't exist yet.
+func Load(path string) (*Playlist, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Playlist{Version: "1.5", Items: []Item{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+
+	var p Playlist
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse playlist %s: %w", path, err)
+	}
+	if p.Items == nil {
+		p.Items = []Item{}
+	}
+	return &p, nil
+}
+
+// Save writes the playlist with the indentation RetroArch uses, replacing the file atomically.
+func (p *Playlist) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create playlist directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write playlist: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write playlist: %w", err)
+	}
+	return nil
+}
+
+// Upsert adds item, or replaces the existing entry with the same path.
+func (p *Playlist) Upsert(item Item) {
+	for i, existing := range p.Items {
+		if existing.Path == item.Path {
+			p.Items[i] = item
+			return
+		}
+	}
+	p.Items = append(p.Items, item)
+}
+
+// Prune removes entries below one of roots whose content no longer exists and returns how
+// many were removed. Entries elsewhere were added by the user or another tool and are left
+// alone. Entries inside archives ("game.zip#game.gba") are checked against the archive.
+func (p *Playlist) Prune(roots []string, exists func(path string) bool) int {
+	kept := p.Items[:0]
+	for _, item := range p.Items {
+		path := contentPath(item.Path)
+		if !underAny(roots, path) || exists(path) {
+			kept = append(kept, item)
+		}
+	}
+	removed := len(p.Items) - len(kept)
+	p.Items = kept
+	return removed
+}
+
+func contentPath(path string) string {
+	lower := strings.ToLower(path)
+	for _, ext := range []string{".zip#", ".7z#"} {
+		if idx := strings.Index(lower, ext); idx >= 0 {
+			return path[:idx+len(ext)-1]
+		}
+	}
+	return path
+}
+
+func underAny(roots []string, path string) bool {
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(path))
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package retroarch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlaylistRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists", PlaylistFileName("Nintendo - Game Boy Advance"))
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load missing playlist: %v", err)
+	}
+	if p.Version != "1.5" || len(p.Items) != 0 {
+		t.Fatalf("new playlist = %+v, want empty v1.5", p)
+	}
+
+	p.Upsert(Item{Path: "/roms/gba/a.gba", Label: "A"})
+	p.Upsert(Item{Path: "/roms/gba/b.gba", Label: "B"})
+	p.Upsert(Item{Path: "/roms/gba/a.gba", Label: "A (Rev 1)", CRC32: FormatCRC32("1a2b3c4d")})
+	if err := p.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(loaded.Items))
+	}
+	if got := loaded.Items[0]; got.Label != "A (Rev 1)" || got.CRC32 != "1A2B3C4D|crc" {
+		t.Errorf("item 0 = %+v, want replaced label and formatted crc", got)
+	}
+}
+
+func TestPlaylistPrune(t *testing.T) {
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "kept.zip")
+	if err := os.WriteFile(kept, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	unmanaged := filepath.Join(t.TempDir(), "elsewhere.gba")
+
+	p := &Playlist{Items: []Item{
+		{Path: kept + "#game.gba"},
+		{Path: filepath.Join(dir, "deleted.gba")},
+		{Path: unmanaged},
+	}}
+	removed := p.Prune([]string{dir}, func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	})
+
+	if removed != 1 || len(p.Items) != 2 || p.Items[0].Path != kept+"#game.gba" || p.Items[1].Path != unmanaged {
+		t.Errorf("Prune removed %d, left %+v; want only the deleted managed entry gone", removed, p.Items)
+	}
+}
+
+func TestThumbnailName(t *testing.T) {
+	if got := ThumbnailName("Zelda: A Link to the Past & More?"); got != "Zelda_ A Link to the Past _ More_.png" {
+		t.Errorf("ThumbnailName = %q", got)
+	}
+	if got := FormatCRC32(""); got != CoreDetect {
+		t.Errorf("FormatCRC32(\"\") = %q, want DETECT", got)
+	}
+}
@@ -0,0 +1,243 @@
+// Package romset collapses the regional and revision variants RomM reports for a
+// game into a single "1G1R" (one game, one ROM) entry.
+package romset
+
+import (
+	"cmp"
+	"fmt"
+	"grout/internal/stringutil"
+	"grout/romm"
+	"slices"
+	"strings"
+)
+
+var (
+	DefaultRegionPriority   = []string{"USA", "World", "Europe", "Japan"}
+	DefaultLanguagePriority = []string{"En"}
+)
+
+// unwantedTags mark dumps that should only be picked when nothing better exists.
+var unwantedTags = []string{"beta", "proto", "prototype", "demo", "sample", "pirate", "hack", "unl", "aftermarket"}
+
+type Preferences struct {
+	Regions   []string
+	Languages []string
+}
+
+// DownloadedFunc reports whether a ROM already exists on the device.
+// Downloaded versions always win so the list never hides what the user has.
+type DownloadedFunc func(game romm.Rom) bool
+
+// Group is a set of variants of the same game with the preferred one first.
+type Group struct {
+	Best     romm.Rom
+	Versions []romm.Rom
+}
+
+// Groups buckets games by sibling relationships and by their tagless file name,
+// then orders every bucket by preference. The result keeps the order in which
+// each group's first member appeared in games.
+func Groups(games []romm.Rom, prefs Preferences, isDownloaded DownloadedFunc) []Group {
+	parent := make([]int, len(games))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		if ra < rb {
+			parent[rb] = ra
+		} else {
+			parent[ra] = rb
+		}
+	}
+
+	byID := make(map[int]int, len(games))
+	byName := make(map[string]int, len(games))
+	for i, g := range games {
+		byID[g.ID] = i
+		if key := groupKey(g); key != "" {
+			if j, ok := byName[key]; ok {
+				union(i, j)
+			} else {
+				byName[key] = i
+			}
+		}
+	}
+
+	for i, g := range games {
+		for _, sibling := range g.Siblings {
+			if j, ok := byID[sibling.ID]; ok {
+				union(i, j)
+			}
+		}
+	}
+
+	members := make(map[int][]romm.Rom)
+	var roots []int
+	for i, g := range games {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], g)
+	}
+
+	groups := make([]Group, 0, len(roots))
+	for _, root := range roots {
+		versions := members[root]
+		SortVersions(versions, prefs, isDownloaded)
+		groups = append(groups, Group{Best: versions[0], Versions: versions})
+	}
+
+	return groups
+}
+
+// Dedupe returns one game per group, preserving the input order.
+func Dedupe(games []romm.Rom, prefs Preferences, isDownloaded DownloadedFunc) []romm.Rom {
+	groups := Groups(games, prefs, isDownloaded)
+	result := make([]romm.Rom, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, g.Best)
+	}
+	return result
+}
+
+// Versions returns every variant of game found in games, preferred first.
+// The game itself is always included even when it has no variants.
+func Versions(games []romm.Rom, game romm.Rom, prefs Preferences, isDownloaded DownloadedFunc) []romm.Rom {
+	for _, g := range Groups(games, prefs, isDownloaded) {
+		if slices.ContainsFunc(g.Versions, func(r romm.Rom) bool { return r.ID == game.ID }) {
+			return g.Versions
+		}
+	}
+	return []romm.Rom{game}
+}
+
+// SortVersions orders variants of a single game from most to least preferred.
+func SortVersions(versions []romm.Rom, prefs Preferences, isDownloaded DownloadedFunc) {
+	regions := prefs.Regions
+	if len(regions) == 0 {
+		regions = DefaultRegionPriority
+	}
+	languages := prefs.Languages
+	if len(languages) == 0 {
+		languages = DefaultLanguagePriority
+	}
+
+	slices.SortStableFunc(versions, func(a, b romm.Rom) int {
+		if isDownloaded != nil {
+			da, db := isDownloaded(a), isDownloaded(b)
+			if da != db {
+				if da {
+					return -1
+				}
+				return 1
+			}
+		}
+		if c := cmp.Compare(unwantedScore(a), unwantedScore(b)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(rank(a.Regions, regions), rank(b.Regions, regions)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(rank(a.Languages, languages), rank(b.Languages, languages)); c != 0 {
+			return c
+		}
+		if c := compareRevision(a.Revision, b.Revision); c != 0 {
+			return -c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// VersionLabel describes what distinguishes a variant, e.g. "USA, Rev 1".
+func VersionLabel(game romm.Rom) string {
+	var parts []string
+	if len(game.Regions) > 0 {
+		parts = append(parts, strings.Join(game.Regions, ", "))
+	}
+	if len(game.Languages) > 0 {
+		parts = append(parts, strings.Join(game.Languages, ", "))
+	}
+	if game.Revision != "" {
+		rev := game.Revision
+		if !strings.HasPrefix(strings.ToLower(rev), "rev") {
+			rev = fmt.Sprintf("Rev %s", rev)
+		}
+		parts = append(parts, rev)
+	}
+	if len(parts) == 0 {
+		if tag := stringutil.ParseTag(game.FsNameNoExt); tag != "" {
+			return tag
+		}
+		return game.FsName
+	}
+	return strings.Join(parts, " · ")
+}
+
+func groupKey(game romm.Rom) string {
+	name := strings.ToLower(strings.TrimSpace(game.FsNameNoTags))
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d/%s", game.PlatformID, name)
+}
+
+// rank returns the position of the best matching value in priority, or
+// len(priority) when none match.
+func rank(values []string, priority []string) int {
+	best := len(priority)
+	for _, v := range values {
+		for i, p := range priority {
+			if i < best && strings.EqualFold(v, p) {
+				best = i
+			}
+		}
+	}
+	return best
+}
+
+func unwantedScore(game romm.Rom) int {
+	var tags []string
+	for _, t := range game.Tags {
+		if s, ok := t.(string); ok {
+			tags = append(tags, s)
+		}
+	}
+	for _, match := range stringutil.TagRegex.FindAllStringSubmatch(game.FsNameNoExt, -1) {
+		tags = append(tags, strings.Split(match[1], ",")...)
+	}
+
+	score := 0
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		for _, unwanted := range unwantedTags {
+			if tag == unwanted || strings.HasPrefix(tag, unwanted+" ") {
+				score++
+			}
+		}
+	}
+	return score
+}
+
+// compareRevision compares revision strings such as "1", "Rev 2" or "A",
+// treating a missing revision as the oldest.
+func compareRevision(a, b string) int {
+	a = strings.TrimSpace(strings.TrimPrefix(strings.ToLower(a), "rev"))
+	b = strings.TrimSpace(strings.TrimPrefix(strings.ToLower(b), "rev"))
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
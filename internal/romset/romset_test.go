@@ -0,0 +1,101 @@
+package romset
+
+import (
+	"testing"
+
+	"grout/romm"
+)
+
+func ids(games []romm.Rom) []int {
+	out := make([]int, len(games))
+	for i, g := range games {
+		out[i] = g.ID
+	}
+	return out
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDedupe(t *testing.T) {
+	games := []romm.Rom{
+		{ID: 1, PlatformID: 1, FsNameNoTags: "Zelda", FsNameNoExt: "Zelda (Japan)", Regions: []string{"Japan"}},
+		{ID: 2, PlatformID: 1, FsNameNoTags: "Zelda", FsNameNoExt: "Zelda (USA)", Regions: []string{"USA"}},
+		{ID: 3, PlatformID: 1, FsNameNoTags: "Zelda", FsNameNoExt: "Zelda (USA) (Rev 1)", Regions: []string{"USA"}, Revision: "1"},
+		{ID: 4, PlatformID: 1, FsNameNoTags: "Mario", FsNameNoExt: "Mario (Europe)", Regions: []string{"Europe"}},
+		{ID: 5, PlatformID: 2, FsNameNoTags: "Zelda", FsNameNoExt: "Zelda (USA)", Regions: []string{"USA"}},
+		{ID: 6, PlatformID: 1, FsNameNoTags: "Mario Beta", FsNameNoExt: "Mario Beta", Siblings: []romm.RomSibling{{ID: 4}}},
+	}
+
+	tests := []struct {
+		name       string
+		prefs      Preferences
+		downloaded map[int]bool
+		want       []int
+	}{
+		{"default priority", Preferences{}, nil, []int{3, 4, 5}},
+		{"japan first", Preferences{Regions: []string{"Japan", "USA"}}, nil, []int{1, 4, 5}},
+		{"downloaded wins", Preferences{}, map[int]bool{2: true}, []int{2, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Dedupe(games, tt.prefs, func(g romm.Rom) bool { return tt.downloaded[g.ID] })
+			if !equalIDs(ids(got), tt.want) {
+				t.Errorf("Dedupe() = %v, want %v", ids(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestSortVersionsPenalizesUnwantedTags(t *testing.T) {
+	versions := []romm.Rom{
+		{ID: 1, FsNameNoExt: "Game (USA) (Beta)", Regions: []string{"USA"}},
+		{ID: 2, FsNameNoExt: "Game (Europe)", Regions: []string{"Europe"}},
+		{ID: 3, FsNameNoExt: "Game (USA)", Regions: []string{"USA"}, Tags: []any{"Proto"}},
+	}
+	SortVersions(versions, Preferences{}, nil)
+	if want := []int{2, 1, 3}; !equalIDs(ids(versions), want) {
+		t.Errorf("SortVersions() = %v, want %v", ids(versions), want)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	games := []romm.Rom{
+		{ID: 1, PlatformID: 1, FsNameNoTags: "A", Regions: []string{"Europe"}},
+		{ID: 2, PlatformID: 1, FsNameNoTags: "A", Regions: []string{"USA"}},
+		{ID: 3, PlatformID: 1, FsNameNoTags: "B"},
+	}
+	if got := Versions(games, games[0], Preferences{}, nil); !equalIDs(ids(got), []int{2, 1}) {
+		t.Errorf("Versions() = %v, want [2 1]", ids(got))
+	}
+	lone := romm.Rom{ID: 9}
+	if got := Versions(games, lone, Preferences{}, nil); !equalIDs(ids(got), []int{9}) {
+		t.Errorf("Versions() for unknown game = %v, want [9]", ids(got))
+	}
+}
+
+func TestVersionLabel(t *testing.T) {
+	tests := []struct {
+		game romm.Rom
+		want string
+	}{
+		{romm.Rom{Regions: []string{"USA"}, Revision: "1"}, "USA · Rev 1"},
+		{romm.Rom{Regions: []string{"USA", "Europe"}, Languages: []string{"En", "Fr"}}, "USA, Europe · En, Fr"},
+		{romm.Rom{Revision: "Rev A"}, "Rev A"},
+		{romm.Rom{FsNameNoExt: "Game (Hack)"}, "Hack"},
+	}
+	for _, tt := range tests {
+		if got := VersionLabel(tt.game); got != tt.want {
+			t.Errorf("VersionLabel(%+v) = %q, want %q", tt.game, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+// Package live listens to the events RomM broadcasts over socket.io while it scans the
+// library, so changed platforms can be refetched as soon as the scan reaches them
+// instead of at the next cache refresh.
+package live
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grout/romm"
+	"net"
+	"slices"
+	"strings"
+	gosync "sync"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	eventScanPlatform = "scan:scanning_platform"
+	eventScanRom      = "scan:scanning_rom"
+	eventScanDone     = "scan:done"
+	eventScanFailed   = "scan:done_ko"
+)
+
+const (
+	// flushDelay is how long changes collect after the last ROM event before they are
+	// reported, so a scan refetches each platform once rather than once per ROM.
+	flushDelay = 5 * time.Second
+
+	dialTimeout = 10 * time.Second
+
+	// minReconnectDelay is the wait after a dropped connection. It doubles with each
+	// failed attempt, up to maxReconnectDelay.
+	minReconnectDelay = 2 * time.Second
+	maxReconnectDelay = 5 * time.Minute
+)
+
+var errServerClosed = errors.New("closed by server")
+
+// ChangeFunc receives the IDs of platforms whose ROMs changed on the server.
+type ChangeFunc func(platformIDs []int)
+
+// Listener keeps a socket.io connection to RomM open, reconnecting with backoff, and
+// reports the platforms touched by library scans.
+type Listener struct {
+	dial     func() (*websocket.Conn, error)
+	onChange ChangeFunc
+	backoff  func(failures int) time.Duration
+	stop     chan struct{}
+	once     gosync.Once
+}
+
+// NewListener creates a listener for host. onChange runs on the listener's goroutine.
+func NewListener(host romm.Host, onChange ChangeFunc) *Listener {
+	return newListener(func() (*websocket.Conn, error) { return dialHost(host) }, onChange)
+}
+
+func newListener(dial func() (*websocket.Conn, error), onChange ChangeFunc) *Listener {
+	return &Listener{
+		dial:     dial,
+		onChange: onChange,
+		backoff:  reconnectDelay,
+		stop:     make(chan struct{}),
+	}
+}
+
+func dialHost(host romm.Host) (*websocket.Conn, error) {
+	location, err := socketURL(host)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := websocket.NewConfig(location, host.URL())
+	if err != nil {
+		return nil, err
+	}
+	if auth := host.AuthHeader(); auth != "" {
+		config.Header.Set("Authorization", auth)
+	}
+	if host.InsecureSkipVerify {
+		config.TlsConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	config.Dialer = &net.Dialer{Timeout: dialTimeout}
+
+	return websocket.DialConfig(config)
+}
+
+// Start connects in the background.
+func (l *Listener) Start() {
+	go l.run()
+}
+
+// Stop closes the connection and stops reconnecting. A stopped listener can't be
+// started again.
+func (l *Listener) Stop() {
+	l.once.Do(func() { close(l.stop) })
+}
+
+func (l *Listener) stopped() bool {
+	select {
+	case <-l.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *Listener) run() {
+	logger := gaba.GetLogger()
+	failures := 0
+
+	for {
+		connected, err := l.session()
+		if l.stopped() {
+			logger.Debug("Live: Listener stopped")
+			return
+		}
+
+		if connected {
+			failures = 0
+		}
+		failures++
+
+		delay := l.backoff(failures)
+		logger.Debug("Live: Disconnected from RomM", "error", err, "retry_in", delay)
+
+		select {
+		case <-l.stop:
+			logger.Debug("Live: Listener stopped")
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// reconnectDelay backs off exponentially from minReconnectDelay, capped at
+// maxReconnectDelay, after consecutive failed connections.
+func reconnectDelay(failures int) time.Duration {
+	delay := minReconnectDelay
+	for i := 1; i < failures && delay < maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxReconnectDelay)
+}
+
+// session runs one connection until it drops or the listener stops. connected reports
+// whether the socket.io handshake succeeded.
+func (l *Listener) session() (connected bool, err error) {
+	logger := gaba.GetLogger()
+
+	conn, err := l.dial()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the reader when the listener stops.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-l.stop:
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	h, err := connect(conn)
+	if err != nil {
+		return false, err
+	}
+	logger.Info("Live: Listening for library changes")
+
+	messages := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			if timeout := h.readTimeout(); timeout > 0 {
+				conn.SetReadDeadline(time.Now().Add(timeout))
+			}
+			var msg string
+			if err := websocket.Message.Receive(conn, &msg); err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	pending := make(map[int]struct{})
+	var quiet <-chan time.Time
+
+	for {
+		select {
+		case <-l.stop:
+			return true, nil
+
+		case err := <-readErr:
+			l.flush(pending)
+			return true, err
+
+		case <-quiet:
+			quiet = nil
+			l.flush(pending)
+
+		case msg := <-messages:
+			switch {
+			case msg == packetPing:
+				if err := websocket.Message.Send(conn, packetPong); err != nil {
+					l.flush(pending)
+					return true, err
+				}
+
+			case msg == packetClose, msg == socketDisconnect:
+				l.flush(pending)
+				return true, errServerClosed
+
+			case strings.HasPrefix(msg, socketEvent):
+				name, data, err := parseEvent(msg[len(socketEvent):])
+				if err != nil {
+					logger.Debug("Live: Ignoring malformed event", "error", err)
+					continue
+				}
+				if l.handleEvent(pending, name, data) {
+					quiet = time.After(flushDelay)
+				}
+			}
+		}
+	}
+}
+
+// connect completes the Engine.IO and socket.io handshakes.
+func connect(conn *websocket.Conn) (handshake, error) {
+	conn.SetReadDeadline(time.Now().Add(dialTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var msg string
+	if err := websocket.Message.Receive(conn, &msg); err != nil {
+		return handshake{}, err
+	}
+	h, err := parseOpen(msg)
+	if err != nil {
+		return handshake{}, err
+	}
+
+	if err := websocket.Message.Send(conn, socketConnect); err != nil {
+		return handshake{}, err
+	}
+
+	for {
+		if err := websocket.Message.Receive(conn, &msg); err != nil {
+			return handshake{}, err
+		}
+		switch {
+		case strings.HasPrefix(msg, socketConnect):
+			return h, nil
+		case strings.HasPrefix(msg, socketConnectError):
+			return handshake{}, fmt.Errorf("connection refused: %s", msg[len(socketConnectError):])
+		case msg == packetPing:
+			if err := websocket.Message.Send(conn, packetPong); err != nil {
+				return handshake{}, err
+			}
+		default:
+			return handshake{}, fmt.Errorf("%w: %q", errUnexpectedPacket, msg)
+		}
+	}
+}
+
+// handleEvent records the platform an event touched and reports whether a delayed
+// flush should be scheduled.
+func (l *Listener) handleEvent(pending map[int]struct{}, name string, data json.RawMessage) bool {
+	switch name {
+	case eventScanPlatform:
+		// The scan moved on, so the platforms before it are done.
+		l.flush(pending)
+		return false
+
+	case eventScanRom:
+		var rom struct {
+			PlatformID int `json:"platform_id"`
+		}
+		if err := json.Unmarshal(data, &rom); err != nil || rom.PlatformID == 0 {
+			gaba.GetLogger().Debug("Live: ROM event without a platform", "error", err)
+			return false
+		}
+		pending[rom.PlatformID] = struct{}{}
+		return true
+
+	case eventScanDone, eventScanFailed:
+		l.flush(pending)
+	}
+	return false
+}
+
+// flush reports the pending platforms and clears them.
+func (l *Listener) flush(pending map[int]struct{}) {
+	if len(pending) == 0 {
+		return
+	}
+
+	ids := make([]int, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+	clear(pending)
+	slices.Sort(ids)
+
+	gaba.GetLogger().Debug("Live: Library changed", "platform_ids", ids)
+	if l.onChange != nil {
+		l.onChange(ids)
+	}
+}
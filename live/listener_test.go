@@ -0,0 +1,167 @@
+package live
+
+import (
+	"grout/romm"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// newSocketStandIn starts a local stand-in for RomM's socket.io server. The nth
+// connection is handed to sessions[n]; connections past the last one stay idle.
+func newSocketStandIn(t *testing.T, sessions ...func(t *testing.T, conn *websocket.Conn)) romm.Host {
+	t.Helper()
+
+	var next atomic.Int32
+	ws := websocket.Handler(func(conn *websocket.Conn) {
+		n := int(next.Add(1)) - 1
+		if n < len(sessions) {
+			sessions[n](t, conn)
+			return
+		}
+		var msg string
+		for websocket.Message.Receive(conn, &msg) == nil {
+		}
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(socketPath, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q, want the host token", got)
+		}
+		ws.ServeHTTP(w, r)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return romm.Host{RootURI: srv.URL, Token: "test-token"}
+}
+
+func serverHandshake(t *testing.T, conn *websocket.Conn) {
+	t.Helper()
+	send(t, conn, `0{"sid":"test","upgrades":[],"pingInterval":25000,"pingTimeout":20000}`)
+	if got := receive(t, conn); got != socketConnect {
+		t.Errorf("client sent %q, want a socket.io connect", got)
+	}
+	send(t, conn, `40{"sid":"socket"}`)
+}
+
+func send(t *testing.T, conn *websocket.Conn, msg string) {
+	t.Helper()
+	if err := websocket.Message.Send(conn, msg); err != nil {
+		t.Errorf("send %q: %v", msg, err)
+	}
+}
+
+func receive(t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+	var msg string
+	if err := websocket.Message.Receive(conn, &msg); err != nil {
+		t.Errorf("receive: %v", err)
+	}
+	return msg
+}
+
+func startListener(t *testing.T, host romm.Host) <-chan []int {
+	t.Helper()
+	changes := make(chan []int, 10)
+	l := NewListener(host, func(ids []int) { changes <- ids })
+	l.backoff = func(int) time.Duration { return 10 * time.Millisecond }
+	l.Start()
+	t.Cleanup(l.Stop)
+	return changes
+}
+
+func expectChange(t *testing.T, changes <-chan []int, want ...int) {
+	t.Helper()
+	select {
+	case got := <-changes:
+		if !slices.Equal(got, want) {
+			t.Errorf("changed platforms = %v, want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no change reported, want %v", want)
+	}
+}
+
+func TestListenerReportsScannedPlatforms(t *testing.T) {
+	host := newSocketStandIn(t, func(t *testing.T, conn *websocket.Conn) {
+		serverHandshake(t, conn)
+
+		send(t, conn, packetPing)
+		if got := receive(t, conn); got != packetPong {
+			t.Errorf("client answered ping with %q", got)
+		}
+
+		send(t, conn, `42["scan:scanning_platform",{"id":3,"name":"Game Boy Advance"}]`)
+		send(t, conn, `42["scan:scanning_rom",{"id":10,"platform_id":3}]`)
+		send(t, conn, `42["scan:scanning_rom",{"id":11,"platform_id":3}]`)
+		send(t, conn, `42["scan:scanning_platform",{"id":5,"name":"Super Nintendo"}]`)
+		send(t, conn, `42["scan:scanning_rom",{"id":12,"platform_id":5}]`)
+		send(t, conn, `42["scan:done",{"scanned_platforms":2}]`)
+
+		var msg string
+		for websocket.Message.Receive(conn, &msg) == nil {
+		}
+	})
+
+	changes := startListener(t, host)
+
+	// Each platform is reported once, when the scan moves past it.
+	expectChange(t, changes, 3)
+	expectChange(t, changes, 5)
+}
+
+func TestListenerReconnects(t *testing.T) {
+	host := newSocketStandIn(t,
+		func(t *testing.T, conn *websocket.Conn) {
+			send(t, conn, `0{"sid":"test","pingInterval":25000,"pingTimeout":20000}`)
+			receive(t, conn)
+			send(t, conn, `44{"message":"not ready"}`)
+		},
+		func(t *testing.T, conn *websocket.Conn) {
+			serverHandshake(t, conn)
+			// Changes seen before a dropped connection are still reported.
+			send(t, conn, `42["scan:scanning_rom",{"id":10,"platform_id":4}]`)
+		},
+		func(t *testing.T, conn *websocket.Conn) {
+			serverHandshake(t, conn)
+			send(t, conn, `42["scan:scanning_rom",{"id":11,"platform_id":6}]`)
+			send(t, conn, `42["scan:done_ko","scan failed"]`)
+
+			var msg string
+			for websocket.Message.Receive(conn, &msg) == nil {
+			}
+		},
+	)
+
+	changes := startListener(t, host)
+
+	expectChange(t, changes, 4)
+	expectChange(t, changes, 6)
+}
+
+func TestReconnectDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{5, 32 * time.Second},
+		{8, 256 * time.Second},
+		{9, 5 * time.Minute},
+		{100, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := reconnectDelay(tt.failures); got != tt.want {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grout/romm"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// socketPath is where RomM mounts its socket.io server.
+const socketPath = "/ws/socket.io/"
+
+// Engine.IO packets, the first byte of every websocket message.
+const (
+	packetOpen  = "0"
+	packetClose = "1"
+	packetPing  = "2"
+	packetPong  = "3"
+)
+
+// Socket.IO packets, carried in Engine.IO message packets ("4").
+const (
+	socketConnect      = "40"
+	socketDisconnect   = "41"
+	socketEvent        = "42"
+	socketConnectError = "44"
+)
+
+var errUnexpectedPacket = errors.New("unexpected socket.io packet")
+
+// handshake is the payload of the Engine.IO open packet.
+type handshake struct {
+	SID          string `json:"sid"`
+	PingInterval int    `json:"pingInterval"` // milliseconds
+	PingTimeout  int    `json:"pingTimeout"`  // milliseconds
+}
+
+// readTimeout is how long to wait for the next message before the connection is
+// considered dead: the server pings every interval and allows timeout for the pong.
+func (h handshake) readTimeout() time.Duration {
+	return time.Duration(h.PingInterval+h.PingTimeout) * time.Millisecond
+}
+
+// socketURL returns the websocket endpoint of the RomM socket.io server for host.
+func socketURL(host romm.Host) (string, error) {
+	u, err := url.Parse(host.URL())
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + socketPath
+	u.RawQuery = url.Values{"EIO": {"4"}, "transport": {"websocket"}}.Encode()
+	return u.String(), nil
+}
+
+// parseOpen reads the handshake from an Engine.IO open packet.
+func parseOpen(msg string) (handshake, error) {
+	if !strings.HasPrefix(msg, packetOpen) {
+		return handshake{}, fmt.Errorf("%w: expected open, got %q", errUnexpectedPacket, msg)
+	}
+
+	var h handshake
+	if err := json.Unmarshal([]byte(msg[len(packetOpen):]), &h); err != nil {
+		return handshake{}, fmt.Errorf("invalid open packet: %w", err)
+	}
+	return h, nil
+}
+
+// parseEvent returns the name and first argument of a socket.io event packet, the
+// part of a message after socketEvent. A namespace or an ack ID before the array is skipped.
+func parseEvent(payload string) (string, json.RawMessage, error) {
+	if strings.HasPrefix(payload, "/") {
+		comma := strings.IndexByte(payload, ',')
+		if comma < 0 {
+			return "", nil, fmt.Errorf("%w: namespace without payload", errUnexpectedPacket)
+		}
+		payload = payload[comma+1:]
+	}
+	payload = strings.TrimLeft(payload, "0123456789")
+
+	var args []json.RawMessage
+	if err := json.Unmarshal([]byte(payload), &args); err != nil {
+		return "", nil, fmt.Errorf("invalid event: %w", err)
+	}
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%w: event without a name", errUnexpectedPacket)
+	}
+
+	var name string
+	if err := json.Unmarshal(args[0], &name); err != nil {
+		return "", nil, fmt.Errorf("invalid event name: %w", err)
+	}
+
+	var data json.RawMessage
+	if len(args) > 1 {
+		data = args[1]
+	}
+	return name, data, nil
+}
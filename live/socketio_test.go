@@ -0,0 +1,81 @@
+package live
+
+import (
+	"grout/romm"
+	"testing"
+	"time"
+)
+
+func TestSocketURL(t *testing.T) {
+	tests := []struct {
+		name string
+		host romm.Host
+		want string
+	}{
+		{"http", romm.Host{RootURI: "http://romm.local"}, "ws://romm.local/ws/socket.io/?EIO=4&transport=websocket"},
+		{"https with port", romm.Host{RootURI: "https://romm.example.com", Port: 8443}, "wss://romm.example.com:8443/ws/socket.io/?EIO=4&transport=websocket"},
+		{"sub path", romm.Host{RootURI: "http://nas.local/romm/"}, "ws://nas.local/romm/ws/socket.io/?EIO=4&transport=websocket"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := socketURL(tt.host)
+			if err != nil {
+				t.Fatalf("socketURL: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("socketURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := socketURL(romm.Host{RootURI: "ftp://romm.local"}); err == nil {
+		t.Error("socketURL accepted an ftp host")
+	}
+}
+
+func TestParseOpen(t *testing.T) {
+	h, err := parseOpen(`0{"sid":"abc","upgrades":[],"pingInterval":25000,"pingTimeout":20000,"maxPayload":1000000}`)
+	if err != nil {
+		t.Fatalf("parseOpen: %v", err)
+	}
+	if h.SID != "abc" || h.readTimeout() != 45*time.Second {
+		t.Errorf("handshake = %+v, want sid abc and a 45s read timeout", h)
+	}
+
+	if _, err := parseOpen(`40`); err == nil {
+		t.Error("parseOpen accepted a connect packet")
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		wantName string
+		wantData string
+	}{
+		{"event with data", `["scan:scanning_rom",{"id":7,"platform_id":3}]`, "scan:scanning_rom", `{"id":7,"platform_id":3}`},
+		{"event without data", `["scan:done"]`, "scan:done", ""},
+		{"namespace", `/ws,["scan:done",{}]`, "scan:done", `{}`},
+		{"ack id", `12["scan:done",{}]`, "scan:done", `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, data, err := parseEvent(tt.payload)
+			if err != nil {
+				t.Fatalf("parseEvent: %v", err)
+			}
+			if name != tt.wantName || string(data) != tt.wantData {
+				t.Errorf("parseEvent = %q, %s; want %q, %s", name, data, tt.wantName, tt.wantData)
+			}
+		})
+	}
+
+	for _, bad := range []string{`[]`, `not json`, `[42]`, `/ws`} {
+		if _, _, err := parseEvent(bad); err == nil {
+			t.Errorf("parseEvent(%q) succeeded", bad)
+		}
+	}
+}
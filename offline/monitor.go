@@ -0,0 +1,90 @@
+package offline
+
+import (
+	"grout/internal"
+	"grout/romm"
+	gosync "sync"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+const (
+	iconOffline = "\U000F0164"
+
+	// HeartbeatInterval is how often RomM is polled while offline.
+	HeartbeatInterval = 30 * time.Second
+)
+
+// Monitor shows the offline icon in the status bar and polls the RomM heartbeat until
+// it answers, then switches the session back online.
+type Monitor struct {
+	host     romm.Host
+	icon     *gaba.DynamicStatusBarIcon
+	onOnline func(romm.HeartbeatResponse)
+	stop     chan struct{}
+	once     gosync.Once
+}
+
+// NewMonitor creates a monitor for host. onOnline runs on the monitor's goroutine once
+// the heartbeat succeeds.
+func NewMonitor(host romm.Host, onOnline func(romm.HeartbeatResponse)) *Monitor {
+	text := ""
+	if IsOffline() {
+		text = iconOffline
+	}
+	return &Monitor{
+		host:     host,
+		icon:     gaba.NewDynamicStatusBarIcon(text),
+		onOnline: onOnline,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (m *Monitor) Icon() gaba.StatusBarIcon {
+	return gaba.StatusBarIcon{
+		Dynamic: m.icon,
+	}
+}
+
+// Start polls in the background while the session is offline. It does nothing when
+// already online.
+func (m *Monitor) Start() {
+	if !IsOffline() {
+		return
+	}
+	go m.run()
+}
+
+func (m *Monitor) Stop() {
+	m.once.Do(func() { close(m.stop) })
+}
+
+func (m *Monitor) run() {
+	logger := gaba.GetLogger()
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+
+		client := romm.NewClientFromHost(m.host, internal.ValidationTimeout)
+		heartbeat, err := client.GetHeartbeat()
+		if err != nil {
+			logger.Debug("Offline: RomM still unreachable", "error", err)
+			continue
+		}
+
+		logger.Info("Offline: RomM is reachable again, going online")
+		SetOffline(false)
+		m.icon.SetText("")
+		if m.onOnline != nil {
+			m.onOnline(heartbeat)
+		}
+		return
+	}
+}
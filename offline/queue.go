@@ -0,0 +1,123 @@
+// Package offline lets Grout run from the cache when RomM can't be reached. It tracks
+// whether the session is offline, keeps the actions that need the server until it is
+// reachable again, and watches the heartbeat to notice when it is.
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"os"
+	gosync "sync"
+	"sync/atomic"
+	"time"
+)
+
+// QueueFile holds the queued actions so they survive a restart while still offline.
+const QueueFile = "offline_queue.json"
+
+type ActionKind string
+
+const (
+	ActionDownload ActionKind = "download"
+	ActionSaveSync ActionKind = "save_sync"
+)
+
+// Action is something the user asked for while offline.
+type Action struct {
+	Kind     ActionKind `json:"kind"`
+	QueuedAt time.Time  `json:"queued_at"`
+
+	// Download fields, mirroring what the download screen is given.
+	Platform   romm.Platform  `json:"platform,omitempty"`
+	Games      []romm.Rom     `json:"games,omitempty"`
+	FileID     int            `json:"file_id,omitempty"`
+	PatchID    int            `json:"patch_id,omitempty"`
+	Extras     []romm.RomFile `json:"extras,omitempty"`
+	ExtrasOnly bool           `json:"extras_only,omitempty"`
+}
+
+var (
+	offline atomic.Bool
+	queueMu gosync.Mutex
+)
+
+// IsOffline reports whether the session is running from the cache.
+func IsOffline() bool {
+	return offline.Load()
+}
+
+func SetOffline(enabled bool) {
+	offline.Store(enabled)
+}
+
+// Enqueue adds an action to the queue. A save sync covers every game, so only one is
+// ever queued.
+func Enqueue(action Action) error {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+	return enqueue(QueueFile, action)
+}
+
+// Pending returns the queued actions in the order they were added.
+func Pending() []Action {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+	actions, _ := load(QueueFile)
+	return actions
+}
+
+// Clear empties the queue.
+func Clear() error {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+	if err := os.Remove(QueueFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func enqueue(path string, action Action) error {
+	actions, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	if action.QueuedAt.IsZero() {
+		action.QueuedAt = time.Now().UTC()
+	}
+	if action.Kind == ActionSaveSync {
+		for _, queued := range actions {
+			if queued.Kind == ActionSaveSync {
+				return nil
+			}
+		}
+	}
+
+	return save(path, append(actions, action))
+}
+
+func load(path string) ([]Action, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []Action
+	if err := json.Unmarshal(data, &actions); err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+func save(path string, actions []Action) error {
+	data, err := json.MarshalIndent(actions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomic(path, data, 0644)
+}
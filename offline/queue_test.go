@@ -0,0 +1,69 @@
+package offline
+
+import (
+	"grout/romm"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnqueuePersistsInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), QueueFile)
+
+	first := Action{Kind: ActionDownload, Platform: romm.Platform{ID: 1}, Games: []romm.Rom{{ID: 10}}, FileID: 3}
+	second := Action{Kind: ActionDownload, Platform: romm.Platform{ID: 2}, Games: []romm.Rom{{ID: 20}, {ID: 21}}}
+
+	for _, action := range []Action{first, second} {
+		if err := enqueue(path, action); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+
+	actions, err := load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("got %d actions, want 2", len(actions))
+	}
+	if actions[0].Games[0].ID != 10 || actions[0].FileID != 3 {
+		t.Errorf("first action = %+v", actions[0])
+	}
+	if len(actions[1].Games) != 2 || actions[1].Platform.ID != 2 {
+		t.Errorf("second action = %+v", actions[1])
+	}
+	if actions[0].QueuedAt.IsZero() {
+		t.Error("QueuedAt was not set")
+	}
+}
+
+func TestEnqueueKeepsOneSaveSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), QueueFile)
+
+	for _, action := range []Action{
+		{Kind: ActionSaveSync},
+		{Kind: ActionDownload, Games: []romm.Rom{{ID: 1}}},
+		{Kind: ActionSaveSync},
+	} {
+		if err := enqueue(path, action); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+
+	actions, err := load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("got %d actions, want 2: %+v", len(actions), actions)
+	}
+	if actions[0].Kind != ActionSaveSync || actions[1].Kind != ActionDownload {
+		t.Errorf("unexpected order: %+v", actions)
+	}
+}
+
+func TestLoadMissingQueue(t *testing.T) {
+	actions, err := load(filepath.Join(t.TempDir(), QueueFile))
+	if err != nil || len(actions) != 0 {
+		t.Fatalf("load missing = %v, %v; want empty, nil", actions, err)
+	}
+}
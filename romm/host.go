@@ -34,14 +34,22 @@ func (h Host) HasTokenAuth() bool {
 	return h.Token != ""
 }
 
+// ToLoggable returns every field of the host with the token masked, safe to log or
+// share in a bug report.
 func (h Host) ToLoggable() map[string]any {
 	temp := map[string]any{
-		"display_name":         h.DisplayName,
-		"root_uri":             h.RootURI,
-		"port":                 h.Port,
-		"username":             h.Username,
-		"token":                strings.Repeat("*", len(h.Token)),
-		"insecure_skip_verify": h.InsecureSkipVerify,
+		"display_name":             h.DisplayName,
+		"root_uri":                 h.RootURI,
+		"port":                     h.Port,
+		"username":                 h.Username,
+		"token":                    strings.Repeat("*", len(h.Token)),
+		"token_name":               h.TokenName,
+		"token_expires_at":         h.TokenExpiresAt,
+		"insecure_skip_verify":     h.InsecureSkipVerify,
+		"client_device_identifier": h.ClientDeviceID,
+		"device_id":                h.DeviceID,
+		"device_name":              h.DeviceName,
+		"device_client_version":    h.DeviceClientVersion,
 	}
 
 	return temp
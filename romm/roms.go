@@ -79,11 +79,21 @@ type Rom struct {
 	CreatedAt             time.Time      `json:"created_at,omitempty"`
 	UpdatedAt             time.Time      `json:"updated_at,omitempty"`
 	MissingFromFs         bool           `json:"missing_from_fs,omitempty"`
-	Siblings              []any          `json:"siblings,omitempty"`
+	Siblings              []RomSibling   `json:"siblings,omitempty"`
 	PathVideo             string         `json:"path_video,omitempty"`
 	ScreenScraperMetadata ScreenScrapper `json:"ss_metadata,omitempty"`
 }
 
+// RomSibling is another dump of the same game on the same platform (a different
+// region, revision or language), as reported by RomM.
+type RomSibling struct {
+	ID             int    `json:"id,omitempty"`
+	Name           string `json:"name,omitempty"`
+	FsNameNoTags   string `json:"fs_name_no_tags,omitempty"`
+	FsNameNoExt    string `json:"fs_name_no_ext,omitempty"`
+	SortComparator string `json:"sort_comparator,omitempty"`
+}
+
 type Screenshot struct {
 	ID       int    `json:"id,omitempty"`
 	RomID    int    `json:"rom_id,omitempty"`
@@ -106,20 +116,63 @@ type RomMetadata struct {
 }
 
 type RomFile struct {
-	ID            int       `json:"id,omitempty"`
-	RomID         int       `json:"rom_id,omitempty"`
-	FileName      string    `json:"file_name,omitempty"`
-	FilePath      string    `json:"file_path,omitempty"`
-	FileSizeBytes int64     `json:"file_size_bytes,omitempty"`
-	FullPath      string    `json:"full_path,omitempty"`
-	CreatedAt     time.Time `json:"created_at,omitempty"`
-	UpdatedAt     time.Time `json:"updated_at,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	CrcHash       string    `json:"crc_hash,omitempty"`
-	Md5Hash       string    `json:"md5_hash,omitempty"`
-	Sha1Hash      string    `json:"sha1_hash,omitempty"`
-	RAHash        string    `json:"ra_hash,omitempty"`
-	Category      any       `json:"category,omitempty"`
+	ID            int          `json:"id,omitempty"`
+	RomID         int          `json:"rom_id,omitempty"`
+	FileName      string       `json:"file_name,omitempty"`
+	FilePath      string       `json:"file_path,omitempty"`
+	FileSizeBytes int64        `json:"file_size_bytes,omitempty"`
+	FullPath      string       `json:"full_path,omitempty"`
+	CreatedAt     time.Time    `json:"created_at,omitempty"`
+	UpdatedAt     time.Time    `json:"updated_at,omitempty"`
+	LastModified  time.Time    `json:"last_modified,omitempty"`
+	CrcHash       string       `json:"crc_hash,omitempty"`
+	Md5Hash       string       `json:"md5_hash,omitempty"`
+	Sha1Hash      string       `json:"sha1_hash,omitempty"`
+	RAHash        string       `json:"ra_hash,omitempty"`
+	Category      FileCategory `json:"category,omitempty"`
+}
+
+// FileCategory is the role RomM assigns to a file that belongs to a ROM.
+type FileCategory string
+
+const (
+	FileCategoryGame        FileCategory = "game"
+	FileCategoryDLC         FileCategory = "dlc"
+	FileCategoryUpdate      FileCategory = "update"
+	FileCategoryHack        FileCategory = "hack"
+	FileCategoryTranslation FileCategory = "translation"
+	FileCategoryManual      FileCategory = "manual"
+	FileCategoryPatch       FileCategory = "patch"
+	FileCategoryDemo        FileCategory = "demo"
+	FileCategoryCheat       FileCategory = "cheat"
+)
+
+// FileCategories lists every category in the order they are shown to the user.
+var FileCategories = []FileCategory{
+	FileCategoryGame,
+	FileCategoryUpdate,
+	FileCategoryDLC,
+	FileCategoryTranslation,
+	FileCategoryHack,
+	FileCategoryPatch,
+	FileCategoryDemo,
+	FileCategoryCheat,
+	FileCategoryManual,
+}
+
+var patchExtensions = []string{".ips", ".bps", ".ups", ".xdelta", ".ppf"}
+
+// GetCategory returns the file's category. Files RomM left uncategorized are
+// treated as game files, except for patches which are recognized by extension.
+func (f RomFile) GetCategory() FileCategory {
+	category := FileCategory(strings.ToLower(string(f.Category)))
+	if slices.Contains(FileCategories, category) {
+		return category
+	}
+	if slices.Contains(patchExtensions, strings.ToLower(filepath.Ext(f.FileName))) {
+		return FileCategoryPatch
+	}
+	return FileCategoryGame
 }
 
 type GetRomsQuery struct {
@@ -212,6 +265,32 @@ func resolveAssetURL(host Host, path, fallbackURL string) string {
 	return ""
 }
 
+// GameFiles returns the files that make up the game itself.
+func (r *Rom) GameFiles() []RomFile {
+	var files []RomFile
+	for _, f := range r.Files {
+		if f.GetCategory() == FileCategoryGame {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// ExtraFiles returns the DLC, updates, patches, manuals and other files that ship
+// alongside the game, ordered by category.
+func (r *Rom) ExtraFiles() []RomFile {
+	var files []RomFile
+	for _, f := range r.Files {
+		if f.GetCategory() != FileCategoryGame {
+			files = append(files, f)
+		}
+	}
+	slices.SortStableFunc(files, func(a, b RomFile) int {
+		return slices.Index(FileCategories, a.GetCategory()) - slices.Index(FileCategories, b.GetCategory())
+	})
+	return files
+}
+
 func (r *Rom) GetGamePage(host Host) string {
 	u, _ := url.JoinPath(host.URL(), "rom", strconv.Itoa(r.ID))
 	return u
@@ -276,7 +355,7 @@ func (r *Rom) GetLocalPath(resolver PlatformDirResolver) string {
 	romDirectory := resolver.GetPlatformRomDirectory(platform)
 
 	if r.HasMultipleFiles {
-		return filepath.Join(romDirectory, r.FsNameNoExt+".m3u")
+		return r.playlistPath(romDirectory)
 	} else if len(r.Files) > 0 {
 		return filepath.Join(romDirectory, r.Files[0].FileName)
 	}
@@ -284,6 +363,17 @@ func (r *Rom) GetLocalPath(resolver PlatformDirResolver) string {
 	return ""
 }
 
+// playlistPath returns the m3u that launches a multi-disc ROM. Most CFWs keep it next to
+// the other ROMs, while NextUI and MinUI expect it inside a folder named after the game.
+func (r *Rom) playlistPath(romDirectory string) string {
+	flat := filepath.Join(romDirectory, r.FsNameNoExt+".m3u")
+	nested := filepath.Join(romDirectory, r.FsNameNoExt, r.FsNameNoExt+".m3u")
+	if !fileutil.FileExists(flat) && fileutil.FileExists(nested) {
+		return nested
+	}
+	return flat
+}
+
 func (r *Rom) IsDownloaded(resolver PlatformDirResolver) bool {
 	if r.PlatformFSSlug == "" {
 		return false
@@ -298,8 +388,7 @@ func (r *Rom) IsDownloaded(resolver PlatformDirResolver) bool {
 
 	// For multi-disk games, check the m3u file
 	if r.HasMultipleFiles {
-		m3uPath := filepath.Join(romDirectory, r.FsNameNoExt+".m3u")
-		return fileutil.FileExists(m3uPath)
+		return fileutil.FileExists(r.playlistPath(romDirectory))
 	}
 
 	// Check if any of the associated files exist
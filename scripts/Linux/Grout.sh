@@ -0,0 +1,27 @@
+#!/bin/bash
+CUR_DIR="$(cd "$(dirname "$0")" && pwd)"
+FLAG_FILE="./es_restart_request"
+cd "$CUR_DIR/Grout" || exit 1
+
+# Apply pending update
+if [ -d "../.update" ]; then
+    cp -rf ../.update/* ..
+    rm -rf ../.update
+fi
+
+export CFW=LINUX
+export LD_LIBRARY_PATH="$CUR_DIR/Grout/lib:$LD_LIBRARY_PATH"
+# Uncomment to keep games, BIOS files or saves somewhere other than the ES-DE and RetroArch defaults
+#export GROUT_ROM_DIRECTORY="$HOME/ROMs"
+#export GROUT_BIOS_DIRECTORY="$HOME/.config/retroarch/system"
+#export GROUT_SAVE_DIRECTORY="$HOME/.config/retroarch/saves"
+chmod +x ./grout
+
+./grout
+
+if [ -f "$FLAG_FILE" ]; then
+    rm -f "$FLAG_FILE"
+    echo "Restart ES-DE to see newly downloaded games."
+fi
+
+exit 0
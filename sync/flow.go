@@ -47,10 +47,12 @@ func ResolveSaveSync(client *romm.Client, config *internal.Config, deviceID stri
 			"emulator", s.Emulator, "hasHash", s.ContentHash != "", "size", s.FileSizeBytes)
 	}
 
-	resp, err := client.Negotiate(romm.SyncNegotiatePayload{
+	payload := romm.SyncNegotiatePayload{
 		DeviceID: deviceID,
 		Saves:    states,
-	})
+	}
+	resp, err := client.Negotiate(payload)
+	recordNegotiate(payload, resp, err)
 	if err != nil {
 		return SyncResult{}, fmt.Errorf("negotiate failed: %w", err)
 	}
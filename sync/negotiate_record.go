@@ -0,0 +1,39 @@
+package sync
+
+import (
+	"encoding/json"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// LastNegotiateFile keeps the most recent negotiate exchange so a diagnostics export
+// can include it, even from a later session.
+const LastNegotiateFile = "last_negotiate.json"
+
+// NegotiateExchange is one negotiate request and what the server answered.
+type NegotiateExchange struct {
+	At       time.Time                   `json:"at"`
+	Request  romm.SyncNegotiatePayload   `json:"request"`
+	Response *romm.SyncNegotiateResponse `json:"response,omitempty"`
+	Error    string                      `json:"error,omitempty"`
+}
+
+func recordNegotiate(request romm.SyncNegotiatePayload, response romm.SyncNegotiateResponse, err error) {
+	exchange := NegotiateExchange{At: time.Now().UTC(), Request: request}
+	if err != nil {
+		exchange.Error = err.Error()
+	} else {
+		exchange.Response = &response
+	}
+
+	data, marshalErr := json.MarshalIndent(exchange, "", "  ")
+	if marshalErr != nil {
+		return
+	}
+	if writeErr := fileutil.WriteFileAtomic(LastNegotiateFile, data, 0644); writeErr != nil {
+		gaba.GetLogger().Debug("Failed to record negotiate exchange", "error", writeErr)
+	}
+}
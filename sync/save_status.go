@@ -0,0 +1,73 @@
+package sync
+
+import (
+	"grout/cache"
+	"grout/cfw"
+	"grout/internal"
+	"grout/romm"
+)
+
+// SavesNewerOnServer counts the save slots of downloaded games where RomM has a newer
+// save than the one this device last synced. Only slots this device has synced before
+// are compared; saves it has never had are left for the next sync to offer.
+func SavesNewerOnServer(client *romm.Client, config *internal.Config, deviceID string) (int, error) {
+	cm := cache.GetCacheManager()
+	if cm == nil || deviceID == "" {
+		return 0, nil
+	}
+
+	synced := syncedSlotsByRom(cm.GetSaveStates(deviceID))
+	if len(synced) == 0 {
+		return 0, nil
+	}
+
+	downloaded := ResolveLocalRoms(cfw.ScanRoms(config))
+
+	newer := 0
+	for romID, slots := range synced {
+		if _, ok := downloaded[romID]; !ok {
+			continue
+		}
+		summary, err := client.GetSaveSummary(romID)
+		if err != nil {
+			return 0, err
+		}
+		newer += countNewerSlots(summary, slots)
+	}
+	return newer, nil
+}
+
+// syncedSlotsByRom maps each ROM to the save ID last synced in each of its slots.
+func syncedSlotsByRom(states []cache.SaveSyncState) map[int]map[string]int {
+	synced := make(map[int]map[string]int)
+	for _, state := range states {
+		if state.SaveID == 0 {
+			continue
+		}
+		slot := state.Slot
+		if slot == "" {
+			slot = "autosave"
+		}
+		if synced[state.RomID] == nil {
+			synced[state.RomID] = make(map[string]int)
+		}
+		synced[state.RomID][slot] = max(synced[state.RomID][slot], state.SaveID)
+	}
+	return synced
+}
+
+// countNewerSlots compares by save ID rather than timestamp: IDs only grow, while the
+// clocks of handhelds are often wrong.
+func countNewerSlots(summary romm.SaveSummary, synced map[string]int) int {
+	newer := 0
+	for _, info := range summary.Slots {
+		slot := "autosave"
+		if info.Slot != nil && *info.Slot != "" {
+			slot = *info.Slot
+		}
+		if savedID, ok := synced[slot]; ok && info.Latest.ID > savedID {
+			newer++
+		}
+	}
+	return newer
+}
@@ -0,0 +1,42 @@
+package sync
+
+import (
+	"grout/cache"
+	"grout/romm"
+	"testing"
+)
+
+func TestSyncedSlotsByRom(t *testing.T) {
+	synced := syncedSlotsByRom([]cache.SaveSyncState{
+		{RomID: 1, FileName: "a.srm", Slot: "", SaveID: 10},
+		{RomID: 1, FileName: "a.state", Slot: "autosave", SaveID: 12},
+		{RomID: 1, FileName: "b.srm", Slot: "quest", SaveID: 7},
+		{RomID: 2, FileName: "c.srm", Slot: "autosave", SaveID: 0},
+	})
+
+	if got := synced[1]["autosave"]; got != 12 {
+		t.Errorf("rom 1 autosave = %d, want 12 (empty slot counts as autosave, highest ID wins)", got)
+	}
+	if got := synced[1]["quest"]; got != 7 {
+		t.Errorf("rom 1 quest = %d, want 7", got)
+	}
+	if _, ok := synced[2]; ok {
+		t.Error("rom 2 has no synced save ID and should be skipped")
+	}
+}
+
+func TestCountNewerSlots(t *testing.T) {
+	summary := romm.SaveSummary{
+		Slots: []romm.SaveSlotInfo{
+			{Slot: nil, Latest: romm.Save{ID: 15}},
+			{Slot: ptrStr("quest"), Latest: romm.Save{ID: 7}},
+			{Slot: ptrStr("boss"), Latest: romm.Save{ID: 30}},
+		},
+	}
+	synced := map[string]int{"autosave": 12, "quest": 7}
+
+	// autosave moved on from 12 to 15; quest is unchanged; boss was never synced here.
+	if got := countNewerSlots(summary, synced); got != 1 {
+		t.Errorf("countNewerSlots = %d, want 1", got)
+	}
+}
@@ -8,6 +8,7 @@ type PlatformSelectionAction int
 const (
 	PlatformSelectionActionSelected PlatformSelectionAction = iota
 	PlatformSelectionActionCollections
+	PlatformSelectionActionPlayHistory
 	PlatformSelectionActionSettings
 	PlatformSelectionActionSaveSync
 	PlatformSelectionActionQuit
@@ -83,9 +84,25 @@ type GeneralSettingsAction int
 
 const (
 	GeneralSettingsActionSaved GeneralSettingsAction = iota
+	GeneralSettingsActionRegionPriority
+	GeneralSettingsActionLanguagePriority
 	GeneralSettingsActionBack
 )
 
+type GameFilesAction int
+
+const (
+	GameFilesActionDownload GameFilesAction = iota
+	GameFilesActionBack
+)
+
+type RomPriorityAction int
+
+const (
+	RomPriorityActionSaved RomPriorityAction = iota
+	RomPriorityActionBack
+)
+
 type CollectionsSettingsAction int
 
 const (
@@ -102,6 +119,7 @@ const (
 	AdvancedSettingsActionServerAddress
 	AdvancedSettingsActionInputMapping
 	AdvancedSettingsActionResetInputMapping
+	AdvancedSettingsActionExportDiagnostics
 	AdvancedSettingsActionBack
 )
 
@@ -110,6 +128,9 @@ type ToolsSettingsAction int
 const (
 	ToolsSettingsActionSaved ToolsSettingsAction = iota
 	ToolsSettingsActionSyncLocalArtwork
+	ToolsSettingsActionOptimizeArt
+	ToolsSettingsActionCleanGamelists
+	ToolsSettingsActionFetchAllBIOS
 	ToolsSettingsActionBack
 )
 
@@ -95,6 +95,11 @@ func (s *AdvancedSettingsScreen) Draw(input AdvancedSettingsInput) (AdvancedSett
 			return output, nil
 		}
 
+		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_export_diagnostics", Other: "Export Diagnostics"}, nil) {
+			output.Action = AdvancedSettingsActionExportDiagnostics
+			return output, nil
+		}
+
 		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_reset_input_mapping", Other: "Reset Input Mapping"}, nil) {
 			if err := os.Remove("input_mapping.json"); err != nil {
 				gaba.GetLogger().Error("Failed to delete input mapping", "error", err)
@@ -167,6 +172,37 @@ func (s *AdvancedSettingsScreen) buildMenuItems(config *internal.Config) []gaba.
 			},
 			SelectedOption: s.findApiTimeoutIndex(config.ApiTimeout.Duration()),
 		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_refresh_interval", Other: "Background Refresh"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "option_disabled", Other: "Disabled"}, nil), Value: -1},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "time_15_minutes", Other: "15 Minutes"}, nil), Value: 15},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "time_30_minutes", Other: "30 Minutes"}, nil), Value: 30},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "time_60_minutes", Other: "60 Minutes"}, nil), Value: 60},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "time_120_minutes", Other: "120 Minutes"}, nil), Value: 120},
+			},
+			SelectedOption: s.findRefreshIntervalIndex(config.GetRefreshInterval()),
+		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_live_updates", Other: "Live Updates"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_false", Other: "False"}, nil), Value: false},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_true", Other: "True"}, nil), Value: true},
+			},
+			SelectedOption: boolToIndex(config.LiveUpdates),
+		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_artwork_cache_size", Other: "Artwork Cache Size"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: "50 MB", Value: 50},
+				{DisplayName: "100 MB", Value: 100},
+				{DisplayName: "200 MB", Value: 200},
+				{DisplayName: "500 MB", Value: 500},
+				{DisplayName: "1 GB", Value: 1024},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "option_unlimited", Other: "Unlimited"}, nil), Value: -1},
+			},
+			SelectedOption: s.findArtworkCacheSizeIndex(config.ArtworkCacheSizeMB),
+		},
 		{
 			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_server_address", Other: "Server Address"}, nil)},
 			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
@@ -193,6 +229,10 @@ func (s *AdvancedSettingsScreen) buildMenuItems(config *internal.Config) []gaba.
 			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_input_mapping", Other: "Input Mapping"}, nil)},
 			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
 		},
+		{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_export_diagnostics", Other: "Export Diagnostics"}, nil)},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		},
 	}
 
 	if _, err := os.Stat("input_mapping.json"); err == nil {
@@ -220,6 +260,21 @@ func (s *AdvancedSettingsScreen) applySettings(config *internal.Config, items []
 				config.ApiTimeout = internal.DurationSeconds(val)
 			}
 
+		case i18n.Localize(&goi18n.Message{ID: "settings_refresh_interval", Other: "Background Refresh"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(int); ok {
+				config.RefreshIntervalMinutes = val
+			}
+
+		case i18n.Localize(&goi18n.Message{ID: "settings_live_updates", Other: "Live Updates"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				config.LiveUpdates = val
+			}
+
+		case i18n.Localize(&goi18n.Message{ID: "settings_artwork_cache_size", Other: "Artwork Cache Size"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(int); ok {
+				config.ArtworkCacheSizeMB = val
+			}
+
 		case i18n.Localize(&goi18n.Message{ID: "settings_log_level", Other: "Log Level"}, nil):
 			if val, ok := item.Options[item.SelectedOption].Value.(internal.LogLevel); ok {
 				config.LogLevel = val
@@ -273,3 +328,29 @@ func (s *AdvancedSettingsScreen) findApiTimeoutIndex(timeout time.Duration) int
 	}
 	return 0 // Default to 15 seconds
 }
+
+func (s *AdvancedSettingsScreen) findRefreshIntervalIndex(interval time.Duration) int {
+	intervals := []time.Duration{
+		0,
+		15 * time.Minute,
+		30 * time.Minute,
+		60 * time.Minute,
+		120 * time.Minute,
+	}
+	for i, t := range intervals {
+		if t == interval {
+			return i
+		}
+	}
+	return 2 // Default to 30 minutes
+}
+
+func (s *AdvancedSettingsScreen) findArtworkCacheSizeIndex(sizeMB int) int {
+	sizes := []int{50, 100, 200, 500, 1024, -1}
+	for i, size := range sizes {
+		if size == sizeMB {
+			return i
+		}
+	}
+	return 2 // Default to 200 MB
+}
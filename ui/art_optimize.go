@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"fmt"
+	"grout/cache"
+	"grout/cfw"
+	"grout/internal"
+	"grout/internal/imageutil"
+	"grout/romm"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type ArtOptimizeInput struct {
+	Config internal.Config
+	Host   romm.Host
+}
+
+type ArtOptimizeOutput struct{}
+
+type ArtOptimizeScreen struct{}
+
+func NewArtOptimizeScreen() *ArtOptimizeScreen {
+	return &ArtOptimizeScreen{}
+}
+
+// Execute re-processes the art already in the CFW art directories with the device's
+// art profile, shrinking art that was downloaded before profiles existed.
+func (s *ArtOptimizeScreen) Execute(input ArtOptimizeInput) ArtOptimizeOutput {
+	logger := gaba.GetLogger()
+
+	mappedPlatforms, err := loadMappedPlatforms(input.Config, input.Host)
+	if err != nil {
+		logger.Error("Failed to fetch platforms", "error", err)
+		gaba.ConfirmationMessage(
+			fmt.Sprintf("Failed to fetch platforms: %v", err),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return ArtOptimizeOutput{}
+	}
+
+	files := findCFWArt(input.Config, mappedPlatforms)
+	if len(files) == 0 {
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "art_optimize_none", Other: "No downloaded art found."}, nil),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return ArtOptimizeOutput{}
+	}
+
+	profile := cfw.GetCFW().ArtProfile()
+	var failCount int32
+
+	gaba.ProcessMessage(
+		fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "art_optimize_processing", Other: "Optimizing %d art images..."}, nil), len(files)),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			var wg sync.WaitGroup
+			semaphore := make(chan struct{}, 4)
+			for path, role := range files {
+				wg.Add(1)
+				go func(path string, role imageutil.ArtRole) {
+					defer wg.Done()
+					semaphore <- struct{}{}
+					defer func() { <-semaphore }()
+
+					if err := profile.Process(path, role); err != nil {
+						logger.Warn("Failed to optimize art", "path", path, "error", err)
+						atomic.AddInt32(&failCount, 1)
+					}
+				}(path, role)
+			}
+			wg.Wait()
+			return nil, nil
+		},
+	)
+
+	failed := int(atomic.LoadInt32(&failCount))
+	logger.Info("Art optimization complete", "processed", len(files)-failed, "failed", failed)
+
+	gaba.ConfirmationMessage(
+		fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "art_optimize_complete", Other: "Optimized %d art images."}, nil), len(files)-failed),
+		ContinueFooter(),
+		gaba.MessageOptions{},
+	)
+	return ArtOptimizeOutput{}
+}
+
+// loadMappedPlatforms returns the platforms that have a ROM directory on the device,
+// from the cache when possible and from RomM otherwise.
+func loadMappedPlatforms(config internal.Config, host romm.Host) ([]romm.Platform, error) {
+	var platforms []romm.Platform
+	if cm := cache.GetCacheManager(); cm != nil {
+		platforms, _ = cm.GetPlatforms()
+	}
+	if len(platforms) == 0 {
+		client := romm.NewClientFromHost(host, config.ApiTimeout.Duration())
+		var err error
+		platforms, err = client.GetPlatforms()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var mapped []romm.Platform
+	for _, p := range platforms {
+		if _, exists := config.DirectoryMappings[p.FSSlug]; exists {
+			mapped = append(mapped, p)
+		}
+	}
+	return mapped, nil
+}
+
+// findCFWArt lists the PNG art in the CFW art directories of platforms, keyed by path.
+// EmulationStation keeps several kinds of art in one folder, so suffixes win over folders.
+func findCFWArt(config internal.Config, platforms []romm.Platform) map[string]imageutil.ArtRole {
+	files := make(map[string]imageutil.ArtRole)
+	seen := make(map[string]bool)
+
+	for _, p := range platforms {
+		dirs := []struct {
+			dir  string
+			role imageutil.ArtRole
+		}{
+			{config.GetArtDirectory(p), imageutil.ArtRoleCover},
+			{config.GetArtPreviewDirectory(p), imageutil.ArtRolePreview},
+			{config.GetArtSplashDirectory(p), imageutil.ArtRoleSplash},
+			{config.GetArtMarqueeDirectory(p), imageutil.ArtRoleMarquee},
+			{config.GetArtBezelDirectory(p), imageutil.ArtRoleBezel},
+			{config.GetBoxbackDirectory(p), imageutil.ArtRoleBoxBack},
+			{config.GetFanartDirectory(p), imageutil.ArtRoleFanart},
+		}
+
+		for _, d := range dirs {
+			if d.dir == "" || seen[d.dir] {
+				continue
+			}
+			seen[d.dir] = true
+
+			entries, err := os.ReadDir(d.dir)
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".png") {
+					continue
+				}
+				files[filepath.Join(d.dir, entry.Name())] = artRoleForFile(entry.Name(), d.role)
+			}
+		}
+	}
+
+	return files
+}
+
+func artRoleForFile(name string, dirRole imageutil.ArtRole) imageutil.ArtRole {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	switch {
+	case strings.HasSuffix(base, "-thumb"):
+		return imageutil.ArtRoleSplash
+	case strings.HasSuffix(base, "-marquee"):
+		return imageutil.ArtRoleMarquee
+	case strings.HasSuffix(base, "-boxback"):
+		return imageutil.ArtRoleBoxBack
+	case strings.HasSuffix(base, "-fanart"):
+		return imageutil.ArtRoleFanart
+	default:
+		return dirRole
+	}
+}
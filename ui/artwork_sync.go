@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"grout/cache"
+	"grout/cfw"
 	"grout/internal"
 	"grout/internal/artutil"
 	"grout/internal/fileutil"
@@ -17,6 +18,7 @@ import (
 	icons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
 	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	uatomic "go.uber.org/atomic"
 )
 
 const (
@@ -207,9 +209,11 @@ func (s *ArtworkSyncScreen) draw(input ArtworkSyncInput) {
 	}
 
 	var downloads []gaba.Download
+	var cfwArtRoles map[string]imageutil.ArtRole
+	var composites []artDownload
 
 	if input.DownloadedOnly {
-		downloads = buildCFWArtDownloads(selectedResults, input.Config, input.Host)
+		downloads, cfwArtRoles, composites = buildCFWArtDownloads(selectedResults, input.Config, input.Host)
 	} else {
 		for _, sr := range selectedResults {
 			for _, rom := range sr.roms {
@@ -230,7 +234,7 @@ func (s *ArtworkSyncScreen) draw(input ArtworkSyncInput) {
 		}
 	}
 
-	if len(downloads) == 0 {
+	if len(downloads) == 0 && len(composites) == 0 {
 		gaba.ConfirmationMessage(
 			i18n.Localize(&goi18n.Message{ID: "artwork_sync_up_to_date", Other: "All artwork is already cached!"}, nil),
 			ContinueFooter(),
@@ -242,9 +246,12 @@ func (s *ArtworkSyncScreen) draw(input ArtworkSyncInput) {
 	headers := make(map[string]string)
 	headers["Authorization"] = input.Host.AuthHeader()
 
-	res, err := gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
-		AutoContinueOnComplete: true,
-	})
+	res := &gaba.DownloadResult{}
+	if len(downloads) > 0 {
+		res, err = gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
+			AutoContinueOnComplete: true,
+		})
+	}
 	if err != nil {
 		logger.Error("Artwork download failed", "error", err)
 		gaba.ConfirmationMessage(
@@ -259,6 +266,12 @@ func (s *ArtworkSyncScreen) draw(input ArtworkSyncInput) {
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, 4)
 
+	// Art for the CFW is sized for the device screen; Grout's own cache keeps its window-based sizing.
+	var profile imageutil.ArtProfile
+	if input.DownloadedOnly {
+		profile = cfw.GetCFW().ArtProfile()
+	}
+
 	for _, download := range res.Completed {
 		wg.Add(1)
 		go func(dl gaba.Download) {
@@ -266,7 +279,13 @@ func (s *ArtworkSyncScreen) draw(input ArtworkSyncInput) {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			if err := imageutil.ProcessArtImage(dl.Location); err != nil {
+			var err error
+			if input.DownloadedOnly {
+				err = profile.Process(dl.Location, cfwArtRoles[dl.Location])
+			} else {
+				err = imageutil.ProcessArtImage(dl.Location)
+			}
+			if err != nil {
 				logger.Warn("Failed to process artwork", "path", dl.Location, "error", err)
 				return
 			}
@@ -289,6 +308,26 @@ func (s *ArtworkSyncScreen) draw(input ArtworkSyncInput) {
 		},
 	)
 
+	if len(composites) > 0 {
+		var games []romm.Rom
+		for _, sr := range selectedResults {
+			games = append(games, sr.roms...)
+		}
+		progress := &uatomic.Float64{}
+		rendered, _ := gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "artwork_sync_rendering", Other: "Rendering composite artwork..."}, nil),
+			gaba.ProcessMessageOptions{
+				ShowThemeBackground: true,
+				ShowProgressBar:     true,
+				Progress:            progress,
+			},
+			func() (int, error) {
+				return NewDownloadScreen().downloadArt(composites, games, headers, progress, input.Host.InsecureSkipVerify), nil
+			},
+		)
+		atomic.AddInt32(&successCount, int32(rendered))
+	}
+
 	finalCount := int(atomic.LoadInt32(&successCount))
 	logger.Info("Artwork sync complete", "success", finalCount, "failed", len(res.Failed))
 
@@ -361,9 +400,14 @@ type platformRoms struct {
 	roms     []romm.Rom
 }
 
-// buildCFWArtDownloads builds download entries targeting CFW art directories.
-func buildCFWArtDownloads(results []platformRoms, config internal.Config, host romm.Host) []gaba.Download {
+// buildCFWArtDownloads builds download entries targeting CFW art directories, along with
+// the art role of each download location. Composite covers are rendered on the device
+// rather than downloaded, so they are returned separately for the download screen's art
+// path, with the regular cover as their fallback.
+func buildCFWArtDownloads(results []platformRoms, config internal.Config, host romm.Host) ([]gaba.Download, map[string]imageutil.ArtRole, []artDownload) {
 	var downloads []gaba.Download
+	var composites []artDownload
+	roles := make(map[string]imageutil.ArtRole)
 
 	for _, sr := range results {
 		for _, rom := range sr.roms {
@@ -371,7 +415,17 @@ func buildCFWArtDownloads(results []platformRoms, config internal.Config, host r
 
 			// Cover art
 			coverURL := rom.GetArtworkURL(config.ArtKind, host)
-			if coverURL != "" {
+			if composite := buildCompositeArt(config, host, sr.platform, rom); composite != nil {
+				composites = append(composites, artDownload{
+					URL:       coverURL,
+					Location:  filepath.Join(config.GetArtDirectory(sr.platform), artFileName),
+					GameID:    rom.ID,
+					GameName:  rom.Name,
+					IsImage:   true,
+					Role:      imageutil.ArtRoleCover,
+					Composite: composite,
+				})
+			} else if coverURL != "" {
 				artDir := config.GetArtDirectory(sr.platform)
 				artLocation := filepath.Join(artDir, artFileName)
 				downloads = append(downloads, gaba.Download{
@@ -379,6 +433,7 @@ func buildCFWArtDownloads(results []platformRoms, config internal.Config, host r
 					Location:    artLocation,
 					DisplayName: rom.Name,
 				})
+				roles[artLocation] = imageutil.ArtRoleCover
 			}
 
 			// Screenshot preview
@@ -386,11 +441,13 @@ func buildCFWArtDownloads(results []platformRoms, config internal.Config, host r
 				previewDir := config.GetArtPreviewDirectory(sr.platform)
 				if previewDir != "" {
 					if screenshotURL := rom.GetScreenshotURL(host); screenshotURL != "" {
+						location := filepath.Join(previewDir, artFileName)
 						downloads = append(downloads, gaba.Download{
 							URL:         screenshotURL,
-							Location:    filepath.Join(previewDir, artFileName),
+							Location:    location,
 							DisplayName: rom.Name,
 						})
+						roles[location] = imageutil.ArtRolePreview
 					}
 				}
 			}
@@ -400,16 +457,18 @@ func buildCFWArtDownloads(results []platformRoms, config internal.Config, host r
 				splashDir := config.GetArtSplashDirectory(sr.platform)
 				if splashDir != "" {
 					if splashURL := rom.GetSplashArtURL(config.DownloadSplashArt, host); splashURL != "" {
+						location := filepath.Join(splashDir, artFileName)
 						downloads = append(downloads, gaba.Download{
 							URL:         splashURL,
-							Location:    filepath.Join(splashDir, artFileName),
+							Location:    location,
 							DisplayName: rom.Name,
 						})
+						roles[location] = imageutil.ArtRoleSplash
 					}
 				}
 			}
 		}
 	}
 
-	return downloads
+	return downloads, roles, composites
 }
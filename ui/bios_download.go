@@ -131,29 +131,23 @@ func (s *BIOSDownloadScreen) draw(input BIOSDownloadInput) (BIOSDownloadOutput,
 		var displayText string
 		var shouldSelect bool
 
-		var fileExists bool
+		biosFile := bios.File{FileName: fw.FileName, RelativePath: fw.FileName}
 		if item.metadata != nil {
-			fileExists = bios.FileExists(*item.metadata, input.Platform.FSSlug)
-		} else {
-			relativePath := fw.FileName
-			for _, p := range cfw.GetBIOSFilePaths(relativePath, input.Platform.FSSlug) {
-				if fileutil.FileExists(p) {
-					fileExists = true
-					break
-				}
-			}
+			biosFile = *item.metadata
+		}
+		verification := bios.Verify(biosFile, input.Platform.FSSlug, firmwareList)
+		if verification.Status == bios.StatusMismatch {
+			logger.Warn("BIOS file doesn't match a known good dump", "file", fw.FileName, "path", verification.Path, "md5", verification.MD5)
 		}
 
-		var statusText string
-		if fileExists {
-			statusText = i18n.Localize(&goi18n.Message{ID: "bios_status_ready", Other: "Ready"}, nil)
-		} else {
-			statusText = i18n.Localize(&goi18n.Message{ID: "bios_status_not_installed", Other: "Missing"}, nil)
+		statusText := biosStatusText(verification.Status)
+		switch verification.Status {
+		case bios.StatusMissing, bios.StatusOptional, bios.StatusMismatch:
 			shouldSelect = true
 		}
 
 		optionalText := ""
-		if item.metadata != nil && item.metadata.Optional {
+		if biosFile.Optional && verification.Status != bios.StatusOptional {
 			optionalText = " (Optional)"
 		}
 
@@ -289,3 +283,18 @@ func (s *BIOSDownloadScreen) draw(input BIOSDownloadInput) (BIOSDownloadOutput,
 
 	return output, nil
 }
+
+func biosStatusText(status bios.Status) string {
+	switch status {
+	case bios.StatusVerified:
+		return i18n.Localize(&goi18n.Message{ID: "bios_status_verified", Other: "Verified"}, nil)
+	case bios.StatusPresent:
+		return i18n.Localize(&goi18n.Message{ID: "bios_status_ready", Other: "Ready"}, nil)
+	case bios.StatusMismatch:
+		return i18n.Localize(&goi18n.Message{ID: "bios_status_mismatch", Other: "Wrong Version"}, nil)
+	case bios.StatusOptional:
+		return i18n.Localize(&goi18n.Message{ID: "bios_status_optional", Other: "Optional"}, nil)
+	default:
+		return i18n.Localize(&goi18n.Message{ID: "bios_status_not_installed", Other: "Missing"}, nil)
+	}
+}
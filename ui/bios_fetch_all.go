@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"grout/bios"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// maxUnresolvedBIOSShown caps how many unresolved files are listed in the summary.
+const maxUnresolvedBIOSShown = 8
+
+type BIOSFetchAllInput struct {
+	Config internal.Config
+	Host   romm.Host
+}
+
+type BIOSFetchAllOutput struct{}
+
+type BIOSFetchAllScreen struct{}
+
+func NewBIOSFetchAllScreen() *BIOSFetchAllScreen {
+	return &BIOSFetchAllScreen{}
+}
+
+// biosTarget is one BIOS file a platform needs, with the firmware that provides it.
+type biosTarget struct {
+	platform romm.Platform
+	file     bios.File
+	firmware romm.Firmware
+}
+
+// Execute downloads every missing or invalid BIOS file for all mapped platforms in one
+// batch and reports what is still missing afterwards.
+func (s *BIOSFetchAllScreen) Execute(input BIOSFetchAllInput) BIOSFetchAllOutput {
+	logger := gaba.GetLogger()
+
+	platforms, err := loadMappedPlatforms(input.Config, input.Host)
+	if err != nil {
+		logger.Error("Failed to fetch platforms", "error", err)
+		gaba.ConfirmationMessage(
+			fmt.Sprintf("Failed to fetch platforms: %v", err),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return BIOSFetchAllOutput{}
+	}
+
+	var targets []biosTarget
+	var unresolved []string
+	gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "bios_fetch_all_checking", Other: "Checking BIOS files..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			targets, unresolved = s.resolve(input, platforms)
+			return nil, nil
+		},
+	)
+
+	if len(targets) == 0 && len(unresolved) == 0 {
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "bios_fetch_all_none_missing", Other: "All required BIOS files are installed."}, nil),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return BIOSFetchAllOutput{}
+	}
+
+	saved, failed := s.download(input.Host, targets)
+	unresolved = append(unresolved, failed...)
+
+	for _, name := range unresolved {
+		logger.Info("BIOS file still missing", "file", name)
+	}
+
+	message := fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "bios_fetch_all_complete", Other: "Downloaded %d BIOS file(s)."}, nil), saved)
+	if len(unresolved) > 0 {
+		shown := unresolved
+		if len(shown) > maxUnresolvedBIOSShown {
+			shown = shown[:maxUnresolvedBIOSShown]
+		}
+		message += "\n\n" + i18n.Localize(&goi18n.Message{ID: "bios_fetch_all_still_missing", Other: "Still missing:"}, nil) + "\n" + strings.Join(shown, "\n")
+		if more := len(unresolved) - len(shown); more > 0 {
+			message += "\n" + fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "bios_fetch_all_more", Other: "...and %d more"}, nil), more)
+		}
+	}
+
+	gaba.ConfirmationMessage(message, ContinueFooter(), gaba.MessageOptions{})
+	return BIOSFetchAllOutput{}
+}
+
+// resolve works out which BIOS files each platform needs and which firmware on the
+// server provides them. Files the server can't provide are returned by name.
+func (s *BIOSFetchAllScreen) resolve(input BIOSFetchAllInput, platforms []romm.Platform) ([]biosTarget, []string) {
+	logger := gaba.GetLogger()
+	client := romm.NewClientFromHost(input.Host, input.Config.ApiTimeout.Duration())
+
+	var targets []biosTarget
+	var unresolved []string
+	for _, platform := range platforms {
+		files := bios.GetFilesForPlatform(platform.FSSlug)
+		if len(files) == 0 {
+			continue
+		}
+
+		firmware, err := client.GetFirmware(platform.ID)
+		if err != nil {
+			logger.Warn("Failed to fetch firmware", "platform", platform.FSSlug, "error", err)
+		}
+
+		for _, file := range files {
+			verification := bios.Verify(file, platform.FSSlug, firmware)
+			if !bios.NeedsDownload(verification) {
+				continue
+			}
+
+			fw, ok := bios.MatchFirmware(file, firmware)
+			if !ok || (verification.Status == bios.StatusMismatch && strings.EqualFold(fw.MD5Hash, verification.MD5)) {
+				unresolved = append(unresolved, fmt.Sprintf("%s: %s", platform.Name, file.FileName))
+				continue
+			}
+			targets = append(targets, biosTarget{platform: platform, file: file, firmware: fw})
+		}
+	}
+	return targets, unresolved
+}
+
+// download fetches each firmware once and writes it everywhere the targets need it.
+// It returns how many files were saved and the names of the ones that failed.
+func (s *BIOSFetchAllScreen) download(host romm.Host, targets []biosTarget) (int, []string) {
+	logger := gaba.GetLogger()
+	if len(targets) == 0 {
+		return 0, nil
+	}
+
+	var downloads []gaba.Download
+	byLocation := make(map[string][]biosTarget)
+	for _, target := range targets {
+		location := filepath.Join(fileutil.TempDir(), fmt.Sprintf("bios_%d_%s", target.firmware.ID, target.firmware.FileName))
+		if _, queued := byLocation[location]; !queued {
+			downloads = append(downloads, gaba.Download{
+				URL:         host.URL() + target.firmware.DownloadURL,
+				Location:    location,
+				DisplayName: target.firmware.FileName,
+			})
+		}
+		byLocation[location] = append(byLocation[location], target)
+	}
+
+	headers := map[string]string{"Authorization": host.AuthHeader()}
+	res, err := gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
+		AutoContinueOnComplete: true,
+	})
+	if err != nil {
+		logger.Error("BIOS download failed", "error", err)
+		return 0, targetNames(targets)
+	}
+
+	saved := 0
+	var failed []string
+	for _, download := range res.Completed {
+		data, err := os.ReadFile(download.Location)
+		if err != nil {
+			logger.Error("Failed to read downloaded BIOS file", "file", download.DisplayName, "error", err)
+			failed = append(failed, targetNames(byLocation[download.Location])...)
+			continue
+		}
+		for _, target := range byLocation[download.Location] {
+			if !bios.MatchesChecksum(target.file, data) {
+				logger.Warn("Downloaded BIOS file does not match its expected checksum", "file", target.file.FileName, "md5", target.file.MD5)
+				failed = append(failed, targetNames([]biosTarget{target})...)
+				continue
+			}
+			if err := bios.SaveFile(target.file, target.platform.FSSlug, data); err != nil {
+				logger.Error("Failed to save BIOS file", "file", target.file.FileName, "error", err)
+				failed = append(failed, targetNames([]biosTarget{target})...)
+				continue
+			}
+			saved++
+		}
+		os.Remove(download.Location)
+	}
+	for _, download := range res.Failed {
+		logger.Error("BIOS download failed", "file", download.Download.DisplayName, "error", download.Error)
+		failed = append(failed, targetNames(byLocation[download.Download.Location])...)
+	}
+
+	logger.Info("BIOS fetch complete", "saved", saved, "failed", len(failed))
+	return saved, failed
+}
+
+func targetNames(targets []biosTarget) []string {
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		names = append(names, fmt.Sprintf("%s: %s", t.platform.Name, t.file.FileName))
+	}
+	return names
+}
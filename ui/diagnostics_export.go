@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"grout/diagnostics"
+	"grout/internal"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type DiagnosticsExportInput struct {
+	Config *internal.Config
+	Host   romm.Host
+}
+
+type DiagnosticsExportOutput struct{}
+
+type DiagnosticsExportScreen struct{}
+
+func NewDiagnosticsExportScreen() *DiagnosticsExportScreen {
+	return &DiagnosticsExportScreen{}
+}
+
+// Execute writes a diagnostics bundle to the SD card for attaching to bug reports. The
+// bundle isn't uploaded to RomM: RomM has no endpoint for device attachments, and the
+// ones that take uploads (saves, firmware) would file it as game data.
+func (s *DiagnosticsExportScreen) Execute(input DiagnosticsExportInput) DiagnosticsExportOutput {
+	logger := gaba.GetLogger()
+
+	var path string
+	var exportErr error
+	gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "diagnostics_collecting", Other: "Collecting diagnostics..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			path, exportErr = diagnostics.Export(input.Config, input.Host)
+			return nil, nil
+		},
+	)
+
+	if exportErr != nil {
+		logger.Error("Failed to export diagnostics", "error", exportErr)
+		gaba.ConfirmationMessage(
+			fmt.Sprintf("Failed to export diagnostics: %v", exportErr),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return DiagnosticsExportOutput{}
+	}
+
+	gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "diagnostics_saved", Other: "Diagnostics saved to\n{{.Path}}"}, map[string]interface{}{"Path": path}),
+		ContinueFooter(),
+		gaba.MessageOptions{},
+	)
+	return DiagnosticsExportOutput{}
+}
@@ -1,42 +1,59 @@
 package ui
 
 import (
+	"context"
+	"crypto/md5"
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"grout/cache"
 	"grout/cfw"
-	"grout/cfw/muos"
 	"grout/internal"
 	"grout/internal/artutil"
 	"grout/internal/fileutil"
 	"grout/internal/gamelist"
 	"grout/internal/imageutil"
+	"grout/internal/multidisc"
+	"grout/internal/patch"
 	"grout/romm"
+	"hash/crc32"
 	_ "image/gif"
 	_ "image/jpeg"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
 	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 	"go.uber.org/atomic"
 )
 
 type DownloadInput struct {
-	Config         internal.Config
-	Host           romm.Host
-	Platform       romm.Platform
-	SelectedGames  []romm.Rom
-	AllGames       []romm.Rom
-	SearchFilter   string
-	SelectedFileID int
+	Config        internal.Config
+	Host          romm.Host
+	Platform      romm.Platform
+	SelectedGames []romm.Rom
+	AllGames      []romm.Rom
+	SearchFilter  string
+	Selection     FileSelection
+}
+
+// FileSelection narrows down which files of a single game are downloaded.
+type FileSelection struct {
+	FileID     int            // File to download for games with several versions; 0 for the default
+	PatchID    int            // Patch to apply after downloading; 0 for none
+	Extras     []romm.RomFile // DLC, updates, manuals and the like, saved to their category's directory
+	ExtrasOnly bool           // Skip the game itself and only download Extras
 }
 
 type DownloadOutput struct {
@@ -49,25 +66,65 @@ type DownloadOutput struct {
 type DownloadScreen struct{}
 
 type artDownload struct {
-	URL      string
-	Location string
-	GameName string
-	IsImage  bool
+	URL       string
+	Location  string
+	GameID    int
+	GameName  string
+	IsImage   bool
+	Role      imageutil.ArtRole // Decides how the image is resized for the device
+	Composite *compositeArt     // Set when the art is rendered on the device; URL is the fallback cover
+}
+
+// compositeArt describes a mix image built from separately downloaded pieces.
+type compositeArt struct {
+	Template       imageutil.CompositeTemplate
+	PlatformFSSlug string // Where the pieces are tracked in the artwork cache
+	Background     artPiece
+	Box            artPiece
+	Logo           artPiece
+}
+
+const (
+	// streamConnectTimeout bounds connecting to RomM and the TLS handshake for streamed archives.
+	streamConnectTimeout = 30 * time.Second
+	// streamResponseTimeout bounds the wait for RomM to start answering, which includes
+	// building the zip of a multi-file ROM.
+	streamResponseTimeout = 5 * time.Minute
+	// streamIdleTimeout fails a streamed archive that stops delivering data.
+	streamIdleTimeout = 60 * time.Second
+)
+
+// romDownloads maps the URL of each ROM download to the ID of its game. Results are
+// matched to games through it rather than by display name, which two games can share.
+type romDownloads map[string]int
+
+// completed reports whether the ROM download of g is among res.Completed.
+func (r romDownloads) completed(res *gaba.DownloadResult, g romm.Rom) bool {
+	return slices.ContainsFunc(res.Completed, func(d gaba.Download) bool {
+		id, ok := r[d.URL]
+		return ok && id == g.ID
+	})
+}
+
+// artPiece is one source image of a composite and where it is cached.
+type artPiece struct {
+	URL  string
+	Path string
 }
 
 func NewDownloadScreen() *DownloadScreen {
 	return &DownloadScreen{}
 }
 
-func (s *DownloadScreen) Execute(config internal.Config, host romm.Host, platform romm.Platform, selectedGames []romm.Rom, allGames []romm.Rom, searchFilter string, selectedFileID int) DownloadOutput {
+func (s *DownloadScreen) Execute(config internal.Config, host romm.Host, platform romm.Platform, selectedGames []romm.Rom, allGames []romm.Rom, searchFilter string, selection FileSelection) DownloadOutput {
 	result, err := s.draw(DownloadInput{
-		Config:         config,
-		Host:           host,
-		Platform:       platform,
-		SelectedGames:  selectedGames,
-		AllGames:       allGames,
-		SelectedFileID: selectedFileID,
-		SearchFilter:   searchFilter,
+		Config:        config,
+		Host:          host,
+		Platform:      platform,
+		SelectedGames: selectedGames,
+		AllGames:      allGames,
+		SearchFilter:  searchFilter,
+		Selection:     selection,
 	})
 
 	if err != nil {
@@ -95,7 +152,8 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 		SearchFilter: input.SearchFilter,
 	}
 
-	downloads, artDownloads, gamelistEntries := s.buildDownloads(input.Config, input.Host, input.Platform, input.SelectedGames, input.SelectedFileID)
+	downloads, artDownloads, gamelistEntries, romURLs := s.buildDownloads(input.Config, input.Host, input.Platform, input.SelectedGames, input.Selection)
+	downloads, streamed := s.splitStreamedDownloads(input.Config, input.Platform, input.SelectedGames, downloads, romURLs)
 
 	headers := make(map[string]string)
 	headers["Authorization"] = input.Host.AuthHeader()
@@ -104,23 +162,46 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 		return strings.Compare(strings.ToLower(a.DisplayName), strings.ToLower(b.DisplayName))
 	})
 
-	logger.Debug("Starting ROM download", "downloads", downloads)
+	res := &gaba.DownloadResult{}
+	if len(downloads) > 0 {
+		logger.Debug("Starting ROM download", "downloads", downloads)
 
-	res, err := gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
-		AutoContinueOnComplete: input.Config.DownloadArt,
-		SkipSSLVerification:    input.Host.InsecureSkipVerify,
-	})
-	if err != nil {
-		logger.Error("Error downloading", "error", err)
+		var err error
+		res, err = gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
+			AutoContinueOnComplete: input.Config.DownloadArt || len(streamed) > 0,
+			SkipSSLVerification:    input.Host.InsecureSkipVerify,
+		})
+		if err != nil {
+			logger.Error("Error downloading", "error", err)
 
-		// Clean up any partial downloads when cancelled
-		if errors.Is(err, gaba.ErrCancelled) {
-			for _, d := range downloads {
-				fileutil.DeleteFile(d.Location)
+			// Clean up any partial downloads when cancelled
+			if errors.Is(err, gaba.ErrCancelled) {
+				for _, d := range downloads {
+					fileutil.DeleteFile(d.Location)
+				}
 			}
+
+			return output, err
 		}
+	}
 
-		return output, err
+	// Archives are extracted straight from the response body so they never need
+	// to be stored on the SD card alongside their contents.
+	streamedFiles := make(map[int][]string, len(streamed))
+	for i, a := range streamed {
+		files, err := s.runStreamedArchive(a, input.Host, headers)
+		if errors.Is(err, gaba.ErrCancelled) {
+			// Like cancelling the download manager, this stops every archive still to
+			// come; the ones already extracted are kept.
+			logger.Info("Streamed download cancelled", "game", a.Game.Name, "skipped", len(streamed)-i-1)
+			break
+		}
+		if err != nil {
+			res.Failed = append(res.Failed, gaba.DownloadError{Download: a.Download, Error: err})
+			continue
+		}
+		streamedFiles[a.Game.ID] = files
+		res.Completed = append(res.Completed, a.Download)
 	}
 
 	logger.Debug("Download results", "completed", len(res.Completed), "failed", len(res.Failed))
@@ -132,7 +213,7 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 
 		for _, g := range downloads {
 			failedMatch := slices.ContainsFunc(res.Failed, func(de gaba.DownloadError) bool {
-				return de.Download.DisplayName == g.DisplayName
+				return de.Download.URL == g.URL && de.Download.Location == g.Location
 			})
 			if failedMatch {
 				fileutil.DeleteFile(g.Location)
@@ -149,10 +230,7 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 			continue
 		}
 
-		completed := slices.ContainsFunc(res.Completed, func(d gaba.Download) bool {
-			return d.DisplayName == g.Name
-		})
-		if !completed {
+		if !romURLs.completed(res, g) {
 			continue
 		}
 
@@ -165,56 +243,37 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 			}
 		}
 
-		tmpZipPath := filepath.Join(fileutil.TempDir(), fmt.Sprintf("grout_multirom_%d.zip", g.ID))
+		// The extracted folder is named after the game and removed on failure, so a
+		// name that escapes the ROM directory must never get that far.
+		if err := multidisc.ValidateGameName(g.FsNameNoExt); err != nil {
+			logger.Error("Skipping extracted ROM with an unsafe name", "game", g.Name, "error", err)
+			continue
+		}
+
 		romDirectory := input.Config.GetPlatformRomDirectory(gamePlatform)
 		extractDir := filepath.Join(romDirectory, g.FsNameNoExt)
 
-		progress := &atomic.Float64{}
-		_, err := gaba.ProcessMessage(
-			i18n.Localize(&goi18n.Message{ID: "download_extracting", Other: "Extracting {{.Name}}..."}, map[string]interface{}{"Name": g.DisplayName}),
-			gaba.ProcessMessageOptions{
-				ShowThemeBackground: true,
-				ShowProgressBar:     true,
-				Progress:            progress,
-			},
-			func() (interface{}, error) {
-				logger.Debug("Extracting multi-file ROM", "game", g.DisplayName, "dest", extractDir)
-
-				if err := fileutil.Unzip(tmpZipPath, extractDir, progress); err != nil {
-					logger.Error("Failed to extract multi-file ROM", "game", g.DisplayName, "error", err)
-					os.Remove(tmpZipPath)
-					return nil, err
-				}
-
-				if cfw.GetCFW() == cfw.MuOS {
-					if err := muos.OrganizeMultiFileRom(extractDir, romDirectory, g.FsNameNoExt); err != nil {
-						logger.Error("Failed to organize multi-file ROM for muOS", "game", g.FsNameNoExt, "error", err)
-						os.Remove(tmpZipPath)
-						os.RemoveAll(extractDir)
-						return nil, err
-					}
-				}
-
-				if err := os.Remove(tmpZipPath); err != nil {
-					logger.Warn("Failed to remove temp zip file", "path", tmpZipPath, "error", err)
-				}
-
-				// Update the gamelist entry to point to the extracted file
-				// instead of the (now deleted) temporary zip.
-				newGamePath := resolveExtractedGamePath(romDirectory, extractDir, g.FsNameNoExt)
-				for i, entry := range gamelistEntries {
-					if entry.Game.ID == g.ID {
-						gamelistEntries[i].GamePath = newGamePath
-						break
-					}
-				}
-
-				return nil, nil
-			},
-		)
+		var newGamePath string
+		if multidisc.IsDiscSet(romFileNames(g)) || multidisc.HasPlaylist(extractDir) {
+			layout := cfw.GetCFW().MultiDiscLayout()
+			m3uPath, err := multidisc.Organize(extractDir, romDirectory, g.FsNameNoExt, layout)
+			if err != nil {
+				logger.Error("Failed to organize multi-disc ROM", "game", g.FsNameNoExt, "error", err)
+				os.RemoveAll(extractDir)
+				continue
+			}
+			logger.Debug("Organized multi-disc ROM", "game", g.FsNameNoExt, "playlist", m3uPath)
+			newGamePath = m3uPath
+		} else {
+			newGamePath = resolveExtractedGamePath(romDirectory, extractDir, g.FsNameNoExt)
+		}
 
-		if err != nil {
-			continue
+		// Update the gamelist entry to point to the playlist or extracted file.
+		for i, entry := range gamelistEntries {
+			if entry.Game.ID == g.ID {
+				gamelistEntries[i].GamePath = newGamePath
+				break
+			}
 		}
 	}
 
@@ -224,10 +283,7 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 				continue
 			}
 
-			completed := slices.ContainsFunc(res.Completed, func(d gaba.Download) bool {
-				return d.DisplayName == g.Name
-			})
-			if !completed {
+			if !romURLs.completed(res, g) {
 				continue
 			}
 
@@ -240,6 +296,19 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 				}
 			}
 
+			if files, ok := streamedFiles[g.ID]; ok {
+				romDirectory := input.Config.GetPlatformRomDirectory(gamePlatform)
+				if gamePath := archiveGamePath(files); gamePath != "" {
+					for i, entry := range gamelistEntries {
+						if entry.Game.ID == g.ID {
+							gamelistEntries[i].GamePath = filepath.Join(romDirectory, gamePath)
+							break
+						}
+					}
+				}
+				continue
+			}
+
 			if len(g.Files) > 0 {
 				ext := strings.ToLower(filepath.Ext(g.Files[0].FileName))
 				if ext == ".zip" || ext == ".7z" {
@@ -280,16 +349,7 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 								logger.Warn("Failed to remove archive file after extraction", "path", archivePath, "error", err)
 							}
 
-							if len(archiveFiles) > 0 {
-								gamePath := archiveFiles[0]
-								if len(archiveFiles) > 1 {
-									for _, f := range archiveFiles {
-										if strings.ToLower(filepath.Ext(f)) == ".m3u" {
-											gamePath = f
-											break
-										}
-									}
-								}
+							if gamePath := archiveGamePath(archiveFiles); gamePath != "" {
 								for i, entry := range gamelistEntries {
 									if entry.Game.ID == g.ID {
 										gamelistEntries[i].GamePath = filepath.Join(romDirectory, gamePath)
@@ -313,15 +373,34 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 
 	downloadedGames := make([]romm.Rom, 0, len(res.Completed))
 	for _, g := range input.SelectedGames {
-		if slices.ContainsFunc(res.Completed, func(d gaba.Download) bool {
-			return d.DisplayName == g.Name
-		}) {
+		if romURLs.completed(res, g) {
 			downloadedGames = append(downloadedGames, g)
 		}
 	}
 
 	logger.Debug("Download complete", "successful", len(downloadedGames), "attempted", len(input.SelectedGames))
 
+	if input.Selection.PatchID > 0 && len(downloadedGames) == 1 {
+		g := downloadedGames[0]
+		patchedEntry, err := gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "download_patching", Other: "Patching {{.Name}}..."}, map[string]interface{}{"Name": g.Name}),
+			gaba.ProcessMessageOptions{ShowThemeBackground: true},
+			func() (gamelist.RomGameEntry, error) {
+				return s.applyPatch(input, g, gamelistEntries, headers)
+			},
+		)
+		if err != nil {
+			logger.Error("Failed to apply patch", "game", g.Name, "error", err)
+			gaba.ConfirmationMessage(
+				i18n.Localize(&goi18n.Message{ID: "download_patch_failed", Other: "The ROM was downloaded, but the patch could not be applied: {{.Error}}"}, map[string]interface{}{"Error": err.Error()}),
+				ContinueFooter(),
+				gaba.MessageOptions{},
+			)
+		} else {
+			gamelistEntries = append(gamelistEntries, patchedEntry)
+		}
+	}
+
 	if len(artDownloads) > 0 && len(downloadedGames) > 0 {
 		progress := &atomic.Float64{}
 		_, err := gaba.ProcessMessage(
@@ -343,13 +422,15 @@ func (s *DownloadScreen) draw(input DownloadInput) (DownloadOutput, error) {
 	}
 
 	cfw.FillGamesMetadata(gamelistEntries)
+	updateRetroArchPlaylists(input.Config, gamelistEntries)
 
 	output.DownloadedGames = downloadedGames
 	return output, nil
 }
 
-func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host, platform romm.Platform, games []romm.Rom, selectedFileID int) ([]gaba.Download, []artDownload, []gamelist.RomGameEntry) {
+func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host, platform romm.Platform, games []romm.Rom, selection FileSelection) ([]gaba.Download, []artDownload, []gamelist.RomGameEntry, romDownloads) {
 	downloads := make([]gaba.Download, 0, len(games))
+	romURLs := make(romDownloads, len(games))
 	artDownloads := make([]artDownload, 0, len(games))
 	gamesSummaries := make([]gamelist.RomGameEntry, 0, len(games))
 
@@ -367,8 +448,26 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 			}
 		}
 
+		for _, f := range selection.Extras {
+			if f.RomID != 0 && f.RomID != g.ID {
+				continue
+			}
+			extraURL, _ := url.JoinPath(host.URL(), "/api/roms/", strconv.Itoa(g.ID), "content", f.FileName)
+			extraURL += "?" + url.Values{"file_ids": {strconv.Itoa(f.ID)}}.Encode()
+			downloads = append(downloads, gaba.Download{
+				URL:         extraURL,
+				Location:    filepath.Join(config.GetFileCategoryDirectory(gamePlatform, f.GetCategory()), f.FileName),
+				DisplayName: f.FileName,
+				Timeout:     config.DownloadTimeout.Duration(),
+			})
+		}
+		if selection.ExtrasOnly {
+			continue
+		}
+
 		romDirectory := config.GetPlatformRomDirectory(gamePlatform)
 		gamelistRomEntry.RomDirectory = romDirectory
+		gamelistRomEntry.GamelistDirectory = config.GetPlatformGamelistDirectory(gamePlatform)
 		downloadLocation := ""
 
 		sourceURL := ""
@@ -377,6 +476,14 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 			tmpDir := fileutil.TempDir()
 			downloadLocation = filepath.Join(tmpDir, fmt.Sprintf("grout_multirom_%d.zip", g.ID))
 			sourceURL, _ = url.JoinPath(host.URL(), "/api/roms/", strconv.Itoa(g.ID), "content", g.FsName)
+			// Leave DLC, updates and the like out of the archive; they are saved elsewhere
+			if gameFiles := g.GameFiles(); len(gameFiles) > 0 && len(gameFiles) < len(g.Files) {
+				ids := make([]string, len(gameFiles))
+				for i, f := range gameFiles {
+					ids[i] = strconv.Itoa(f.ID)
+				}
+				sourceURL += "?" + url.Values{"file_ids": {strings.Join(ids, ",")}}.Encode()
+			}
 		} else {
 			// Skip games with no file metadata to avoid an out-of-range panic.
 			// This can happen when the cached row was written without a
@@ -386,11 +493,14 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 					"game", g.Name, "id", g.ID, "fs_name", g.FsName)
 				continue
 			}
-			// Find the file to download - use selected file if specified, otherwise first file
+			// Find the file to download - use selected file if specified, otherwise the first game file
 			fileToDownload := g.Files[0]
-			if selectedFileID > 0 {
+			if gameFiles := g.GameFiles(); len(gameFiles) > 0 {
+				fileToDownload = gameFiles[0]
+			}
+			if selection.FileID > 0 {
 				for _, f := range g.Files {
-					if f.ID == selectedFileID {
+					if f.ID == selection.FileID {
 						fileToDownload = f
 						break
 					}
@@ -403,6 +513,7 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 
 		gamelistRomEntry.GamePath = downloadLocation
 
+		romURLs[sourceURL] = g.ID
 		downloads = append(downloads, gaba.Download{
 			URL:         sourceURL,
 			Location:    downloadLocation,
@@ -413,21 +524,23 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 		if config.DownloadArt && (g.PathCoverLarge != "" || g.PathCoverSmall != "" || g.URLCover != "") {
 			// Prepare download for cover art
 			artDir := config.GetArtDirectory(gamePlatform)
-			var artFileName string
-			if cfw.GetCFW() == cfw.MinUI && len(g.Files) > 0 {
-				artFileName = g.Files[0].FileName + ".png"
-			} else {
-				artFileName = g.FsNameNoExt + ".png"
+			romFileName := ""
+			if len(g.Files) > 0 {
+				romFileName = g.Files[0].FileName
 			}
+			artFileName := cfw.GetCFW().ArtFileName(g.FsNameNoExt, romFileName)
 			artLocation := filepath.Join(artDir, artFileName)
 			coverURL := g.GetArtworkURL(config.ArtKind, host)
 			gamelistRomEntry.ArtLocation.ImagePath = artLocation
 
 			artDownloads = append(artDownloads, artDownload{
-				URL:      coverURL,
-				Location: artLocation,
-				GameName: g.Name,
-				IsImage:  true,
+				URL:       coverURL,
+				Location:  artLocation,
+				GameID:    g.ID,
+				GameName:  g.Name,
+				IsImage:   true,
+				Role:      imageutil.ArtRoleCover,
+				Composite: buildCompositeArt(config, host, gamePlatform, g),
 			})
 
 			// Prepare download for additional art types if enabled
@@ -438,8 +551,10 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 					artDownloads = append(artDownloads, artDownload{
 						URL:      screenshotURL,
 						Location: screenshotPreviewLocation,
+						GameID:   g.ID,
 						GameName: g.Name,
 						IsImage:  true,
+						Role:     imageutil.ArtRolePreview,
 					})
 				}
 			}
@@ -465,8 +580,10 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 					artDownloads = append(artDownloads, artDownload{
 						URL:      splashURL,
 						Location: splashArtLocation,
+						GameID:   g.ID,
 						GameName: g.Name,
 						IsImage:  true,
+						Role:     imageutil.ArtRoleSplash,
 					})
 				}
 			}
@@ -474,8 +591,8 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 			artMarqueeDir := config.GetArtMarqueeDirectory(gamePlatform)
 			if config.AdditionalDownloads.Marquee != artutil.ArtKindNone && artMarqueeDir != "" {
 				marqueeArtFileName := g.FsNameNoExt
-				// is cfw is ES based, use -marquee suffix to avoid conflicts with cover art
-				if cfw.GetCFW().IsBasedOnEmulationStation() {
+				// is cfw is ES based and keeps it beside the cover art, use -marquee suffix to avoid conflicts
+				if cfw.GetCFW().IsBasedOnEmulationStation() && artMarqueeDir == artDir {
 					marqueeArtFileName += "-marquee.png"
 				} else {
 					marqueeArtFileName += ".png"
@@ -493,8 +610,10 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 					artDownloads = append(artDownloads, artDownload{
 						URL:      marqueeURL,
 						Location: marqueeArtLocation,
+						GameID:   g.ID,
 						GameName: g.Name,
 						IsImage:  true,
+						Role:     imageutil.ArtRoleMarquee,
 					})
 				}
 			}
@@ -507,6 +626,7 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 					artDownloads = append(artDownloads, artDownload{
 						URL:      videoURL,
 						Location: videoLocation,
+						GameID:   g.ID,
 						GameName: g.Name,
 						IsImage:  false,
 					})
@@ -521,8 +641,10 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 					artDownloads = append(artDownloads, artDownload{
 						URL:      bezelURL,
 						Location: bezelArtLocation,
+						GameID:   g.ID,
 						GameName: g.Name,
 						IsImage:  true,
+						Role:     imageutil.ArtRoleBezel,
 					})
 				}
 			}
@@ -535,6 +657,7 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 					artDownloads = append(artDownloads, artDownload{
 						URL:      manualURL,
 						Location: manualLocation,
+						GameID:   g.ID,
 						GameName: g.Name,
 						IsImage:  false,
 					})
@@ -544,8 +667,8 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 			boxbackDir := config.GetBoxbackDirectory(gamePlatform)
 			if config.AdditionalDownloads.BoxBack && boxbackDir != "" {
 				boxbackArtFileName := g.FsNameNoExt
-				// is cfw is ES based, use -boxback suffix to avoid conflicts with cover art
-				if cfw.GetCFW().IsBasedOnEmulationStation() {
+				// is cfw is ES based and keeps it beside the cover art, use -boxback suffix to avoid conflicts
+				if cfw.GetCFW().IsBasedOnEmulationStation() && boxbackDir == artDir {
 					boxbackArtFileName += "-boxback.png"
 				} else {
 					boxbackArtFileName += ".png"
@@ -556,8 +679,10 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 					artDownloads = append(artDownloads, artDownload{
 						URL:      boxbackURL,
 						Location: boxbackArtLocation,
+						GameID:   g.ID,
 						GameName: g.Name,
 						IsImage:  true,
+						Role:     imageutil.ArtRoleBoxBack,
 					})
 				}
 			}
@@ -565,8 +690,8 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 			fanartDir := config.GetFanartDirectory(gamePlatform)
 			if config.AdditionalDownloads.Fanart && fanartDir != "" {
 				fanartFileName := g.FsNameNoExt
-				// is cfw is ES based, use -fanart suffix to avoid conflicts with cover art
-				if cfw.GetCFW().IsBasedOnEmulationStation() {
+				// is cfw is ES based and keeps it beside the cover art, use -fanart suffix to avoid conflicts
+				if cfw.GetCFW().IsBasedOnEmulationStation() && fanartDir == artDir {
 					fanartFileName += "-fanart.png"
 				} else {
 					fanartFileName += ".png"
@@ -577,8 +702,10 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 					artDownloads = append(artDownloads, artDownload{
 						URL:      fanartURL,
 						Location: fanartLocation,
+						GameID:   g.ID,
 						GameName: g.Name,
 						IsImage:  true,
+						Role:     imageutil.ArtRoleFanart,
 					})
 				}
 			}
@@ -587,7 +714,392 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 		gamesSummaries = append(gamesSummaries, gamelistRomEntry)
 	}
 
-	return downloads, artDownloads, gamesSummaries
+	return downloads, artDownloads, gamesSummaries, romURLs
+}
+
+// streamedArchive is a zip download that is extracted while it is being received.
+type streamedArchive struct {
+	Game     romm.Rom
+	Download gaba.Download
+	DestDir  string
+	Expected map[string]fileutil.ExpectedHashes
+}
+
+// splitStreamedDownloads pulls out the downloads that are zip archives about to be
+// extracted anyway: multi-file ROMs, and single-file zips when Archived Downloads is
+// set to uncompress. 7z archives need random access, so they stay regular downloads.
+func (s *DownloadScreen) splitStreamedDownloads(config internal.Config, platform romm.Platform, games []romm.Rom, downloads []gaba.Download, romURLs romDownloads) ([]gaba.Download, []streamedArchive) {
+	regular := make([]gaba.Download, 0, len(downloads))
+	var streamed []streamedArchive
+
+	for _, d := range downloads {
+		id, isROM := romURLs[d.URL]
+		idx := slices.IndexFunc(games, func(g romm.Rom) bool { return g.ID == id })
+		if !isROM || idx < 0 {
+			regular = append(regular, d)
+			continue
+		}
+		g := games[idx]
+
+		gamePlatform := platform
+		if platform.ID == 0 && g.PlatformID != 0 {
+			gamePlatform = romm.Platform{
+				ID:     g.PlatformID,
+				FSSlug: g.PlatformFSSlug,
+				Name:   g.PlatformDisplayName,
+			}
+		}
+		romDirectory := config.GetPlatformRomDirectory(gamePlatform)
+
+		switch {
+		case g.HasMultipleFiles:
+			expected := make(map[string]fileutil.ExpectedHashes, len(g.Files))
+			for _, f := range g.Files {
+				expected[fileutil.ExpectedKey(archivePath(g, f))] = fileutil.ExpectedHashes{
+					CRC32: f.CrcHash,
+					MD5:   f.Md5Hash,
+					SHA1:  f.Sha1Hash,
+				}
+			}
+			streamed = append(streamed, streamedArchive{
+				Game:     g,
+				Download: d,
+				DestDir:  filepath.Join(romDirectory, g.FsNameNoExt),
+				Expected: expected,
+			})
+		case config.UnzipDownloads && strings.ToLower(filepath.Ext(d.Location)) == ".zip":
+			streamed = append(streamed, streamedArchive{Game: g, Download: d, DestDir: romDirectory})
+		default:
+			regular = append(regular, d)
+		}
+	}
+
+	return regular, streamed
+}
+
+// runStreamedArchive streams one archive behind a progress bar that B cancels. A
+// cancelled archive is cleaned up before it returns gaba.ErrCancelled.
+func (s *DownloadScreen) runStreamedArchive(a streamedArchive, host romm.Host, headers map[string]string) ([]string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	progress := &atomic.Float64{}
+	done := make(chan struct{})
+	var files []string
+	var streamErr error
+
+	_, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "download_streaming", Other: "Downloading {{.Name}}..."}, map[string]interface{}{"Name": a.Game.Name}),
+		gaba.ProcessMessageOptions{
+			ShowThemeBackground: true,
+			ShowProgressBar:     true,
+			Progress:            progress,
+			CancelButton:        constants.VirtualButtonB,
+			FooterHelpItems: []gaba.FooterHelpItem{
+				{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_cancel", Other: "Cancel"}, nil)},
+			},
+		},
+		func() (struct{}, error) {
+			defer close(done)
+			files, streamErr = s.streamArchive(ctx, a, host, headers, progress)
+			return struct{}{}, streamErr
+		},
+	)
+	if errors.Is(err, gaba.ErrCancelled) {
+		// Abort the transfer and wait for the partial extraction to be removed.
+		cancel()
+		<-done
+		return nil, gaba.ErrCancelled
+	}
+	<-done
+	return files, streamErr
+}
+
+// newStreamClient builds the client for streamed archives. It bounds connecting and
+// waiting for the response, but not the transfer itself: a large archive can take
+// longer than any fixed timeout, so stalls are caught by an idle deadline instead.
+func newStreamClient(host romm.Host) *http.Client {
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: streamConnectTimeout}).DialContext,
+		TLSHandshakeTimeout:   streamConnectTimeout,
+		ResponseHeaderTimeout: streamResponseTimeout,
+	}
+	if host.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &http.Client{Transport: transport}
+}
+
+// streamArchive downloads a zip and extracts it on the fly. If the archive turns out
+// to need random access it is downloaded to its regular location and unzipped from there.
+func (s *DownloadScreen) streamArchive(ctx context.Context, a streamedArchive, host romm.Host, headers map[string]string, progress *atomic.Float64) ([]string, error) {
+	logger := gaba.GetLogger()
+
+	client := newStreamClient(host)
+
+	resp, err := s.openDownload(ctx, client, a.Download.URL, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	total := resp.ContentLength
+	if total <= 0 {
+		total = a.Game.FsSizeBytes
+	}
+
+	logger.Debug("Streaming archive extraction", "game", a.Game.Name, "dest", a.DestDir, "size", total)
+	files, err := fileutil.StreamUnzip(resp.Body, a.DestDir, total, progress, a.Expected)
+	resp.Body.Close()
+	if err == nil {
+		return files, nil
+	}
+
+	s.removeExtracted(a, files)
+	if !errors.Is(err, fileutil.ErrStreamUnsupported) {
+		logger.Error("Streaming extraction failed", "game", a.Game.Name, "error", err)
+		return nil, err
+	}
+
+	logger.Debug("Archive needs random access, falling back to a temporary file", "game", a.Game.Name, "path", a.Download.Location)
+	return s.downloadAndUnzip(ctx, client, a, headers, progress)
+}
+
+func (s *DownloadScreen) downloadAndUnzip(ctx context.Context, client *http.Client, a streamedArchive, headers map[string]string, progress *atomic.Float64) ([]string, error) {
+	resp, err := s.openDownload(ctx, client, a.Download.URL, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(a.Download.Location), 0755); err != nil {
+		return nil, err
+	}
+	out, err := os.Create(a.Download.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	total := resp.ContentLength
+	if total <= 0 {
+		total = a.Game.FsSizeBytes
+	}
+	progress.Store(0)
+	_, err = io.Copy(out, fileutil.ProgressReader(resp.Body, total, progress))
+	out.Close()
+	defer os.Remove(a.Download.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := fileutil.ZipFileNames(a.Download.Location)
+	if err != nil {
+		return nil, err
+	}
+	if err := fileutil.Unzip(a.Download.Location, a.DestDir, progress); err != nil {
+		s.removeExtracted(a, files)
+		return nil, err
+	}
+	if err := fileutil.VerifyFiles(a.DestDir, files, a.Expected); err != nil {
+		gaba.GetLogger().Error("Extracted archive failed verification", "game", a.Game.Name, "error", err)
+		s.removeExtracted(a, files)
+		return nil, err
+	}
+	return files, nil
+}
+
+// archivePath returns where f sits inside the archive RomM serves for the multi-file
+// ROM g: its path below the ROM's folder, or just its name when that can't be told.
+func archivePath(g romm.Rom, f romm.RomFile) string {
+	full := f.FullPath
+	if full == "" {
+		full = path.Join(f.FilePath, f.FileName)
+	}
+	if g.FullPath != "" {
+		if rel, ok := strings.CutPrefix(full, strings.TrimSuffix(g.FullPath, "/")+"/"); ok {
+			return rel
+		}
+	}
+	return f.FileName
+}
+
+// openDownload starts a GET whose body fails with fileutil.ErrStalled once no data has
+// arrived for streamIdleTimeout. Cancelling ctx aborts the transfer.
+func (s *DownloadScreen) openDownload(ctx context.Context, client *http.Client, url string, headers map[string]string) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+	resp.Body = &cancelOnClose{ReadCloser: fileutil.WithIdleTimeout(resp.Body, streamIdleTimeout, cancel), cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases a request's context once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// removeExtracted cleans up after a failed extraction. Multi-file ROMs own their
+// directory; single-file archives share the ROM directory, so only their files go.
+func (s *DownloadScreen) removeExtracted(a streamedArchive, files []string) {
+	if a.Game.HasMultipleFiles {
+		os.RemoveAll(a.DestDir)
+		return
+	}
+	for _, f := range files {
+		os.Remove(filepath.Join(a.DestDir, filepath.FromSlash(f)))
+	}
+}
+
+// applyPatch downloads the selected patch, applies it to the freshly downloaded ROM
+// and writes the result beside it under a name that says which patch it carries.
+// The returned gamelist entry describes the patched ROM.
+func (s *DownloadScreen) applyPatch(input DownloadInput, g romm.Rom, entries []gamelist.RomGameEntry, headers map[string]string) (gamelist.RomGameEntry, error) {
+	logger := gaba.GetLogger()
+
+	patchFileIdx := slices.IndexFunc(g.Files, func(f romm.RomFile) bool { return f.ID == input.Selection.PatchID })
+	if patchFileIdx < 0 {
+		return gamelist.RomGameEntry{}, fmt.Errorf("patch file %d not found", input.Selection.PatchID)
+	}
+	patchFile := g.Files[patchFileIdx]
+
+	baseFile, ok := patchBaseFile(g)
+	if idx := slices.IndexFunc(g.Files, func(f romm.RomFile) bool { return f.ID == input.Selection.FileID }); idx >= 0 && isPatchBase(g.Files[idx]) {
+		baseFile, ok = g.Files[idx], true
+	}
+	if !ok {
+		return gamelist.RomGameEntry{}, errors.New("no ROM to apply the patch to")
+	}
+
+	gamePlatform := input.Platform
+	if input.Platform.ID == 0 && g.PlatformID != 0 {
+		gamePlatform = romm.Platform{
+			ID:     g.PlatformID,
+			FSSlug: g.PlatformFSSlug,
+			Name:   g.PlatformDisplayName,
+		}
+	}
+	romDirectory := input.Config.GetPlatformRomDirectory(gamePlatform)
+
+	source, err := os.ReadFile(filepath.Join(romDirectory, baseFile.FileName))
+	if err != nil {
+		return gamelist.RomGameEntry{}, fmt.Errorf("failed to read ROM: %w", err)
+	}
+	if baseFile.CrcHash != "" && !strings.EqualFold(baseFile.CrcHash, fmt.Sprintf("%08x", crc32.ChecksumIEEE(source))) {
+		return gamelist.RomGameEntry{}, patch.ErrSourceMismatch
+	}
+
+	client := &http.Client{Timeout: input.Config.DownloadTimeout.Duration()}
+	if input.Host.InsecureSkipVerify {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+	patchURL, _ := url.JoinPath(input.Host.URL(), "/api/roms/", strconv.Itoa(g.ID), "content", patchFile.FileName)
+	patchURL += "?" + url.Values{"file_ids": {strconv.Itoa(patchFile.ID)}}.Encode()
+
+	resp, err := s.openDownload(context.Background(), client, patchURL, headers)
+	if err != nil {
+		return gamelist.RomGameEntry{}, fmt.Errorf("failed to download patch: %w", err)
+	}
+	patchData, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return gamelist.RomGameEntry{}, fmt.Errorf("failed to download patch: %w", err)
+	}
+
+	patched, err := patch.Apply(source, patchData)
+	if err != nil {
+		return gamelist.RomGameEntry{}, err
+	}
+
+	patchedName := patch.PatchedFileName(baseFile.FileName, patchFile.FileName)
+	patchedPath := filepath.Join(romDirectory, patchedName)
+	tmpPath := patchedPath + ".tmp"
+	if err := os.WriteFile(tmpPath, patched, 0644); err != nil {
+		os.Remove(tmpPath)
+		return gamelist.RomGameEntry{}, fmt.Errorf("failed to write patched ROM: %w", err)
+	}
+
+	// Read the file back so a bad write to the SD card is caught before it is used.
+	written, err := fileutil.ComputeCRC32(tmpPath)
+	if err != nil || !strings.EqualFold(written, fmt.Sprintf("%08x", crc32.ChecksumIEEE(patched))) {
+		os.Remove(tmpPath)
+		return gamelist.RomGameEntry{}, fmt.Errorf("patched ROM failed verification after writing: %w", patch.ErrTargetMismatch)
+	}
+	if err := os.Rename(tmpPath, patchedPath); err != nil {
+		os.Remove(tmpPath)
+		return gamelist.RomGameEntry{}, fmt.Errorf("failed to write patched ROM: %w", err)
+	}
+
+	logger.Info("Applied patch", "game", g.Name, "patch", patchFile.FileName, "format", patch.DetectFormat(patchData), "output", patchedPath)
+
+	patchedGame := g
+	patchedGame.Name = fmt.Sprintf("%s (%s)", g.Name, strings.TrimSuffix(patchFile.FileName, filepath.Ext(patchFile.FileName)))
+	patchedGame.FsName = patchedName
+	patchedGame.FsNameNoExt = strings.TrimSuffix(patchedName, filepath.Ext(patchedName))
+	patchedGame.Md5Hash = fmt.Sprintf("%x", md5.Sum(patched))
+	patchedGame.RetroAchievementsHash = ""
+
+	entry := gamelist.RomGameEntry{
+		Game:              &patchedGame,
+		Platform:          &gamePlatform,
+		RomDirectory:      romDirectory,
+		GamelistDirectory: input.Config.GetPlatformGamelistDirectory(gamePlatform),
+		GamePath:          patchedPath,
+	}
+	if idx := slices.IndexFunc(entries, func(e gamelist.RomGameEntry) bool { return e.Game.ID == g.ID }); idx >= 0 {
+		entry.ArtLocation = entries[idx].ArtLocation
+		entry.Platform = entries[idx].Platform
+	}
+	return entry, nil
+}
+
+// archiveGamePath picks the file a frontend should launch from an extracted archive,
+// preferring an .m3u playlist when the archive held several files.
+func archiveGamePath(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	if len(files) > 1 {
+		for _, f := range files {
+			if strings.ToLower(filepath.Ext(f)) == ".m3u" {
+				return f
+			}
+		}
+	}
+	return files[0]
+}
+
+func romFileNames(g romm.Rom) []string {
+	names := make([]string, 0, len(g.Files))
+	for _, f := range g.Files {
+		names = append(names, f.FileName)
+	}
+	return names
 }
 
 // resolveExtractedGamePath returns the best path for a multi-file ROM after extraction.
@@ -626,17 +1138,19 @@ func resolveExtractedGamePath(romDirectory, extractDir, fsNameNoExt string) stri
 	return extractDir
 }
 
-func (s *DownloadScreen) downloadArt(artDownloads []artDownload, downloadedGames []romm.Rom, headers map[string]string, progress *atomic.Float64, insecureSkipVerify bool) {
+// downloadArt fetches the art of downloadedGames, rendering composites on the device,
+// and returns how many images were written.
+func (s *DownloadScreen) downloadArt(artDownloads []artDownload, downloadedGames []romm.Rom, headers map[string]string, progress *atomic.Float64, insecureSkipVerify bool) int {
 	logger := gaba.GetLogger()
 
-	downloadedGameNames := make(map[string]bool)
+	downloadedGameIDs := make(map[int]bool)
 	for _, g := range downloadedGames {
-		downloadedGameNames[g.Name] = true
+		downloadedGameIDs[g.ID] = true
 	}
 
 	totalArt := 0
 	for _, art := range artDownloads {
-		if downloadedGameNames[art.GameName] {
+		if downloadedGameIDs[art.GameID] {
 			totalArt++
 		}
 	}
@@ -644,9 +1158,10 @@ func (s *DownloadScreen) downloadArt(artDownloads []artDownload, downloadedGames
 	successCount := 0
 	failCount := 0
 	processedCount := 0
+	profile := cfw.GetCFW().ArtProfile()
 
 	for _, art := range artDownloads {
-		if !downloadedGameNames[art.GameName] {
+		if !downloadedGameIDs[art.GameID] {
 			continue
 		}
 
@@ -661,6 +1176,22 @@ func (s *DownloadScreen) downloadArt(artDownloads []artDownload, downloadedGames
 			continue
 		}
 
+		if art.Composite != nil {
+			err := s.renderComposite(art, headers, insecureSkipVerify)
+			if err == nil {
+				if err := profile.Process(art.Location, art.Role); err != nil {
+					logger.Warn("Failed to process composite art", "game", art.GameName, "location", art.Location, "error", err)
+				}
+				successCount++
+				processedCount++
+				if totalArt > 0 {
+					progress.Store(float64(processedCount) / float64(totalArt))
+				}
+				continue
+			}
+			logger.Warn("Failed to render composite art, using the cover instead", "game", art.GameName, "error", err)
+		}
+
 		req, err := http.NewRequest("GET", art.URL, nil)
 		if err != nil {
 			logger.Warn("Failed to create art request", "game", art.GameName, "error", err)
@@ -732,7 +1263,7 @@ func (s *DownloadScreen) downloadArt(artDownloads []artDownload, downloadedGames
 		}
 
 		if art.IsImage {
-			if err := imageutil.ProcessArtImage(art.Location); err != nil {
+			if err := profile.Process(art.Location, art.Role); err != nil {
 				logger.Warn("Failed to process art image", "game", art.GameName, "location", art.Location, "error", err, "url", art.URL)
 				os.Remove(art.Location)
 				failCount++
@@ -751,4 +1282,88 @@ func (s *DownloadScreen) downloadArt(artDownloads []artDownload, downloadedGames
 			progress.Store(float64(processedCount) / float64(totalArt))
 		}
 	}
+	return successCount
+}
+
+// buildCompositeArt returns the pieces of a mix image for g, or nil unless the art
+// kind is set to Composite.
+func buildCompositeArt(config internal.Config, host romm.Host, platform romm.Platform, g romm.Rom) *compositeArt {
+	if config.ArtKind != artutil.ArtKindComposite {
+		return nil
+	}
+
+	logoURL := g.GetLogoURL(host)
+	if logoURL == "" {
+		logoURL = g.GetMarqueeURL(host)
+	}
+
+	piece := func(name, url string) artPiece {
+		return artPiece{URL: url, Path: cache.GetArtPiecePath(platform.FSSlug, g.ID, name, url)}
+	}
+	return &compositeArt{
+		Template:       cfw.GetCFW().CompositeTemplate(),
+		PlatformFSSlug: platform.FSSlug,
+		Background:     piece("background", g.GetScreenshotURL(host)),
+		Box:            piece("box", g.GetArtworkURL(artutil.ArtKindBox2D, host)),
+		Logo:           piece("logo", logoURL),
+	}
+}
+
+// renderComposite downloads any pieces not cached yet and renders them into
+// art.Location. Pieces that cannot be fetched are left out of the image.
+func (s *DownloadScreen) renderComposite(art artDownload, headers map[string]string, insecureSkipVerify bool) error {
+	logger := gaba.GetLogger()
+
+	client := &http.Client{Timeout: romm.DefaultClientTimeout}
+	if insecureSkipVerify {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+
+	c := art.Composite
+	paths := make([]string, 3)
+	fetched := false
+	for i, piece := range []artPiece{c.Background, c.Box, c.Logo} {
+		if piece.URL == "" {
+			continue
+		}
+		if !fileutil.FileExists(piece.Path) {
+			// The artwork changed on the server, so earlier versions are stale.
+			cache.RemoveStaleArtPieces(piece.Path)
+			if err := s.downloadArtPiece(client, piece, headers); err != nil {
+				logger.Debug("Skipping composite piece", "game", art.GameName, "url", piece.URL, "error", err)
+				continue
+			}
+			fetched = true
+		}
+		paths[i] = piece.Path
+	}
+	if fetched {
+		cache.GetCacheManager().TrackArtwork(romm.Rom{ID: art.GameID, PlatformFSSlug: c.PlatformFSSlug})
+	}
+
+	return imageutil.CompositeFiles(c.Template, paths[0], paths[1], paths[2], art.Location)
+}
+
+func (s *DownloadScreen) downloadArtPiece(client *http.Client, piece artPiece, headers map[string]string) error {
+	resp, err := s.openDownload(context.Background(), client, piece.URL, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(piece.Path), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(piece.Path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, resp.Body)
+	out.Close()
+	if err != nil {
+		os.Remove(piece.Path)
+	}
+	return err
 }
@@ -2,10 +2,14 @@ package ui
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"grout/internal"
 	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
 
 func TestMain(m *testing.M) {
@@ -43,7 +47,7 @@ func TestBuildDownloads_EmptyFiles(t *testing.T) {
 		}
 	}()
 
-	downloads, artDownloads, gamelistEntries := s.buildDownloads(config, host, platform, games, 0)
+	downloads, artDownloads, gamelistEntries, _ := s.buildDownloads(config, host, platform, games, FileSelection{})
 
 	if len(downloads) != 0 {
 		t.Errorf("expected 0 downloads when Files is empty, got %d", len(downloads))
@@ -77,7 +81,7 @@ func TestBuildDownloads_SingleFile_HappyPath(t *testing.T) {
 		},
 	}
 
-	downloads, _, gamelistEntries := s.buildDownloads(config, host, platform, games, 0)
+	downloads, _, gamelistEntries, _ := s.buildDownloads(config, host, platform, games, FileSelection{})
 
 	if len(downloads) != 1 {
 		t.Fatalf("expected 1 download, got %d", len(downloads))
@@ -89,3 +93,96 @@ func TestBuildDownloads_SingleFile_HappyPath(t *testing.T) {
 		t.Error("expected non-empty download URL")
 	}
 }
+
+// TestBuildDownloads_Extras checks that add-on files are routed to their category
+// directory and kept out of a multi-file game's archive.
+func TestBuildDownloads_Extras(t *testing.T) {
+	s := NewDownloadScreen()
+	config := internal.Config{}
+	host := romm.Host{RootURI: "http://example.invalid"}
+	platform := romm.Platform{ID: 1, FSSlug: "switch", Name: "Nintendo Switch"}
+
+	dlc := romm.RomFile{ID: 3, RomID: 7, FileName: "Game DLC.nsp", Category: romm.FileCategoryDLC}
+	games := []romm.Rom{
+		{
+			ID:               7,
+			Name:             "Game",
+			FsName:           "Game",
+			FsNameNoExt:      "Game",
+			HasMultipleFiles: true,
+			Files: []romm.RomFile{
+				{ID: 1, RomID: 7, FileName: "Game.nsp"},
+				{ID: 2, RomID: 7, FileName: "Game v1.1.nsp", Category: romm.FileCategoryUpdate},
+				dlc,
+			},
+		},
+	}
+
+	downloads, _, gamelistEntries, _ := s.buildDownloads(config, host, platform, games, FileSelection{Extras: []romm.RomFile{dlc}})
+	if len(downloads) != 2 {
+		t.Fatalf("expected 2 downloads, got %d", len(downloads))
+	}
+	if len(gamelistEntries) != 1 {
+		t.Fatalf("expected 1 gamelist entry, got %d", len(gamelistEntries))
+	}
+
+	romDir := config.GetPlatformRomDirectory(platform)
+	if want := filepath.Join(romDir, ".dlc", "Game DLC.nsp"); downloads[0].Location != want {
+		t.Errorf("DLC location = %q, want %q", downloads[0].Location, want)
+	}
+	if !strings.HasSuffix(downloads[1].URL, "?file_ids=1") {
+		t.Errorf("game archive URL should only request game files, got %q", downloads[1].URL)
+	}
+
+	downloads, _, gamelistEntries, _ = s.buildDownloads(config, host, platform, games, FileSelection{Extras: []romm.RomFile{dlc}, ExtrasOnly: true})
+	if len(downloads) != 1 || len(gamelistEntries) != 0 {
+		t.Errorf("extras only: got %d downloads and %d gamelist entries, want 1 and 0", len(downloads), len(gamelistEntries))
+	}
+}
+
+// TestBuildDownloads_MatchesGamesByID checks that two games sharing a display name
+// are told apart when download results are matched back to them.
+func TestBuildDownloads_MatchesGamesByID(t *testing.T) {
+	s := NewDownloadScreen()
+	config := internal.Config{}
+	host := romm.Host{RootURI: "http://example.invalid"}
+	platform := romm.Platform{ID: 1, FSSlug: "gba", Name: "Game Boy Advance"}
+
+	games := []romm.Rom{
+		{ID: 1, Name: "Tetris", FsName: "Tetris (USA).gba", FsNameNoExt: "Tetris (USA)",
+			Files: []romm.RomFile{{ID: 10, FileName: "Tetris (USA).gba"}}},
+		{ID: 2, Name: "Tetris", FsName: "Tetris (Japan).gba", FsNameNoExt: "Tetris (Japan)",
+			Files: []romm.RomFile{{ID: 20, FileName: "Tetris (Japan).gba"}}},
+	}
+
+	downloads, _, _, romURLs := s.buildDownloads(config, host, platform, games, FileSelection{})
+	if len(downloads) != 2 {
+		t.Fatalf("expected 2 downloads, got %d", len(downloads))
+	}
+
+	res := &gaba.DownloadResult{Completed: []gaba.Download{downloads[1]}}
+	if romURLs.completed(res, games[0]) {
+		t.Error("the USA release was reported downloaded after only the Japanese one finished")
+	}
+	if !romURLs.completed(res, games[1]) {
+		t.Error("the Japanese release was not reported downloaded")
+	}
+}
+
+func TestArchivePath(t *testing.T) {
+	g := romm.Rom{FullPath: "roms/psx/Game"}
+	cases := []struct {
+		file romm.RomFile
+		want string
+	}{
+		{romm.RomFile{FileName: "track01.bin", FullPath: "roms/psx/Game/disc1/track01.bin"}, "disc1/track01.bin"},
+		{romm.RomFile{FileName: "track01.bin", FilePath: "roms/psx/Game/disc2"}, "disc2/track01.bin"},
+		{romm.RomFile{FileName: "Game.cue", FullPath: "roms/psx/Game/Game.cue"}, "Game.cue"},
+		{romm.RomFile{FileName: "Game.cue", FullPath: "elsewhere/Game.cue"}, "Game.cue"},
+	}
+	for _, c := range cases {
+		if got := archivePath(g, c.file); got != c.want {
+			t.Errorf("archivePath(%+v) = %q, want %q", c.file, got, c.want)
+		}
+	}
+}
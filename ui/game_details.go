@@ -7,6 +7,8 @@ import (
 	"grout/internal"
 	"grout/internal/fileutil"
 	"grout/internal/imageutil"
+	"grout/internal/patch"
+	"grout/internal/romset"
 	"grout/internal/stringutil"
 	"io"
 	"net/http"
@@ -30,13 +32,16 @@ type GameDetailsInput struct {
 	Host     romm.Host
 	Platform romm.Platform
 	Game     romm.Rom
+	Versions []romm.Rom // Other dumps of the same game, preferred first; only set in one-game-one-ROM mode
 }
 
 type GameDetailsOutput struct {
 	Action            GameDetailsAction
 	DownloadRequested bool
 	SelectedFileID    int
+	SelectedPatchID   int // Patch file to apply after downloading; 0 for none
 	Game              romm.Rom
+	Versions          []romm.Rom
 	Platform          romm.Platform
 }
 
@@ -51,10 +56,14 @@ func (s *GameDetailsScreen) Draw(input GameDetailsInput) (GameDetailsOutput, err
 	output := GameDetailsOutput{
 		Action:   GameDetailsActionBack,
 		Game:     input.Game,
+		Versions: input.Versions,
 		Platform: input.Platform,
 	}
 
 	hasMultipleFiles := input.Game.HasNestedSingleFile && len(input.Game.Files) > 1
+	hasVersions := len(input.Versions) > 1
+	hasPatches := len(patchFiles(input.Game)) > 0
+	hasDropdown := hasMultipleFiles || hasVersions || hasPatches
 	downloadText := i18n.Localize(&goi18n.Message{ID: "button_download", Other: "Download"}, nil)
 	redownloadText := i18n.Localize(&goi18n.Message{ID: "button_redownload", Other: "Redownload"}, nil)
 
@@ -64,14 +73,32 @@ func (s *GameDetailsScreen) Draw(input GameDetailsInput) (GameDetailsOutput, err
 		initialDownloadText = redownloadText
 	}
 
-	// Create dynamic help text for multi-file games
+	// Create dynamic help text for games with a file or version dropdown
 	var dynamicDownloadText *atomic.String
-	if hasMultipleFiles {
+	if hasDropdown {
 		dynamicDownloadText = atomic.NewString(initialDownloadText)
 	}
 
 	sections := s.buildSections(input)
 
+	// Set OnChange callback for the ROM version dropdown to update footer dynamically
+	if hasVersions && dynamicDownloadText != nil {
+		for i := range sections {
+			if sections[i].DropdownID == "rom_version" {
+				sections[i].OnChange = func(option gaba.DropdownOption) {
+					if version, ok := findVersion(input.Versions, option.Value); ok {
+						if version.IsDownloaded(input.Config) {
+							dynamicDownloadText.Store(redownloadText)
+						} else {
+							dynamicDownloadText.Store(downloadText)
+						}
+					}
+				}
+				break
+			}
+		}
+	}
+
 	// Set OnChange callback for the file version dropdown to update footer dynamically
 	if hasMultipleFiles && dynamicDownloadText != nil {
 		romDirectory := input.Config.GetPlatformRomDirectory(input.Platform)
@@ -101,7 +128,7 @@ func (s *GameDetailsScreen) Draw(input GameDetailsInput) (GameDetailsOutput, err
 	options.Sections = sections
 	options.ShowThemeBackground = false
 	options.ShowScrollbar = true
-	if hasMultipleFiles {
+	if hasDropdown {
 		options.ConfirmButton = constants.VirtualButtonX
 	}
 	if !internal.IsKidModeEnabled() {
@@ -110,7 +137,7 @@ func (s *GameDetailsScreen) Draw(input GameDetailsInput) (GameDetailsOutput, err
 	}
 
 	downloadButton := "A"
-	if hasMultipleFiles {
+	if hasDropdown {
 		downloadButton = "X"
 	}
 
@@ -140,15 +167,35 @@ func (s *GameDetailsScreen) Draw(input GameDetailsInput) (GameDetailsOutput, err
 	if result.Action == gaba.DetailActionConfirmed {
 		output.Action = GameDetailsActionDownload
 		output.DownloadRequested = true
-		// Check if a specific file was selected from the dropdown
+		// Check if a specific version or file was selected from the dropdowns
+		for _, selection := range result.DropdownSelections {
+			if selection.ID == "rom_version" {
+				if version, ok := findVersion(input.Versions, selection.Option.Value); ok {
+					output.Game = version
+				}
+			}
+		}
 		for _, selection := range result.DropdownSelections {
-			if selection.ID == "file_version" {
+			// File IDs only apply to the game the dropdown was built for
+			if selection.ID == "file_version" && output.Game.ID == input.Game.ID {
 				if fileID, err := strconv.Atoi(selection.Option.Value); err == nil {
 					output.SelectedFileID = fileID
 				}
 				break
 			}
 		}
+		for _, selection := range result.DropdownSelections {
+			if selection.ID == "rom_patch" && output.Game.ID == input.Game.ID {
+				output.SelectedPatchID, _ = strconv.Atoi(selection.Option.Value)
+				break
+			}
+		}
+		// A patch needs an unpatched ROM to apply to, never another patch.
+		if output.SelectedPatchID > 0 && !isPatchBaseID(output.Game, output.SelectedFileID) {
+			if base, ok := patchBaseFile(output.Game); ok {
+				output.SelectedFileID = base.ID
+			}
+		}
 		return output, nil
 	}
 
@@ -172,6 +219,31 @@ func (s *GameDetailsScreen) buildSections(input GameDetailsInput) []gaba.Section
 		logger.Debug("No cover image available", "game", game.Name)
 	}
 
+	// Show version selection dropdown when other dumps of this game exist
+	if len(input.Versions) > 1 {
+		versionOptions := make([]gaba.DropdownOption, len(input.Versions))
+		selected := 0
+		for i, version := range input.Versions {
+			label := romset.VersionLabel(version)
+			if version.IsDownloaded(input.Config) {
+				label = constants.Download + " " + label
+			}
+			if version.ID == game.ID {
+				selected = i
+			}
+			versionOptions[i] = gaba.DropdownOption{
+				Label: label,
+				Value: strconv.Itoa(version.ID),
+			}
+		}
+		sections = append(sections, gaba.NewDropdownSection(
+			i18n.Localize(&goi18n.Message{ID: "game_details_rom_version", Other: "Version"}, nil),
+			"rom_version",
+			versionOptions,
+			selected,
+		))
+	}
+
 	// Show file selection dropdown for games with nested single file (multiple versions)
 	if game.HasNestedSingleFile && len(game.Files) > 1 {
 		fileOptions := make([]gaba.DropdownOption, len(game.Files))
@@ -195,6 +267,26 @@ func (s *GameDetailsScreen) buildSections(input GameDetailsInput) []gaba.Section
 		))
 	}
 
+	// Offer translation and hack patches that can be applied on the device
+	if patches := patchFiles(game); len(patches) > 0 {
+		patchOptions := []gaba.DropdownOption{{
+			Label: i18n.Localize(&goi18n.Message{ID: "game_details_patch_none", Other: "None"}, nil),
+			Value: "0",
+		}}
+		for _, file := range patches {
+			patchOptions = append(patchOptions, gaba.DropdownOption{
+				Label: file.FileName,
+				Value: strconv.Itoa(file.ID),
+			})
+		}
+		sections = append(sections, gaba.NewDropdownSection(
+			i18n.Localize(&goi18n.Message{ID: "game_details_patch", Other: "Patch"}, nil),
+			"rom_patch",
+			patchOptions,
+			0,
+		))
+	}
+
 	if game.Summary != "" {
 		sections = append(sections, gaba.NewDescriptionSection("", game.Summary))
 	}
@@ -280,11 +372,85 @@ func (s *GameDetailsScreen) buildSections(input GameDetailsInput) []gaba.Section
 	return sections
 }
 
+func findVersion(versions []romm.Rom, id string) (romm.Rom, bool) {
+	romID, err := strconv.Atoi(id)
+	if err != nil {
+		return romm.Rom{}, false
+	}
+	for _, version := range versions {
+		if version.ID == romID {
+			return version, true
+		}
+	}
+	return romm.Rom{}, false
+}
+
+// patchFiles returns the IPS/BPS/UPS files RomM files as patches, translations or hacks,
+// provided there is also an unarchived ROM among the game's files to apply them to.
+func patchFiles(game romm.Rom) []romm.RomFile {
+	if game.HasMultipleFiles {
+		return nil
+	}
+	if _, ok := patchBaseFile(game); !ok {
+		return nil
+	}
+	var patches []romm.RomFile
+	for _, f := range game.Files {
+		if isPatch(f) {
+			patches = append(patches, f)
+		}
+	}
+	return patches
+}
+
+// isPatch reports whether f is a patch file in one of the categories patches are filed
+// under. A .ips among a game's cheats, say, isn't offered as a patch.
+func isPatch(f romm.RomFile) bool {
+	switch f.GetCategory() {
+	case romm.FileCategoryPatch, romm.FileCategoryTranslation, romm.FileCategoryHack:
+		return patch.IsPatchFile(f.FileName)
+	default:
+		return false
+	}
+}
+
+// patchBaseFile returns the first file of a game that a patch can be applied to.
+func patchBaseFile(game romm.Rom) (romm.RomFile, bool) {
+	for _, f := range game.Files {
+		if isPatchBase(f) {
+			return f, true
+		}
+	}
+	return romm.RomFile{}, false
+}
+
+func isPatchBaseID(game romm.Rom, fileID int) bool {
+	for _, f := range game.Files {
+		if f.ID == fileID {
+			return isPatchBase(f)
+		}
+	}
+	return false
+}
+
+// isPatchBase reports whether f is a plain ROM rather than a patch, an add-on or an archive.
+func isPatchBase(f romm.RomFile) bool {
+	switch f.GetCategory() {
+	case romm.FileCategoryGame, romm.FileCategoryHack, romm.FileCategoryTranslation, romm.FileCategoryDemo:
+	default:
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(f.FileName))
+	return !patch.IsPatchFile(f.FileName) && ext != ".zip" && ext != ".7z"
+}
+
 // getCoverImagePath returns the path to the cover image, using cache if available
 func (s *GameDetailsScreen) getCoverImagePath(config *internal.Config, host romm.Host, game romm.Rom) string {
 	logger := gaba.GetLogger()
 
 	// First, check if artwork is in the cache
+	cm := cache.GetCacheManager()
+	cm.RecordArtworkAccess([]romm.Rom{game})
 	if cache.ArtworkExists(game.PlatformFSSlug, game.ID) {
 		cachePath := cache.GetArtworkCachePath(game.PlatformFSSlug, game.ID)
 		logger.Debug("Using cached artwork for game details", "game", game.Name)
@@ -300,6 +466,7 @@ func (s *GameDetailsScreen) getCoverImagePath(config *internal.Config, host romm
 			cachePath := cache.GetArtworkCachePath(game.PlatformFSSlug, game.ID)
 			if err := os.WriteFile(cachePath, imageData, 0644); err == nil {
 				imageutil.ProcessArtImage(cachePath)
+				cm.TrackArtwork(game)
 				return cachePath
 			}
 		}
@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"grout/romm"
+)
+
+func TestPatchFiles_FiltersByCategory(t *testing.T) {
+	game := romm.Rom{Files: []romm.RomFile{
+		{ID: 1, FileName: "Game.sfc", Category: romm.FileCategoryGame},
+		{ID: 2, FileName: "Fix.ips"},
+		{ID: 3, FileName: "English.bps", Category: romm.FileCategoryTranslation},
+		{ID: 4, FileName: "Hard Mode.ups", Category: romm.FileCategoryHack},
+		{ID: 5, FileName: "Infinite Lives.ips", Category: romm.FileCategoryCheat},
+		{ID: 6, FileName: "Notes.txt", Category: romm.FileCategoryPatch},
+	}}
+
+	var got []int
+	for _, f := range patchFiles(game) {
+		got = append(got, f.ID)
+	}
+	if want := []int{2, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("patchFiles = %v, want %v", got, want)
+	}
+}
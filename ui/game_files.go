@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"path/filepath"
+
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	icons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type GameFilesInput struct {
+	Config    *internal.Config
+	Host      romm.Host
+	Platform  romm.Platform
+	Game      romm.Rom
+	Selection FileSelection
+}
+
+type GameFilesOutput struct {
+	Action    GameFilesAction
+	Platform  romm.Platform
+	Game      romm.Rom
+	Selection FileSelection
+}
+
+// gameFileItem is a row on the file selection screen; a zero File stands for the game itself.
+type gameFileItem struct {
+	File   romm.RomFile
+	IsGame bool
+}
+
+type GameFilesScreen struct{}
+
+func NewGameFilesScreen() *GameFilesScreen {
+	return &GameFilesScreen{}
+}
+
+func (s *GameFilesScreen) Draw(input GameFilesInput) (GameFilesOutput, error) {
+	output := GameFilesOutput{
+		Action:    GameFilesActionBack,
+		Platform:  input.Platform,
+		Game:      input.Game,
+		Selection: input.Selection,
+	}
+
+	menuItems := []gaba.MenuItem{{
+		Text:     fmt.Sprintf("[%s] %s", FileCategoryLabel(romm.FileCategoryGame), input.Game.Name),
+		Selected: true,
+		Metadata: gameFileItem{IsGame: true},
+	}}
+
+	for _, file := range input.Game.ExtraFiles() {
+		label := fmt.Sprintf("[%s] %s", FileCategoryLabel(file.GetCategory()), file.FileName)
+		categoryDir := input.Config.GetFileCategoryDirectory(input.Platform, file.GetCategory())
+		if fileutil.FileExists(filepath.Join(categoryDir, file.FileName)) {
+			label = icons.Download + " " + label
+		}
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     label,
+			Metadata: gameFileItem{File: file},
+		})
+	}
+
+	options := gaba.DefaultListOptions(input.Game.Name, menuItems)
+	options.UseSmallTitle = true
+	options.InitialMultiSelectMode = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		FooterBack(),
+		{ButtonName: icons.Start, HelpText: i18n.Localize(&goi18n.Message{ID: "button_download", Other: "Download"}, nil), IsConfirmButton: true},
+	}
+	options.StatusBar = StatusBar()
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		if errors.Is(err, gaba.ErrCancelled) {
+			return output, nil
+		}
+		return output, err
+	}
+
+	if sel.Action != gaba.ListActionSelected || len(sel.Selected) == 0 {
+		return output, nil
+	}
+
+	output.Selection.Extras = nil
+	output.Selection.ExtrasOnly = true
+	for _, idx := range sel.Selected {
+		item := sel.Items[idx].Metadata.(gameFileItem)
+		if item.IsGame {
+			output.Selection.ExtrasOnly = false
+			continue
+		}
+		output.Selection.Extras = append(output.Selection.Extras, item.File)
+	}
+
+	output.Action = GameFilesActionDownload
+	return output, nil
+}
+
+// FileCategoryLabel returns the localized name of a RomM file category.
+func FileCategoryLabel(category romm.FileCategory) string {
+	switch category {
+	case romm.FileCategoryDLC:
+		return i18n.Localize(&goi18n.Message{ID: "file_category_dlc", Other: "DLC"}, nil)
+	case romm.FileCategoryUpdate:
+		return i18n.Localize(&goi18n.Message{ID: "file_category_update", Other: "Update"}, nil)
+	case romm.FileCategoryHack:
+		return i18n.Localize(&goi18n.Message{ID: "file_category_hack", Other: "Hack"}, nil)
+	case romm.FileCategoryTranslation:
+		return i18n.Localize(&goi18n.Message{ID: "file_category_translation", Other: "Translation"}, nil)
+	case romm.FileCategoryManual:
+		return i18n.Localize(&goi18n.Message{ID: "file_category_manual", Other: "Manual"}, nil)
+	case romm.FileCategoryPatch:
+		return i18n.Localize(&goi18n.Message{ID: "file_category_patch", Other: "Patch"}, nil)
+	case romm.FileCategoryDemo:
+		return i18n.Localize(&goi18n.Message{ID: "file_category_demo", Other: "Demo"}, nil)
+	case romm.FileCategoryCheat:
+		return i18n.Localize(&goi18n.Message{ID: "file_category_cheat", Other: "Cheat"}, nil)
+	default:
+		return i18n.Localize(&goi18n.Message{ID: "file_category_game", Other: "Game"}, nil)
+	}
+}
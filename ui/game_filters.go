@@ -49,7 +49,10 @@ var filterCategories = []filterCategory{
 	{"filter_tag", "Tag", "tags", "game_tags", "tag_id"},
 }
 
-const platformCatIdx = -1
+const (
+	platformCatIdx = -1
+	sortCatIdx     = -2
+)
 
 func isCollection(input GameFiltersInput) bool {
 	return input.Collection.ID != 0 || input.Collection.VirtualID != ""
@@ -121,6 +124,11 @@ func (s *GameFiltersScreen) buildMenuItems(cm *cache.Manager, platformID int, in
 	var items []gaba.ItemWithOptions
 	var activeCats []int
 
+	if _, played := cm.PlayHistoryCounts(); played > 0 {
+		items = append(items, buildSortItem(current.Sort))
+		activeCats = append(activeCats, sortCatIdx)
+	}
+
 	if isUnifiedCollection(input) {
 		platforms, err := cm.GetCollectionPlatforms(input.Collection, searchFilter)
 		if err == nil && len(platforms) > 0 {
@@ -176,6 +184,28 @@ func (s *GameFiltersScreen) buildMenuItems(cm *cache.Manager, platformID int, in
 	return items
 }
 
+// buildSortItem offers ordering the list by the play history imported from the frontend.
+func buildSortItem(current cache.GameSort) gaba.ItemWithOptions {
+	options := []gaba.Option{
+		{DisplayName: i18n.Localize(&goi18n.Message{ID: "sort_name", Other: "Name"}, nil), Value: string(cache.SortByName)},
+		{DisplayName: i18n.Localize(&goi18n.Message{ID: "sort_play_count", Other: "Most Played"}, nil), Value: string(cache.SortByPlayCount)},
+		{DisplayName: i18n.Localize(&goi18n.Message{ID: "sort_last_played", Other: "Last Played"}, nil), Value: string(cache.SortByLastPlayed)},
+	}
+
+	selected := 0
+	for i, opt := range options {
+		if opt.Value == string(current) {
+			selected = i
+		}
+	}
+
+	return gaba.ItemWithOptions{
+		Item:           gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "filter_sort", Other: "Sort"}, nil)},
+		Options:        options,
+		SelectedOption: selected,
+	}
+}
+
 func buildFilterOptionsList(allLabel string, available []string, onUpdate func(any)) []gaba.Option {
 	options := make([]gaba.Option, 0, len(available)+1)
 	options = append(options, gaba.Option{DisplayName: allLabel, Value: "", OnUpdate: onUpdate})
@@ -217,6 +247,9 @@ func wireFilterCallbacks(cm *cache.Manager, platformID int, collection romm.Coll
 				}
 
 				catIdx := activeCats[j]
+				if catIdx == sortCatIdx {
+					continue
+				}
 				partialFilter := clearFilter(filter, catIdx)
 
 				if catIdx == platformCatIdx {
@@ -244,6 +277,9 @@ func wireFilterCallbacks(cm *cache.Manager, platformID int, collection romm.Coll
 	}
 
 	for i := range items {
+		if activeCats[i] == sortCatIdx {
+			continue
+		}
 		cb := makeCallback(i)
 		for j := range items[i].Options {
 			items[i].Options[j].OnUpdate = cb
@@ -347,6 +383,8 @@ func (s *GameFiltersScreen) applyFilters(items []gaba.ItemWithOptions) cache.Gam
 
 		text := item.Item.Text
 		switch text {
+		case i18n.Localize(&goi18n.Message{ID: "filter_sort", Other: "Sort"}, nil):
+			f.Sort = cache.GameSort(val)
 		case i18n.Localize(&goi18n.Message{ID: "filter_platform", Other: "Platform"}, nil):
 			f.PlatformSlugs = values
 		case i18n.Localize(&goi18n.Message{ID: "filter_genre", Other: "Genre"}, nil):
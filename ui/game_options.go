@@ -3,6 +3,7 @@ package ui
 import (
 	"errors"
 	"grout/internal"
+	"grout/offline"
 	"grout/romm"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
@@ -34,9 +35,10 @@ func (s *GameOptionsScreen) Draw(input GameOptionsInput) (GameOptionsOutput, err
 	config := input.Config
 	output := GameOptionsOutput{Action: GameOptionsActionBack, Config: config, Host: input.Host, Game: input.Game}
 
-	// Fetch save summary to determine available slots
+	// Fetch save summary to determine available slots; offline, only the saved slot
+	// preference is shown.
 	var slotNames []string
-	if input.Host.DeviceID != "" {
+	if input.Host.DeviceID != "" && !offline.IsOffline() {
 		client := romm.NewClientFromHost(input.Host, config.ApiTimeout.Duration())
 		gaba.ProcessMessage(
 			i18n.Localize(&goi18n.Message{ID: "synced_games_loading_detail", Other: "Loading save details..."}, nil),
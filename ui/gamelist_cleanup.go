@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"grout/cfw"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/internal/gamelist"
+	"grout/romm"
+	"path/filepath"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type GamelistCleanupInput struct {
+	Config internal.Config
+	Host   romm.Host
+}
+
+type GamelistCleanupOutput struct{}
+
+type GamelistCleanupScreen struct{}
+
+func NewGamelistCleanupScreen() *GamelistCleanupScreen {
+	return &GamelistCleanupScreen{}
+}
+
+// Execute cleans the gamelist in every mapped ROM folder: entries for missing ROMs and
+// art are removed, duplicates merged and paths made relative.
+func (s *GamelistCleanupScreen) Execute(input GamelistCleanupInput) GamelistCleanupOutput {
+	logger := gaba.GetLogger()
+
+	fileName, ok := cfw.GetCFW().GamelistFileName()
+	if !ok {
+		return GamelistCleanupOutput{}
+	}
+
+	platforms, err := loadMappedPlatforms(input.Config, input.Host)
+	if err != nil {
+		logger.Error("Failed to fetch platforms", "error", err)
+		gaba.ConfirmationMessage(
+			fmt.Sprintf("Failed to fetch platforms: %v", err),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return GamelistCleanupOutput{}
+	}
+
+	var total gamelist.CleanResult
+	gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "gamelist_cleanup_processing", Other: "Cleaning up gamelists..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			total = cleanGamelists(input.Config, platforms, fileName)
+			return nil, nil
+		},
+	)
+
+	if total.Changed() {
+		cfw.RefreshGamelists()
+	}
+
+	gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{
+			ID:    "gamelist_cleanup_complete",
+			Other: "Removed {{.Removed}} missing games, merged {{.Merged}} duplicates and fixed {{.Rewritten}} paths.",
+		}, map[string]interface{}{"Removed": total.Removed, "Merged": total.Merged, "Rewritten": total.Rewritten}),
+		ContinueFooter(),
+		gaba.MessageOptions{},
+	)
+	return GamelistCleanupOutput{}
+}
+
+// cleanGamelists cleans the gamelist of each platform and adds up what changed.
+func cleanGamelists(config internal.Config, platforms []romm.Platform, fileName gamelist.FileName) gamelist.CleanResult {
+	logger := gaba.GetLogger()
+	var total gamelist.CleanResult
+	seen := make(map[string]bool)
+
+	for _, p := range platforms {
+		romDir := config.GetPlatformRomDirectory(p)
+		path := filepath.Join(config.GetPlatformGamelistDirectory(p), string(fileName))
+		if seen[path] || !fileutil.FileExists(path) {
+			continue
+		}
+		seen[path] = true
+
+		result, err := gamelist.CleanFile(path, romDir)
+		if err != nil {
+			logger.Warn("Failed to clean gamelist", "path", path, "error", err)
+			continue
+		}
+		if result.Changed() {
+			logger.Info("Cleaned gamelist", "path", path, "removed", result.Removed, "merged", result.Merged,
+				"rewritten", result.Rewritten, "missing_art", result.MissingArt)
+		}
+		total.Removed += result.Removed
+		total.Merged += result.Merged
+		total.Rewritten += result.Rewritten
+		total.MissingArt += result.MissingArt
+	}
+	return total
+}
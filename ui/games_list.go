@@ -6,6 +6,7 @@ import (
 	"grout/cache"
 	"grout/internal"
 	"grout/internal/environment"
+	"grout/internal/romset"
 	"grout/internal/stringutil"
 	"grout/romm"
 	"slices"
@@ -17,6 +18,7 @@ import (
 	gabaconst "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
 	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/veandco/go-sdl2/sdl"
 	uatomic "go.uber.org/atomic"
 )
 
@@ -73,7 +75,24 @@ func isCollectionSet(c romm.Collection) bool {
 	return c.ID != 0 || c.VirtualID != ""
 }
 
+// Draw shows the game list. With Live Updates on, the list is rebuilt in place when a
+// live update changes one of its platforms, keeping the same game selected.
 func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
+	focusID := 0
+	for {
+		output, refocusID, err := s.draw(input, focusID)
+		if err != nil || refocusID == 0 {
+			return output, err
+		}
+		input.Games = output.AllGames
+		input.HasBIOS = output.HasBIOS
+		focusID = refocusID
+	}
+}
+
+// draw shows the list once. When a live update closed it, it returns the ID of the game
+// to keep selected once the list is rebuilt, and 0 otherwise.
+func (s *GameListScreen) draw(input GameListInput, focusID int) (GameListOutput, int, error) {
 	games := input.Games
 	hasBIOS := input.HasBIOS
 
@@ -81,14 +100,19 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 		loaded, err := s.loadGames(input)
 		if err != nil {
 			s.showErrorMessage(err)
-			return GameListOutput{Action: GameListActionBack}, nil
+			return GameListOutput{Action: GameListActionBack}, 0, nil
 		}
 		games = loaded.games
 		hasBIOS = loaded.hasBIOS
 
+		// Freshly loaded, so earlier live updates are already included.
+		cache.TakePlatformChanges(listPlatformIDs(input, games)...)
+
 		if input.Config.ShowBoxArt {
 			go cache.SyncArtworkInBackground(input.Config.ArtKind, input.Host, games)
 		}
+	} else if reloaded, ok := s.reloadChangedGames(input); ok {
+		games = reloaded
 	}
 
 	output := GameListOutput{
@@ -131,6 +155,12 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 		}
 	}
 
+	if input.Config.OneGameOneRom {
+		displayGames = romset.Dedupe(displayGames, input.Config.RomSetPreferences(), func(g romm.Rom) bool {
+			return g.IsDownloaded(*input.Config)
+		})
+	}
+
 	if input.Config.DownloadedGames == internal.DownloadedGamesModeFilter {
 		filteredGames := make([]romm.Rom, 0, len(displayGames))
 		for _, game := range displayGames {
@@ -222,6 +252,10 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 		displayGames = filterList(displayGames, input.SearchFilter)
 	}
 
+	if input.GameFilter.Sort != cache.SortByName {
+		cache.GetCacheManager().SortByPlayStats(displayGames, input.GameFilter.Sort)
+	}
+
 	if len(displayGames) == 0 {
 		if allGamesFilteredOut {
 			s.showFilteredOutMessage(displayName)
@@ -229,10 +263,10 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 			s.showEmptyMessage(displayName, input.SearchFilter)
 		}
 		if clearLastFilter(&output, input.LastApplied) {
-			return output, nil
+			return output, 0, nil
 		}
 		output.Action = GameListActionBack
-		return output, nil
+		return output, 0, nil
 	}
 
 	menuItems := make([]gaba.MenuItem, len(displayGames))
@@ -253,8 +287,10 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 	// Only offer Filters when there's actually something to filter on: any loaded game
 	// carries filterable metadata, or this is a unified collection (which offers a Platform
 	// picker regardless). Otherwise the Filters screen would open empty and instantly close,
-	// so we hide both the Y hint and the Y action rather than show a dead button.
-	showFilters := hasFilterableMetadata(games) || (isCollectionSet(input.Collection) && input.Platform.ID == 0)
+	// so we hide both the Y hint and the Y action rather than show a dead button. Play
+	// history adds a Sort option, so it counts too.
+	_, played := cache.GetCacheManager().PlayHistoryCounts()
+	showFilters := hasFilterableMetadata(games) || (isCollectionSet(input.Collection) && input.Platform.ID == 0) || played > 0
 
 	options := gaba.DefaultListOptions(title, menuItems)
 	options.UseSmallTitle = true
@@ -327,20 +363,53 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 
 	options.FooterHelpItems = footerItems
 
-	options.SelectedIndex = input.LastSelectedIndex
-	options.VisibleStartIndex = max(0, input.LastSelectedIndex-input.LastSelectedPosition)
+	// A live update may have shortened the list since the selection was made.
+	options.SelectedIndex = min(input.LastSelectedIndex, len(menuItems)-1)
+	if focusID != 0 {
+		if i := slices.IndexFunc(menuItems, func(item gaba.MenuItem) bool { return item.Metadata.(romm.Rom).ID == focusID }); i >= 0 {
+			options.SelectedIndex = i
+		}
+	}
+	options.VisibleStartIndex = max(0, options.SelectedIndex-input.LastSelectedPosition)
 	options.StatusBar = StatusBar()
 
+	if input.Config.ShowBoxArt {
+		recordShownArtwork(menuItems[options.SelectedIndex])
+	}
+	focused := menuItems[options.SelectedIndex].Metadata.(romm.Rom).ID
+	options.OnSelect = func(_ int, item *gaba.MenuItem) {
+		focused = item.Metadata.(romm.Rom).ID
+		if input.Config.ShowBoxArt {
+			recordShownArtwork(*item)
+		}
+	}
+
+	var stopWatching func() bool
+	if input.Config.LiveUpdates {
+		stopWatching = watchLiveUpdates(listPlatformIDs(input, games))
+	}
+
 	res, err := gaba.List(options)
+
+	if stopWatching != nil && stopWatching() {
+		// Drop the quit event if the user closed the list before it arrived.
+		sdl.FlushEvent(sdl.QUIT)
+		if err == nil && res.Action == gaba.ListActionSelected && len(res.Selected) == 0 {
+			return output, focused, nil
+		}
+	}
+	if err == nil && input.Config.ShowBoxArt && len(res.Selected) == 1 {
+		recordShownArtwork(res.Items[res.Selected[0]])
+	}
 	if err != nil {
 		if errors.Is(err, gaba.ErrCancelled) {
 			if clearLastFilter(&output, input.LastApplied) {
-				return output, nil
+				return output, 0, nil
 			}
 			output.Action = GameListActionBack
-			return output, nil
+			return output, 0, nil
 		}
-		return output, err
+		return output, 0, err
 	}
 
 	switch res.Action {
@@ -353,25 +422,99 @@ func (s *GameListScreen) Draw(input GameListInput) (GameListOutput, error) {
 		output.LastSelectedPosition = res.VisiblePosition
 		output.SelectedGames = selectedGames
 		output.Action = GameListActionSelected
-		return output, nil
+		return output, 0, nil
 
 	case gaba.ListActionTriggered:
 		output.Action = GameListActionSearch
-		return output, nil
+		return output, 0, nil
 
 	case gaba.ListActionSecondaryTriggered:
 		output.LastSelectedIndex = res.Selected[0]
 		output.LastSelectedPosition = res.VisiblePosition
 		output.Action = GameListActionFilters
-		return output, nil
+		return output, 0, nil
 
 	case gaba.ListActionTertiaryTriggered:
 		output.Action = GameListActionBIOS
-		return output, nil
+		return output, 0, nil
 	}
 
 	output.Action = GameListActionBack
-	return output, nil
+	return output, 0, nil
+}
+
+// watchLiveUpdates closes the open list when a live update changes one of the platforms,
+// so Draw can rebuild it. gaba's list has no way to swap its items while open, but it
+// returns on a quit event. The returned function stops watching and reports whether the
+// list was closed this way.
+func watchLiveUpdates(platformIDs []int) func() bool {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	closed := uatomic.NewBool(false)
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-cache.PlatformChangeSignal():
+				if !cache.HasPlatformChanges(platformIDs...) {
+					continue
+				}
+				if _, err := sdl.PushEvent(&sdl.QuitEvent{Type: sdl.QUIT}); err != nil {
+					gaba.GetLogger().Debug("Failed to refresh the game list after a live update", "error", err)
+					continue
+				}
+				closed.Store(true)
+				return
+			}
+		}
+	}()
+
+	return func() bool {
+		close(done)
+		<-stopped
+		return closed.Load()
+	}
+}
+
+// reloadChangedGames reloads the games from the cache when a live update refetched
+// any of their platforms since the list was built.
+func (s *GameListScreen) reloadChangedGames(input GameListInput) ([]romm.Rom, bool) {
+	cm := cache.GetCacheManager()
+	if cm == nil || !cache.TakePlatformChanges(listPlatformIDs(input, input.Games)...) {
+		return nil, false
+	}
+
+	var games []romm.Rom
+	var err error
+	if isCollectionSet(input.Collection) {
+		games, err = cm.GetCollectionGames(input.Collection)
+	} else {
+		games, err = cm.GetPlatformGames(input.Platform.ID)
+	}
+	if err != nil || len(games) == 0 {
+		return nil, false
+	}
+
+	gaba.GetLogger().Debug("Reloaded games after a live update", "platform", input.Platform.Name, "count", len(games))
+	return games, true
+}
+
+// listPlatformIDs returns the platforms whose games the list shows.
+func listPlatformIDs(input GameListInput, games []romm.Rom) []int {
+	if input.Platform.ID != 0 && !isCollectionSet(input.Collection) {
+		return []int{input.Platform.ID}
+	}
+
+	var ids []int
+	for _, game := range games {
+		if !slices.Contains(ids, game.PlatformID) {
+			ids = append(ids, game.PlatformID)
+		}
+	}
+	return ids
 }
 
 type loadGamesResult struct {
@@ -689,6 +832,17 @@ func hasFilterableMetadata(games []romm.Rom) bool {
 	return false
 }
 
+// recordShownArtwork marks the cover of a focused game as used, so the artwork budget
+// evicts what hasn't been looked at in a while. The list only draws the focused
+// game's cover, so that is the artwork it rendered; the details view records its own.
+func recordShownArtwork(item gaba.MenuItem) {
+	game, ok := item.Metadata.(romm.Rom)
+	if !ok || item.ImageFilename == "" {
+		return
+	}
+	go cache.GetCacheManager().RecordArtworkAccess([]romm.Rom{game})
+}
+
 func getLetter(item gaba.MenuItem) rune {
 	if game, ok := item.Metadata.(romm.Rom); ok {
 		name := strings.TrimSpace(game.Name)
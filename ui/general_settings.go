@@ -59,26 +59,48 @@ func (s *GeneralSettingsScreen) Draw(input GeneralSettingsInput) (GeneralSetting
 		return output, err
 	}
 
+	if result.Action == gaba.ListActionSelected {
+		selectedText := items[result.Selected].Item.Text
+
+		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_region_priority", Other: "Region Priority"}, nil) {
+			output.Action = GeneralSettingsActionRegionPriority
+			return output, nil
+		}
+
+		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_language_priority", Other: "Language Priority"}, nil) {
+			output.Action = GeneralSettingsActionLanguagePriority
+			return output, nil
+		}
+	}
+
 	output.Action = GeneralSettingsActionSaved
 	return output, nil
 }
 
 func (s *GeneralSettingsScreen) buildMenuItems(config *internal.Config) []gaba.ItemWithOptions {
 	c := cfw.GetCFW()
-	isMuOS := c == cfw.MuOS
+	showsPreviewArt := c.ShowsPreviewArt()
 	isESBasedOS := c.IsBasedOnEmulationStation()
 	showArtKind := atomic.Bool{}
 	showArtKind.Store(config.DownloadArt)
 	displayDownloadArtPreview := atomic.Bool{}
-	displayDownloadArtPreview.Store(showArtKind.Load() && isMuOS)
+	displayDownloadArtPreview.Store(showArtKind.Load() && showsPreviewArt)
 	displayEmulationStationOptions := atomic.Bool{}
 	displayEmulationStationOptions.Store(showArtKind.Load() && isESBasedOS)
 
 	downloadArtUpdateFunc := func(val interface{}) {
 		showArtKind.Store(val.(bool))
-		displayDownloadArtPreview.Store(showArtKind.Load() && isMuOS)
+		displayDownloadArtPreview.Store(showArtKind.Load() && showsPreviewArt)
 		displayEmulationStationOptions.Store(showArtKind.Load() && isESBasedOS)
 	}
+	_, hasRetroArch := c.RetroArchDirectories()
+	displayRetroArchOptions := atomic.Bool{}
+	displayRetroArchOptions.Store(hasRetroArch)
+	showRomPriority := atomic.Bool{}
+	showRomPriority.Store(config.OneGameOneRom)
+	oneGameOneRomUpdateFunc := func(val interface{}) {
+		showRomPriority.Store(val.(bool))
+	}
 
 	return []gaba.ItemWithOptions{
 		{
@@ -98,6 +120,24 @@ func (s *GeneralSettingsScreen) buildMenuItems(config *internal.Config) []gaba.I
 			},
 			SelectedOption: downloadedGamesActionToIndex(config.DownloadedGames),
 		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_one_game_one_rom", Other: "One Game One ROM"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_false", Other: "False"}, nil), Value: false, OnUpdate: oneGameOneRomUpdateFunc},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_true", Other: "True"}, nil), Value: true, OnUpdate: oneGameOneRomUpdateFunc},
+			},
+			SelectedOption: boolToIndex(config.OneGameOneRom),
+		},
+		{
+			Item:        gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_region_priority", Other: "Region Priority"}, nil)},
+			Options:     []gaba.Option{{Type: gaba.OptionTypeClickable}},
+			VisibleWhen: &showRomPriority,
+		},
+		{
+			Item:        gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_language_priority", Other: "Language Priority"}, nil)},
+			Options:     []gaba.Option{{Type: gaba.OptionTypeClickable}},
+			VisibleWhen: &showRomPriority,
+		},
 		{
 			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_compressed_downloads", Other: "Archived Downloads"}, nil)},
 			Options: []gaba.Option{
@@ -121,6 +161,7 @@ func (s *GeneralSettingsScreen) buildMenuItems(config *internal.Config) []gaba.I
 				{DisplayName: i18n.Localize(&goi18n.Message{ID: "settings_download_art_kind_box2d", Other: "Box2D"}, nil), Value: artutil.ArtKindBox2D},
 				{DisplayName: i18n.Localize(&goi18n.Message{ID: "settings_download_art_kind_box3d", Other: "Box3D"}, nil), Value: artutil.ArtKindBox3D},
 				{DisplayName: i18n.Localize(&goi18n.Message{ID: "settings_download_art_kind_miximage", Other: "MixImage"}, nil), Value: artutil.ArtKindMixImage},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "settings_download_art_kind_composite", Other: "Composite"}, nil), Value: artutil.ArtKindComposite},
 			},
 			SelectedOption: boxArtToIndex(config.ArtKind),
 			VisibleWhen:    &showArtKind,
@@ -209,6 +250,15 @@ func (s *GeneralSettingsScreen) buildMenuItems(config *internal.Config) []gaba.I
 			SelectedOption: boolToIndex(!config.AdditionalDownloads.Fanart),
 			VisibleWhen:    &displayEmulationStationOptions,
 		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_retroarch_playlists", Other: "RetroArch Playlists"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_false", Other: "False"}, nil), Value: false},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_true", Other: "True"}, nil), Value: true},
+			},
+			SelectedOption: boolToIndex(config.RetroArchPlaylists),
+			VisibleWhen:    &displayRetroArchOptions,
+		},
 		{
 			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_language", Other: "Language"}, nil)},
 			Options: []gaba.Option{
@@ -249,6 +299,11 @@ func (s *GeneralSettingsScreen) applySettings(config *internal.Config, items []g
 				config.DownloadedGames = val
 			}
 
+		case i18n.Localize(&goi18n.Message{ID: "settings_one_game_one_rom", Other: "One Game One ROM"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				config.OneGameOneRom = val
+			}
+
 		case i18n.Localize(&goi18n.Message{ID: "settings_download_art", Other: "Download Art"}, nil):
 			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
 				config.DownloadArt = val
@@ -304,6 +359,11 @@ func (s *GeneralSettingsScreen) applySettings(config *internal.Config, items []g
 				config.AdditionalDownloads.Fanart = val
 			}
 
+		case i18n.Localize(&goi18n.Message{ID: "settings_retroarch_playlists", Other: "RetroArch Playlists"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				config.RetroArchPlaylists = val
+			}
+
 		case i18n.Localize(&goi18n.Message{ID: "settings_language", Other: "Language"}, nil):
 			if val, ok := item.Options[item.SelectedOption].Value.(string); ok {
 				config.Language = val
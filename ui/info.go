@@ -2,8 +2,11 @@ package ui
 
 import (
 	"errors"
+	"fmt"
+	"grout/cache"
 	"grout/cfw"
 	"grout/internal/imageutil"
+	"grout/internal/stringutil"
 	"grout/romm"
 	"grout/version"
 	"time"
@@ -123,6 +126,13 @@ func (s *InfoScreen) buildSections(input InfoInput) []gaba.Section {
 
 	sections = append(sections, gaba.NewInfoSection("RomM", metadata))
 
+	if cm := cache.GetCacheManager(); cm != nil {
+		sections = append(sections, gaba.NewInfoSection(
+			i18n.Localize(&goi18n.Message{ID: "info_cache", Other: "Cache"}, nil),
+			s.cacheMetadata(cm),
+		))
+	}
+
 	qrText := "https://github.com/rommapp/grout"
 	qrcode, err := imageutil.CreateTempQRCode(qrText, 256)
 	if err == nil {
@@ -139,3 +149,37 @@ func (s *InfoScreen) buildSections(input InfoInput) []gaba.Section {
 
 	return sections
 }
+
+func (s *InfoScreen) cacheMetadata(cm *cache.Manager) []gaba.MetadataItem {
+	art := cm.GetArtworkStats()
+	metaHits, metaMisses, _ := cm.GetMetadataStats()
+
+	budget := i18n.Localize(&goi18n.Message{ID: "option_unlimited", Other: "Unlimited"}, nil)
+	if art.Budget > 0 {
+		budget = stringutil.FormatBytes(art.Budget)
+	}
+
+	return []gaba.MetadataItem{
+		{
+			Label: i18n.Localize(&goi18n.Message{ID: "info_artwork_cache_size", Other: "Artwork"}, nil),
+			Value: fmt.Sprintf("%s / %s", stringutil.FormatBytes(art.Size), budget),
+		},
+		{
+			Label: i18n.Localize(&goi18n.Message{ID: "info_artwork_cache_hits", Other: "Artwork Hits"}, nil),
+			Value: hitRate(art.Hits, art.Misses),
+		},
+		{
+			Label: i18n.Localize(&goi18n.Message{ID: "info_metadata_cache_hits", Other: "Metadata Hits"}, nil),
+			Value: hitRate(metaHits, metaMisses),
+		},
+	}
+}
+
+// hitRate formats cache hits and misses as "hits/total (percent)".
+func hitRate(hits, misses int64) string {
+	total := hits + misses
+	if total == 0 {
+		return "0/0"
+	}
+	return fmt.Sprintf("%d/%d (%d%%)", hits, total, hits*100/total)
+}
@@ -366,6 +366,43 @@ func LoginFlow(existingHost romm.Host) (*internal.Config, error) {
 	}
 }
 
+// ProvisionedLogin logs in with the details from a provisioning file: a pairing code is
+// exchanged without any input, otherwise device pairing starts straight away under
+// deviceName. An error means the caller should fall back to LoginFlow.
+func ProvisionedLogin(host romm.Host, pairingCode, deviceName string) (*internal.Config, error) {
+	connResult := validateConnection(host)
+	if !connResult.Result.Success {
+		return nil, fmt.Errorf("provisioned server unreachable: %s", connResult.Result.ErrorType)
+	}
+
+	sel := authSelection{Host: host, Mode: authModePairingCode, DeviceName: deviceName}
+	sel.Host.Token = pairingCode
+	if pairingCode == "" {
+		if !connResult.SupportsDeviceAuth {
+			return nil, errors.New("provisioning without a pairing_code needs RomM 5.0 or later")
+		}
+		sel.Mode = authModeDevicePairing
+	}
+
+	loginOutput := attemptLogin(sel)
+	if !loginOutput.Result.Success {
+		if loginOutput.Result.ErrorMsg != nil {
+			gabagool.ConfirmationMessage(
+				i18n.Localize(loginOutput.Result.ErrorMsg, nil),
+				ContinueFooter(),
+				gabagool.MessageOptions{},
+			)
+		}
+		return nil, fmt.Errorf("provisioned login failed: %s", loginOutput.Result.ErrorType)
+	}
+
+	config := &internal.Config{
+		Hosts: []romm.Host{loginOutput.Host},
+	}
+	_ = config.LoadPlatformsBinding(loginOutput.Host)
+	return config, nil
+}
+
 // connectionValidation reports server reachability plus whether the server is
 // new enough (RomM 5.0+) to offer device-auth pairing.
 type connectionValidation struct {
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"errors"
+	"grout/cache"
 	"grout/internal"
 	"grout/romm"
 
@@ -26,6 +27,7 @@ type PlatformSelectionOutput struct {
 	LastSelectedIndex    int
 	LastSelectedPosition int
 	ReorderedPlatforms   []romm.Platform
+	SelectedCollection   romm.Collection
 }
 
 type PlatformSelectionScreen struct{}
@@ -58,6 +60,23 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (PlatformSe
 		})
 	}
 
+	favorites, played := cache.GetCacheManager().PlayHistoryCounts()
+	if favorites > 0 {
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:           i18n.Localize(&goi18n.Message{ID: "platform_selection_favorites", Other: "Favorites"}, nil),
+			Metadata:       romm.Collection{VirtualID: cache.FavoritesCollectionID},
+			NotReorderable: true,
+		})
+	}
+	if played > 0 {
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:           i18n.Localize(&goi18n.Message{ID: "platform_selection_recently_played", Other: "Recently Played"}, nil),
+			Metadata:       romm.Collection{VirtualID: cache.RecentlyPlayedCollectionID},
+			NotReorderable: true,
+		})
+	}
+	startIndex := len(menuItems)
+
 	for _, platform := range platforms {
 		menuItems = append(menuItems, gaba.MenuItem{
 			Text:     platform.Name,
@@ -115,10 +134,6 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (PlatformSe
 	// Check for reordering before handling errors
 	// This ensures we save the order even when user presses B (cancel)
 	platformsReordered := false
-	startIndex := 0
-	if input.ShowCollections {
-		startIndex = 1
-	}
 
 	if sel != nil && len(sel.Items) > 0 {
 		if len(sel.Items)-startIndex == len(platforms) {
@@ -152,12 +167,19 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (PlatformSe
 
 	switch sel.Action {
 	case gaba.ListActionSelected:
-		platform := sel.Items[sel.Selected[0]].Metadata.(romm.Platform)
-
-		output.SelectedPlatform = platform
 		output.LastSelectedIndex = sel.Selected[0]
 		output.LastSelectedPosition = sel.VisiblePosition
 
+		if collection, ok := sel.Items[sel.Selected[0]].Metadata.(romm.Collection); ok {
+			collection.Name = sel.Items[sel.Selected[0]].Text
+			output.SelectedCollection = collection
+			output.Action = PlatformSelectionActionPlayHistory
+			return output, nil
+		}
+
+		platform := sel.Items[sel.Selected[0]].Metadata.(romm.Platform)
+		output.SelectedPlatform = platform
+
 		if platform.FSSlug == "collections" {
 			output.Action = PlatformSelectionActionCollections
 			return output, nil
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"grout/bios"
+	"grout/cfw"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/internal/gamelist"
+	"grout/internal/retroarch"
+	"grout/internal/stringutil"
+	"grout/romm"
+	"path/filepath"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// updateRetroArchPlaylists adds downloaded games to RetroArch's per-system playlists,
+// copies their art to where RetroArch looks for thumbnails, and drops playlist entries
+// in Grout's ROM directories whose games are no longer on the device.
+func updateRetroArchPlaylists(config internal.Config, entries []gamelist.RomGameEntry) {
+	logger := gaba.GetLogger()
+
+	dirs, ok := cfw.GetCFW().RetroArchDirectories()
+	if !ok || !config.RetroArchPlaylists {
+		return
+	}
+
+	playlists := make(map[string]*retroarch.Playlist)
+	changed := make(map[string]bool)
+	load := func(system string) *retroarch.Playlist {
+		if p, exists := playlists[system]; exists {
+			return p
+		}
+		p, err := retroarch.Load(filepath.Join(dirs.Playlists, retroarch.PlaylistFileName(system)))
+		if err != nil {
+			logger.Warn("Failed to load RetroArch playlist", "system", system, "error", err)
+		}
+		playlists[system] = p
+		return p
+	}
+
+	for _, entry := range entries {
+		if entry.Game == nil || entry.Platform == nil || entry.GamePath == "" {
+			continue
+		}
+		system, ok := retroarch.SystemName(entry.Platform.FSSlug)
+		if !ok {
+			continue
+		}
+		playlist := load(system)
+		if playlist == nil {
+			continue
+		}
+
+		label := stringutil.PrepareRomName(entry.Game.Name, entry.Game.Regions)
+		corePath, coreName := findRetroArchCore(dirs.Cores, entry.Platform.FSSlug)
+		playlist.Upsert(retroarch.Item{
+			Path:     entry.GamePath,
+			Label:    label,
+			CorePath: corePath,
+			CoreName: coreName,
+			CRC32:    retroarch.FormatCRC32(romCRC(entry)),
+			DBName:   retroarch.PlaylistFileName(system),
+		})
+		changed[system] = true
+
+		copyRetroArchThumbnail(entry.ArtLocation.ImagePath, retroarch.ThumbnailPath(dirs.Thumbnails, system, retroarch.ThumbnailBoxart, label))
+		copyRetroArchThumbnail(entry.ArtLocation.ThumbnailPath, retroarch.ThumbnailPath(dirs.Thumbnails, system, retroarch.ThumbnailSnap, label))
+	}
+
+	var romDirs []string
+	for slug := range config.DirectoryMappings {
+		romDirs = append(romDirs, config.GetPlatformRomDirectory(romm.Platform{FSSlug: slug}))
+		if system, ok := retroarch.SystemName(slug); ok {
+			load(system)
+		}
+	}
+
+	for system, playlist := range playlists {
+		if playlist == nil {
+			continue
+		}
+		if removed := playlist.Prune(romDirs, fileutil.FileExists); removed > 0 {
+			logger.Debug("Removed missing games from RetroArch playlist", "system", system, "count", removed)
+			changed[system] = true
+		}
+		if !changed[system] {
+			continue
+		}
+		if err := playlist.Save(filepath.Join(dirs.Playlists, retroarch.PlaylistFileName(system))); err != nil {
+			logger.Warn("Failed to save RetroArch playlist", "system", system, "error", err)
+		}
+	}
+}
+
+// findRetroArchCore returns the first installed core for a platform, or lets RetroArch
+// pick one when none of the known cores are installed.
+func findRetroArchCore(coreDir, platformFSSlug string) (string, string) {
+	for _, core := range bios.PlatformToLibretroCores[platformFSSlug] {
+		corePath := filepath.Join(coreDir, core+".so")
+		if fileutil.FileExists(corePath) {
+			return corePath, strings.TrimSuffix(core, "_libretro")
+		}
+	}
+	return retroarch.CoreDetect, retroarch.CoreDetect
+}
+
+func romCRC(entry gamelist.RomGameEntry) string {
+	if files := entry.Game.GameFiles(); len(files) == 1 && files[0].CrcHash != "" {
+		return files[0].CrcHash
+	}
+	return entry.Game.CrcHash
+}
+
+func copyRetroArchThumbnail(src, dest string) {
+	if src == "" || !fileutil.FileExists(src) {
+		return
+	}
+	if err := fileutil.CopyFile(src, dest); err != nil {
+		gaba.GetLogger().Debug("Failed to copy RetroArch thumbnail", "src", src, "error", err)
+	}
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"errors"
+	"grout/cache"
+	"grout/internal"
+	"slices"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type RomPriorityKind int
+
+const (
+	RomPriorityRegions RomPriorityKind = iota
+	RomPriorityLanguages
+)
+
+type RomPriorityInput struct {
+	Config *internal.Config
+	Kind   RomPriorityKind
+}
+
+type RomPriorityOutput struct {
+	Action RomPriorityAction
+	Config *internal.Config
+}
+
+type RomPriorityScreen struct{}
+
+func NewRomPriorityScreen() *RomPriorityScreen {
+	return &RomPriorityScreen{}
+}
+
+func (s *RomPriorityScreen) Draw(input RomPriorityInput) (RomPriorityOutput, error) {
+	config := input.Config
+	output := RomPriorityOutput{Action: RomPriorityActionBack, Config: config}
+
+	values := s.buildValues(input)
+
+	menuItems := make([]gaba.MenuItem, len(values))
+	for i, value := range values {
+		menuItems[i] = gaba.MenuItem{
+			Text:     value,
+			Metadata: value,
+		}
+	}
+
+	title := i18n.Localize(&goi18n.Message{ID: "settings_region_priority", Other: "Region Priority"}, nil)
+	if input.Kind == RomPriorityLanguages {
+		title = i18n.Localize(&goi18n.Message{ID: "settings_language_priority", Other: "Language Priority"}, nil)
+	}
+
+	options := gaba.DefaultListOptions(title, menuItems)
+	options.UseSmallTitle = true
+	options.ReorderButton = buttons.VirtualButtonSelect
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		FooterBack(),
+		{ButtonName: buttons.Select, HelpText: i18n.Localize(&goi18n.Message{ID: "button_reorder", Other: "Reorder"}, nil)},
+	}
+	options.StatusBar = StatusBar()
+
+	sel, err := gaba.List(options)
+
+	// Like the platform list, keep the new order even when leaving with B
+	if sel != nil && len(sel.Items) == len(values) {
+		reordered := make([]string, len(sel.Items))
+		for i, item := range sel.Items {
+			reordered[i] = item.Metadata.(string)
+		}
+
+		if !slices.Equal(reordered, values) {
+			if input.Kind == RomPriorityLanguages {
+				config.LanguagePriority = reordered
+			} else {
+				config.RegionPriority = reordered
+			}
+
+			if err := internal.SaveConfig(config); err != nil {
+				gaba.GetLogger().Error("Error saving ROM priority", "error", err)
+				return output, err
+			}
+			output.Action = RomPriorityActionSaved
+		}
+	}
+
+	if err != nil && !errors.Is(err, gaba.ErrCancelled) {
+		return output, err
+	}
+
+	return output, nil
+}
+
+// buildValues returns the configured priority followed by every other region or
+// language known to the cache, so newly seen values can be moved up the list.
+func (s *RomPriorityScreen) buildValues(input RomPriorityInput) []string {
+	prefs := input.Config.RomSetPreferences()
+	values := slices.Clone(prefs.Regions)
+	if input.Kind == RomPriorityLanguages {
+		values = slices.Clone(prefs.Languages)
+	}
+
+	if cm := cache.GetCacheManager(); cm != nil {
+		var known []string
+		var err error
+		if input.Kind == RomPriorityLanguages {
+			known, err = cm.GetDistinctLanguages(0)
+		} else {
+			known, err = cm.GetDistinctRegions(0)
+		}
+		if err != nil {
+			gaba.GetLogger().Debug("Unable to load known values for ROM priority", "error", err)
+		}
+
+		for _, value := range known {
+			if !slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) }) {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
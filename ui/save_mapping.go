@@ -5,9 +5,7 @@ import (
 	"grout/cache"
 	"grout/cfw"
 	"grout/internal"
-	"path/filepath"
 	"sort"
-	"strings"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
@@ -79,14 +77,7 @@ func (s *SaveMappingScreen) Draw(input SaveMappingInput) (SaveMappingOutput, err
 		selectedIndex := 0
 
 		for i, dir := range emulatorDirs {
-			displayName := dir
-
-			if currentCFW == cfw.MuOS {
-				displayName = strings.ReplaceAll(displayName, "file/", "")
-				displayName = strings.ReplaceAll(displayName, "/backup", "")
-			}
-
-			displayName = filepath.Base(displayName)
+			displayName := currentCFW.SaveDirectoryLabel(dir)
 			options = append(options, gaba.Option{
 				DisplayName: displayName,
 				Value:       dir,
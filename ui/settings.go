@@ -263,6 +263,8 @@ func boxArtToIndex(boxArt artutil.ArtKind) int {
 		return 2
 	case artutil.ArtKindMixImage:
 		return 3
+	case artutil.ArtKindComposite:
+		return 4
 	default:
 		return 0
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"grout/cache"
 	"grout/cfw"
+	"grout/cfw/profile"
 	"grout/internal"
 	"grout/internal/fileutil"
 	"grout/internal/stringutil"
@@ -531,10 +532,7 @@ func (s *PlatformMappingScreen) buildPlatformOptions(
 		}
 
 		if !dirExists {
-			displayName := cfwDir
-			if input.CFW == cfw.NextUI || input.CFW == cfw.MinUI {
-				displayName = stringutil.ParseTag(cfwDir)
-			}
+			displayName := input.CFW.RomFolderBase(cfwDir, stringutil.ParseTag)
 			options = append(options, gaba.Option{
 				DisplayName: i18n.Localize(&goi18n.Message{ID: "platform_mapping_create", Other: "Create '{{.Name}}'"}, map[string]interface{}{"Name": displayName}),
 				Value:       cfwDir,
@@ -552,10 +550,7 @@ func (s *PlatformMappingScreen) buildPlatformOptions(
 		dirName := romDir.Name()
 
 		if s.isValidDirectoryForPlatform(dirName, input.CFW, cfwDirectories) {
-			displayName := dirName
-			if input.CFW == cfw.NextUI || input.CFW == cfw.MinUI {
-				displayName = stringutil.ParseTag(dirName)
-			}
+			displayName := input.CFW.RomFolderBase(dirName, stringutil.ParseTag)
 
 			options = append(options, gaba.Option{
 				DisplayName: i18n.Localize(&goi18n.Message{ID: "platform_mapping_path_prefix", Other: "/{{.Name}}"}, map[string]interface{}{"Name": displayName}),
@@ -615,12 +610,10 @@ func (s *PlatformMappingScreen) directoryMatchesPlatform(
 	cfwFSSlug := cfw.RomMFSSlugToCFW(platform.FSSlug)
 	romFolderBase := cfw.RomFolderBase(dirName, stringutil.ParseTag)
 
-	switch c {
-	case cfw.NextUI, cfw.MinUI:
+	if _, tagged := c.Profile().(profile.FolderMatcher); tagged {
 		return stringutil.ParseTag(cfwFSSlug) == romFolderBase
-	default:
-		return cfwFSSlug == romFolderBase
 	}
+	return cfwFSSlug == romFolderBase
 }
 
 func (s *PlatformMappingScreen) getCFWDirectoriesForPlatform(fsSlug string, c cfw.CFW, platformsBinding map[string]string) []string {
@@ -645,10 +638,7 @@ func (s *PlatformMappingScreen) getCFWDirectoriesForPlatform(fsSlug string, c cf
 }
 
 func (s *PlatformMappingScreen) directoriesMatch(dir1, dir2 string, c cfw.CFW) bool {
-	if c == cfw.NextUI || c == cfw.MinUI {
-		return stringutil.ParseTag(dir1) == stringutil.ParseTag(dir2)
-	}
-	return dir1 == dir2
+	return c.RomFolderBase(dir1, stringutil.ParseTag) == c.RomFolderBase(dir2, stringutil.ParseTag)
 }
 
 func (s *PlatformMappingScreen) isValidDirectoryForPlatform(dirName string, c cfw.CFW, cfwDirectories []string) bool {
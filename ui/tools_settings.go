@@ -2,8 +2,10 @@ package ui
 
 import (
 	"errors"
+	"grout/cfw"
 	"grout/internal"
 	"grout/romm"
+	"sync/atomic"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
@@ -73,6 +75,21 @@ func (s *ToolsSettingsScreen) Draw(input ToolsSettingsInput) (ToolsSettingsOutpu
 			output.Action = ToolsSettingsActionSyncLocalArtwork
 			return output, nil
 		}
+
+		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_optimize_art", Other: "Optimize Art"}, nil) {
+			output.Action = ToolsSettingsActionOptimizeArt
+			return output, nil
+		}
+
+		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_clean_gamelists", Other: "Clean Up Gamelists"}, nil) {
+			output.Action = ToolsSettingsActionCleanGamelists
+			return output, nil
+		}
+
+		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_fetch_all_bios", Other: "Fetch Missing BIOS"}, nil) {
+			output.Action = ToolsSettingsActionFetchAllBIOS
+			return output, nil
+		}
 	}
 
 	s.applySettings(config, result.Items)
@@ -88,11 +105,28 @@ func (s *ToolsSettingsScreen) Draw(input ToolsSettingsInput) (ToolsSettingsOutpu
 }
 
 func (s *ToolsSettingsScreen) buildMenuItems(config *internal.Config) []gaba.ItemWithOptions {
+	_, hasGamelists := cfw.GetCFW().GamelistFileName()
+	showGamelistCleanup := atomic.Bool{}
+	showGamelistCleanup.Store(hasGamelists)
+
 	return []gaba.ItemWithOptions{
 		{
 			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_sync_local_artwork", Other: "Download Missing Art"}, nil)},
 			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
 		},
+		{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_optimize_art", Other: "Optimize Art"}, nil)},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		},
+		{
+			Item:        gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_clean_gamelists", Other: "Clean Up Gamelists"}, nil)},
+			Options:     []gaba.Option{{Type: gaba.OptionTypeClickable}},
+			VisibleWhen: &showGamelistCleanup,
+		},
+		{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_fetch_all_bios", Other: "Fetch Missing BIOS"}, nil)},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		},
 		{
 			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_kid_mode", Other: "Kid Mode"}, nil)},
 			Options: []gaba.Option{